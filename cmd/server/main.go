@@ -1,13 +1,22 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
 	"regexp"
+	"task-manager/internal/auth"
+	"task-manager/internal/events"
+	"task-manager/internal/jobs"
 	"task-manager/internal/model"
+	"task-manager/internal/notify"
+	"task-manager/internal/oidc"
 	"task-manager/internal/router"
 	"task-manager/internal/service"
+	"task-manager/internal/workflow"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
@@ -22,6 +31,16 @@ var (
 )
 
 func main() {
+	// Load the token signing keys
+	keys, err := auth.LoadKeySetFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if active, _ := keys.Active(); active != nil && active.ID == auth.DevKeyID {
+		log.Println("WARNING: JWT_INSECURE_DEV is set, tokens are signed with a development key")
+	}
+	auth.SetKeySet(keys)
+
 	// db, err := gorm.Open("postgres", "postgres:password@/task_manager?charset=utf8&parseTime=True&loc=Local&sslmode=disable")
 	db, err := gorm.Open("postgres", "user=postgres dbname=task_manager sslmode=disable host=localhost port=5432 password=password")
 	if err != nil {
@@ -29,10 +48,69 @@ func main() {
 	}
 	defer db.Close()
 
-	db.AutoMigrate(&model.Task{})
+	db.AutoMigrate(&model.Task{}, &model.TaskGrant{}, &model.User{}, &model.RefreshToken{}, &model.RevokedToken{}, &model.PersonalAccessToken{},
+		&model.UserIdentity{}, &model.RecoveryCode{}, &model.Workspace{}, &model.WorkspaceMember{},
+		&model.TaskAssignee{}, &model.TaskAssignmentLog{}, &model.Label{}, &model.TaskLabel{}, &model.TaskDependency{},
+		&model.Project{}, &model.TaskRevision{}, &model.Recurrence{},
+		&model.Reminder{}, &model.Notification{}, &model.WorkLog{},
+		&model.Comment{}, &model.CommentRevision{}, &model.CommentMention{})
+
+	// Status transitions of the tasks
+	taskWorkflow, err := workflow.FromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	// taskService := &service.TaskService{DB: db}
-	taskService := service.NewTaskService(db)
+	taskService := service.NewTaskService(db, taskWorkflow)
+	userService := service.NewUserService(db)
+	tokenService := service.NewTokenService(db)
+	workspaceService := service.NewWorkspaceService(db)
+	mfaService := service.NewMFAService(db)
+	labelService := service.NewLabelService(db)
+	projectService := service.NewProjectService(db, taskWorkflow)
+	recurrenceService := service.NewRecurrenceService(db, taskWorkflow)
+	notificationService := service.NewNotificationService(db)
+	timeService := service.NewTimeService(db)
+	commentService := service.NewCommentService(db)
+
+	// Reminders are delivered to the in-app inbox, and by email and
+	// webhook when configured
+	notifiers := notify.Notifiers{notify.ChannelInbox: notificationService}
+	if config, ok := notify.SMTPConfigFromEnv(); ok {
+		notifiers[notify.ChannelEmail] = notify.NewSMTPNotifier(config)
+	}
+	if config, ok := notify.WebhookConfigFromEnv(); ok {
+		notifiers[notify.ChannelWebhook] = notify.NewWebhookNotifier(config)
+	}
+	reminderService := service.NewReminderService(db, notifiers.Channels())
+
+	// Mark the overdue tasks in the background
+	bus := events.NewBus()
+	bus.Subscribe(events.TaskOverdueEvent, func(ctx context.Context, e events.Event) {
+		task := e.(events.TaskOverdue).Task
+		log.Printf("task %s is overdue since %s", task.ID, task.DueAt.Format(time.RFC3339))
+	})
+	interval, _ := time.ParseDuration(os.Getenv("OVERDUE_CHECK_INTERVAL"))
+	go jobs.NewOverdueJob(taskService, bus, interval).Run(context.Background())
+
+	// Purge the trash in the background
+	retention, _ := time.ParseDuration(os.Getenv("TRASH_RETENTION"))
+	go jobs.NewPurgeJob(taskService, retention, 0).Run(context.Background())
+
+	// Create the tasks of the recurring tasks in the background
+	recurrenceInterval, _ := time.ParseDuration(os.Getenv("RECURRENCE_CHECK_INTERVAL"))
+	go jobs.NewRecurrenceJob(recurrenceService, recurrenceInterval).Run(context.Background())
+
+	// Send the due reminders in the background
+	reminderInterval, _ := time.ParseDuration(os.Getenv("REMINDER_CHECK_INTERVAL"))
+	go jobs.NewReminderJob(reminderService, notifiers, reminderInterval).Run(context.Background())
+
+	// Login through an identity provider is optional
+	var oidcProvider *oidc.Provider
+	if config, ok := oidc.ConfigFromEnv(); ok {
+		oidcProvider = oidc.NewProvider(config)
+	}
 
 	r := gin.Default()
 
@@ -42,7 +120,21 @@ func main() {
 		v.RegisterValidation("phone", PhoneValidator) // for Phone regex validation
 	}
 
-	router.SetupRouter(r, taskService)
+	router.SetupRouter(r, router.Services{
+		TaskService:         taskService,
+		UserService:         userService,
+		TokenService:        tokenService,
+		WorkspaceService:    workspaceService,
+		MFAService:          mfaService,
+		LabelService:        labelService,
+		ProjectService:      projectService,
+		RecurrenceService:   recurrenceService,
+		ReminderService:     reminderService,
+		NotificationService: notificationService,
+		TimeService:         timeService,
+		CommentService:      commentService,
+		OIDCProvider:        oidcProvider,
+	})
 	fmt.Println("test push trigger")
 	log.Fatal(http.ListenAndServe(":8080", r))
 }
@@ -2,28 +2,143 @@ package auth
 
 import (
 	"errors"
+	"task-manager/internal/model"
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
+	"github.com/gofrs/uuid"
 )
 
-var mySecretKey = []byte("secret")
+var (
+	// Issuer is the value placed in, and required of, the "iss" claim
+	Issuer = "task-manager"
 
-func ParseToken(tokenString string) (*jwt.Token, error) {
-    token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-        if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-            return nil, errors.New("unexpected signing method")
-        }
-        return mySecretKey, nil
-    })
-    return token, err
+	// Audience is the value placed in, and required of, the "aud" claim
+	Audience = "task-manager-api"
+
+	// AccessTokenTTL is the lifetime of an issued access token
+	AccessTokenTTL = 1 * time.Hour
+
+	// RefreshTokenTTL is the lifetime of an issued refresh token
+	RefreshTokenTTL = 30 * 24 * time.Hour
+)
+
+var (
+	ErrTokenMalformed        = errors.New("token is malformed")
+	ErrTokenSignatureInvalid = errors.New("token signature is invalid")
+	ErrTokenExpired          = errors.New("token is expired")
+	ErrTokenNotYetValid      = errors.New("token is not valid yet")
+	ErrTokenIssuedInFuture   = errors.New("token used before issued")
+	ErrTokenInvalidIssuer    = errors.New("token issuer is invalid")
+	ErrTokenInvalidAudience  = errors.New("token audience is invalid")
+	ErrTokenInvalid          = errors.New("token is invalid")
+)
+
+// Claims are the JWT claims carried by an access token
+type Claims struct {
+	Username string   `json:"username"`
+	Roles    []string `json:"roles,omitempty"`
+	MFA      bool     `json:"mfa,omitempty"`
+	jwt.StandardClaims
+}
+
+// Valid verifies the time based claims, the issuer and the audience.
+// Unlike jwt.StandardClaims the "exp" claim is mandatory.
+func (c *Claims) Valid() error {
+	now := jwt.TimeFunc().Unix()
+	vErr := &jwt.ValidationError{}
+
+	if c.ExpiresAt == 0 || !c.VerifyExpiresAt(now, true) {
+		vErr.Errors |= jwt.ValidationErrorExpired
+	}
+	if !c.VerifyNotBefore(now, false) {
+		vErr.Errors |= jwt.ValidationErrorNotValidYet
+	}
+	if !c.VerifyIssuedAt(now, false) {
+		vErr.Errors |= jwt.ValidationErrorIssuedAt
+	}
+	if !c.VerifyIssuer(Issuer, true) {
+		vErr.Errors |= jwt.ValidationErrorIssuer
+	}
+	if !c.VerifyAudience(Audience, true) {
+		vErr.Errors |= jwt.ValidationErrorAudience
+	}
+
+	if vErr.Errors == 0 {
+		return nil
+	}
+	return vErr
+}
+
+// ParseToken verifies the signature and the claims of the token, and
+// returns the claims on success. The returned error is one of the
+// ErrToken* values so that callers can tell the failures apart.
+func ParseToken(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	ks := CurrentKeySet()
+	parser := &jwt.Parser{ValidMethods: ks.Algorithms()}
+	_, err := parser.ParseWithClaims(tokenString, claims, ks.Keyfunc)
+	if err != nil {
+		err = translateError(err)
+		if errors.Is(err, ErrTokenInvalidAudience) && claims.Audience == MFAChallengeAudience {
+			return nil, ErrTokenMFAChallenge
+		}
+		return nil, err
+	}
+	return claims, nil
 }
 
-func GenerateToken() (string, error) {
-    claims := jwt.MapClaims{
-        "username": "user1",
-        "exp":      jwt.TimeFunc().Add(1 * time.Hour).Unix(),
-    }
-    token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-    return token.SignedString(mySecretKey)
+// GenerateToken issues a signed access token for the user. Users with
+// two-factor authentication enabled only get access tokens once they
+// passed the second step, so the token records it.
+func GenerateToken(user *model.User) (string, error) {
+	now := jwt.TimeFunc()
+	jti, err := uuid.NewV4()
+	if err != nil {
+		return "", err
+	}
+
+	claims := &Claims{
+		Username: user.Username,
+		Roles:    []string{user.Role},
+		MFA:      user.TOTPEnabled,
+		StandardClaims: jwt.StandardClaims{
+			Id:        jti.String(),
+			Subject:   user.ID.String(),
+			Issuer:    Issuer,
+			Audience:  Audience,
+			IssuedAt:  now.Unix(),
+			NotBefore: now.Unix(),
+			ExpiresAt: now.Add(AccessTokenTTL).Unix(),
+		},
+	}
+	return CurrentKeySet().Sign(claims)
+}
+
+// translateError maps the jwt-go validation bitfield to a single error,
+// checking the structural failures before the claim failures.
+func translateError(err error) error {
+	vErr, ok := err.(*jwt.ValidationError)
+	if !ok {
+		return ErrTokenInvalid
+	}
+
+	switch {
+	case vErr.Errors&jwt.ValidationErrorMalformed != 0:
+		return ErrTokenMalformed
+	case vErr.Errors&(jwt.ValidationErrorSignatureInvalid|jwt.ValidationErrorUnverifiable) != 0:
+		return ErrTokenSignatureInvalid
+	case vErr.Errors&jwt.ValidationErrorExpired != 0:
+		return ErrTokenExpired
+	case vErr.Errors&jwt.ValidationErrorNotValidYet != 0:
+		return ErrTokenNotYetValid
+	case vErr.Errors&jwt.ValidationErrorIssuedAt != 0:
+		return ErrTokenIssuedInFuture
+	case vErr.Errors&jwt.ValidationErrorIssuer != 0:
+		return ErrTokenInvalidIssuer
+	case vErr.Errors&jwt.ValidationErrorAudience != 0:
+		return ErrTokenInvalidAudience
+	default:
+		return ErrTokenInvalid
+	}
 }
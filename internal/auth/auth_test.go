@@ -0,0 +1,193 @@
+package auth
+
+import (
+	"task-manager/internal/model"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	// There is no default signing key, so tests sign with a fixed secret
+	SetKeySet(NewHMACKeySet(DefaultKeyID, []byte("secret")))
+}
+
+func signClaims(t *testing.T, claims *Claims, ks *KeySet) string {
+	token, err := ks.Sign(claims)
+	require.Nil(t, err)
+	return token
+}
+
+func validClaims() *Claims {
+	now := time.Now()
+	return &Claims{
+		Username: "user1",
+		StandardClaims: jwt.StandardClaims{
+			Id:        "jti-1",
+			Subject:   "user1",
+			Issuer:    Issuer,
+			Audience:  Audience,
+			IssuedAt:  now.Unix(),
+			NotBefore: now.Unix(),
+			ExpiresAt: now.Add(time.Hour).Unix(),
+		},
+	}
+}
+
+func TestParseToken(t *testing.T) {
+	tests := []struct {
+		name        string
+		token       func() string
+		expectedErr error
+	}{
+		{
+			name:  "valid token",
+			token: func() string { return signClaims(t, validClaims(), CurrentKeySet()) },
+		},
+		{
+			name:        "malformed token",
+			token:       func() string { return "asdf.qwer.zxcv" },
+			expectedErr: ErrTokenMalformed,
+		},
+		{
+			name: "bad signature",
+			token: func() string {
+				return signClaims(t, validClaims(), NewHMACKeySet(DefaultKeyID, []byte("not-the-secret")))
+			},
+			expectedErr: ErrTokenSignatureInvalid,
+		},
+		{
+			name: "expired token",
+			token: func() string {
+				claims := validClaims()
+				claims.ExpiresAt = time.Now().Add(-time.Minute).Unix()
+				return signClaims(t, claims, CurrentKeySet())
+			},
+			expectedErr: ErrTokenExpired,
+		},
+		{
+			name: "missing exp",
+			token: func() string {
+				claims := validClaims()
+				claims.ExpiresAt = 0
+				return signClaims(t, claims, CurrentKeySet())
+			},
+			expectedErr: ErrTokenExpired,
+		},
+		{
+			name: "not yet valid",
+			token: func() string {
+				claims := validClaims()
+				claims.NotBefore = time.Now().Add(time.Minute).Unix()
+				return signClaims(t, claims, CurrentKeySet())
+			},
+			expectedErr: ErrTokenNotYetValid,
+		},
+		{
+			name: "wrong issuer",
+			token: func() string {
+				claims := validClaims()
+				claims.Issuer = "someone-else"
+				return signClaims(t, claims, CurrentKeySet())
+			},
+			expectedErr: ErrTokenInvalidIssuer,
+		},
+		{
+			name: "wrong audience",
+			token: func() string {
+				claims := validClaims()
+				claims.Audience = "another-api"
+				return signClaims(t, claims, CurrentKeySet())
+			},
+			expectedErr: ErrTokenInvalidAudience,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := ParseToken(tt.token())
+			if tt.expectedErr != nil {
+				require.Equal(t, tt.expectedErr, err)
+				require.Nil(t, claims)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, "user1", claims.Username)
+			require.Equal(t, "jti-1", claims.Id)
+		})
+	}
+}
+
+func TestGenerateToken(t *testing.T) {
+	user := &model.User{ID: uuid.Must(uuid.NewV7()), Username: "user1", Role: RoleViewer}
+	token, err := GenerateToken(user)
+	require.Nil(t, err)
+
+	claims, err := ParseToken(token)
+	require.Nil(t, err)
+	require.Equal(t, user.ID.String(), claims.Subject)
+	require.Equal(t, user.Username, claims.Username)
+	require.Equal(t, []string{RoleViewer}, claims.Roles)
+	require.Equal(t, Issuer, claims.Issuer)
+	require.Equal(t, Audience, claims.Audience)
+	require.NotEmpty(t, claims.Id)
+}
+
+func TestInvitationToken(t *testing.T) {
+	workspaceID, _ := uuid.NewV7()
+	invitedBy, _ := uuid.NewV7()
+
+	token, expiresAt, err := GenerateInvitationToken(workspaceID, "bob@example.com", "member", invitedBy)
+	require.Nil(t, err)
+	require.WithinDuration(t, time.Now().Add(InvitationTTL), expiresAt, time.Minute)
+
+	claims, err := ParseInvitationToken(token)
+	require.Nil(t, err)
+	require.Equal(t, workspaceID.String(), claims.WorkspaceID)
+	require.Equal(t, "bob@example.com", claims.Email)
+	require.Equal(t, "member", claims.Role)
+	require.Equal(t, invitedBy.String(), claims.Subject)
+
+	// An invitation is not an access token, and the other way around
+	_, err = ParseToken(token)
+	require.ErrorIs(t, err, ErrTokenInvalidAudience)
+	_, err = ParseInvitationToken(signClaims(t, validClaims(), CurrentKeySet()))
+	require.ErrorIs(t, err, ErrTokenInvalidAudience)
+}
+
+func TestMFAChallengeToken(t *testing.T) {
+	userID, _ := uuid.NewV7()
+
+	token, _, err := GenerateMFAChallengeToken(userID)
+	require.Nil(t, err)
+
+	claims, err := ParseMFAChallengeToken(token)
+	require.Nil(t, err)
+	require.Equal(t, userID.String(), claims.Subject)
+
+	// A challenge does not authenticate requests
+	_, err = ParseToken(token)
+	require.ErrorIs(t, err, ErrTokenMFAChallenge)
+}
+
+func TestGenerateToken_MFA(t *testing.T) {
+	user := &model.User{ID: uuid.Must(uuid.NewV7()), Username: "user1", Role: RoleAdmin}
+
+	token, err := GenerateToken(user)
+	require.Nil(t, err)
+	claims, err := ParseToken(token)
+	require.Nil(t, err)
+	require.False(t, NewPrincipal(claims).MFA)
+	require.False(t, NewPrincipal(claims).Can(PermUsersManage))
+
+	user.TOTPEnabled = true
+	token, err = GenerateToken(user)
+	require.Nil(t, err)
+	claims, err = ParseToken(token)
+	require.Nil(t, err)
+	require.True(t, NewPrincipal(claims).MFA)
+	require.True(t, NewPrincipal(claims).Can(PermUsersManage))
+}
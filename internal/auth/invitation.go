@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gofrs/uuid"
+)
+
+var (
+	// InvitationAudience is the "aud" claim of invitation tokens, which
+	// keeps them from being accepted as access tokens and vice versa
+	InvitationAudience = "task-manager-invitation"
+
+	// InvitationTTL is the lifetime of an invitation token
+	InvitationTTL = 7 * 24 * time.Hour
+)
+
+// InvitationClaims are the claims of a signed workspace invitation
+type InvitationClaims struct {
+	WorkspaceID string `json:"wid"`
+	Email       string `json:"email"`
+	Role        string `json:"role"`
+	jwt.StandardClaims
+}
+
+// Valid verifies the time based claims, the issuer and the audience
+func (c *InvitationClaims) Valid() error {
+	return verifyServiceClaims(&c.StandardClaims, InvitationAudience)
+}
+
+// GenerateInvitationToken signs an invitation of the email to the
+// workspace with the role
+func GenerateInvitationToken(workspaceID uuid.UUID, email, role string, invitedBy uuid.UUID) (string, time.Time, error) {
+	now := jwt.TimeFunc()
+	expiresAt := now.Add(InvitationTTL)
+	jti, err := uuid.NewV4()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	claims := &InvitationClaims{
+		WorkspaceID: workspaceID.String(),
+		Email:       email,
+		Role:        role,
+		StandardClaims: jwt.StandardClaims{
+			Id:        jti.String(),
+			Subject:   invitedBy.String(),
+			Issuer:    Issuer,
+			Audience:  InvitationAudience,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: expiresAt.Unix(),
+		},
+	}
+	token, err := CurrentKeySet().Sign(claims)
+	return token, expiresAt, err
+}
+
+// ParseInvitationToken verifies an invitation token and returns its claims
+func ParseInvitationToken(tokenString string) (*InvitationClaims, error) {
+	claims := &InvitationClaims{}
+	ks := CurrentKeySet()
+	parser := &jwt.Parser{ValidMethods: ks.Algorithms()}
+	if _, err := parser.ParseWithClaims(tokenString, claims, ks.Keyfunc); err != nil {
+		return nil, translateError(err)
+	}
+	return claims, nil
+}
+
+// verifyServiceClaims verifies the expiry, the issuer and the audience of
+// a token this service issued for its own use
+func verifyServiceClaims(c *jwt.StandardClaims, audience string) error {
+	now := jwt.TimeFunc().Unix()
+	vErr := &jwt.ValidationError{}
+
+	if c.ExpiresAt == 0 || !c.VerifyExpiresAt(now, true) {
+		vErr.Errors |= jwt.ValidationErrorExpired
+	}
+	if !c.VerifyIssuer(Issuer, true) {
+		vErr.Errors |= jwt.ValidationErrorIssuer
+	}
+	if !c.VerifyAudience(audience, true) {
+		vErr.Errors |= jwt.ValidationErrorAudience
+	}
+
+	if vErr.Errors == 0 {
+		return nil
+	}
+	return vErr
+}
@@ -0,0 +1,349 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// Key IDs of the keys configured without JWT_KEYS
+const (
+	// DefaultKeyID is the key ID of the JWT_SECRET key
+	DefaultKeyID = "default"
+
+	// DevKeyID is the key ID of the random key of JWT_INSECURE_DEV
+	DevKeyID = "dev"
+)
+
+var (
+	ErrUnknownKeyID      = errors.New("unknown key id")
+	ErrNoActiveKey       = errors.New("no active signing key")
+	ErrUnsupportedKey    = errors.New("unsupported key")
+	ErrKeyAlgMismatch    = errors.New("key does not match the algorithm")
+	ErrInvalidKeysConfig = errors.New("invalid JWT_KEYS entry, expected kid:alg:source")
+	ErrNoKeysConfigured  = errors.New("neither JWT_KEYS nor JWT_SECRET is set, set JWT_INSECURE_DEV=1 to sign with a development key")
+)
+
+// Key is a signing or verification key identified by its "kid"
+type Key struct {
+	ID     string
+	Method jwt.SigningMethod
+
+	// signer is nil for keys that are only kept to verify tokens, e.g.
+	// the previous key during a rotation
+	signer interface{}
+	verify interface{}
+}
+
+// CanSign reports whether the key holds private material
+func (k *Key) CanSign() bool {
+	return k.signer != nil
+}
+
+// KeySet holds the keys accepted for verification and the active key used
+// for signing. Tokens are matched to a key through their "kid" header.
+type KeySet struct {
+	mu     sync.RWMutex
+	active string
+	keys   map[string]*Key
+}
+
+// keySet is empty until the keys are configured, tokens can neither be
+// signed nor verified before
+var keySet = NewKeySet()
+
+var supportedAlgs = map[string]bool{"HS256": true, "RS256": true, "ES256": true, "EdDSA": true}
+
+// NewKeySet returns an empty key set
+func NewKeySet() *KeySet {
+	return &KeySet{keys: make(map[string]*Key)}
+}
+
+// NewHMACKeySet returns a key set holding a single HS256 key
+func NewHMACKeySet(kid string, secret []byte) *KeySet {
+	ks := NewKeySet()
+	ks.keys[kid] = &Key{ID: kid, Method: jwt.SigningMethodHS256, signer: secret, verify: secret}
+	ks.active = kid
+	return ks
+}
+
+// SetKeySet replaces the keys used to sign and verify tokens
+func SetKeySet(ks *KeySet) {
+	keySet = ks
+}
+
+// CurrentKeySet returns the keys used to sign and verify tokens
+func CurrentKeySet() *KeySet {
+	return keySet
+}
+
+// Add adds a key to the set. The algorithm must be one of HS256, RS256,
+// ES256 or EdDSA, and the key must be of the matching type: a secret for
+// HS256, a private key to sign, or a public key to only verify.
+func (ks *KeySet) Add(kid, alg string, key interface{}) error {
+	method := jwt.GetSigningMethod(alg)
+	if method == nil || !supportedAlgs[alg] {
+		return fmt.Errorf("%w: algorithm %q", ErrUnsupportedKey, alg)
+	}
+
+	k := &Key{ID: kid, Method: method}
+	switch key := key.(type) {
+	case []byte:
+		k.signer, k.verify = key, key
+	case *rsa.PrivateKey:
+		k.signer, k.verify = key, &key.PublicKey
+	case *ecdsa.PrivateKey:
+		k.signer, k.verify = key, &key.PublicKey
+	case ed25519.PrivateKey:
+		k.signer, k.verify = key, key.Public()
+	case *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey:
+		k.verify = key
+	default:
+		return fmt.Errorf("%w: %T", ErrUnsupportedKey, key)
+	}
+
+	if !keyMatchesAlg(alg, k.verify) {
+		return fmt.Errorf("%w: %s key for %s", ErrKeyAlgMismatch, kid, alg)
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	ks.keys[kid] = k
+	return nil
+}
+
+// SetActive selects the key used to sign new tokens
+func (ks *KeySet) SetActive(kid string) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	k, ok := ks.keys[kid]
+	if !ok {
+		return ErrUnknownKeyID
+	}
+	if !k.CanSign() {
+		return fmt.Errorf("%w: %s has no private key", ErrNoActiveKey, kid)
+	}
+	ks.active = kid
+	return nil
+}
+
+// Active returns the key used to sign new tokens
+func (ks *KeySet) Active() (*Key, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	k, ok := ks.keys[ks.active]
+	if !ok {
+		return nil, ErrNoActiveKey
+	}
+	return k, nil
+}
+
+// Lookup returns the key with the given ID
+func (ks *KeySet) Lookup(kid string) (*Key, bool) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	k, ok := ks.keys[kid]
+	return k, ok
+}
+
+// Keys returns all keys of the set
+func (ks *KeySet) Keys() []*Key {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	keys := make([]*Key, 0, len(ks.keys))
+	for _, k := range ks.keys {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Sign signs the claims with the active key and sets its "kid" header
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	k, err := ks.Active()
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(k.Method, claims)
+	token.Header["kid"] = k.ID
+	return token.SignedString(k.signer)
+}
+
+// Keyfunc resolves the verification key of a token from its "kid"
+// header. Tokens without a "kid" are verified with the active key.
+func (ks *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	var k *Key
+	if kid, ok := token.Header["kid"].(string); ok {
+		if k, ok = ks.Lookup(kid); !ok {
+			return nil, ErrUnknownKeyID
+		}
+	} else {
+		var err error
+		if k, err = ks.Active(); err != nil {
+			return nil, err
+		}
+	}
+
+	if token.Method.Alg() != k.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	}
+	return k.verify, nil
+}
+
+// Algorithms returns the algorithms of the keys in the set
+func (ks *KeySet) Algorithms() []string {
+	seen := make(map[string]bool)
+	var algs []string
+	for _, k := range ks.Keys() {
+		if alg := k.Method.Alg(); !seen[alg] {
+			seen[alg] = true
+			algs = append(algs, alg)
+		}
+	}
+	return algs
+}
+
+// LoadKeySetFromEnv builds the key set from the environment.
+//
+// JWT_KEYS is a comma separated list of kid:alg:source entries, where
+// source is either file:<path> to a PEM file, or env:<NAME> of a variable
+// holding the PEM (or the secret for HS256). JWT_ACTIVE_KID selects the
+// signing key and defaults to the first entry with a private key. Entries
+// with a public key are only used for verification, which allows to
+// publish the next key before signing with it, and to keep the previous
+// one until its tokens have expired.
+//
+// When JWT_KEYS is unset, a single HS256 key is read from JWT_SECRET.
+// Without either, JWT_INSECURE_DEV=1 signs with a random HS256 key, whose
+// tokens do not outlive the process. It is meant for development only.
+func LoadKeySetFromEnv() (*KeySet, error) {
+	spec := strings.TrimSpace(os.Getenv("JWT_KEYS"))
+	if spec == "" {
+		secret := os.Getenv("JWT_SECRET")
+		if secret != "" {
+			return NewHMACKeySet(DefaultKeyID, []byte(secret)), nil
+		}
+		if os.Getenv("JWT_INSECURE_DEV") != "1" {
+			return nil, ErrNoKeysConfigured
+		}
+		dev := make([]byte, 32)
+		if _, err := rand.Read(dev); err != nil {
+			return nil, err
+		}
+		return NewHMACKeySet(DevKeyID, dev), nil
+	}
+
+	ks := NewKeySet()
+	var first string
+	for _, entry := range strings.Split(spec, ",") {
+		parts := strings.SplitN(strings.TrimSpace(entry), ":", 3)
+		if len(parts) != 3 || parts[0] == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidKeysConfig, entry)
+		}
+		kid, alg, source := parts[0], parts[1], parts[2]
+
+		material, err := readKeySource(source)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", kid, err)
+		}
+
+		var key interface{} = material
+		if alg != jwt.SigningMethodHS256.Alg() {
+			if key, err = ParsePEMKey(material); err != nil {
+				return nil, fmt.Errorf("key %s: %w", kid, err)
+			}
+		}
+		if err := ks.Add(kid, alg, key); err != nil {
+			return nil, err
+		}
+		if k, _ := ks.Lookup(kid); first == "" && k.CanSign() {
+			first = kid
+		}
+	}
+
+	active := os.Getenv("JWT_ACTIVE_KID")
+	if active == "" {
+		active = first
+	}
+	if err := ks.SetActive(active); err != nil {
+		return nil, err
+	}
+	return ks, nil
+}
+
+// ParsePEMKey parses a PEM encoded private key (PKCS#1, PKCS#8 or SEC 1)
+// or public key (PKIX)
+func ParsePEMKey(data []byte) (interface{}, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		return x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("%w: PEM type %q", ErrUnsupportedKey, block.Type)
+	}
+}
+
+/*
+	Supporting functions
+*/
+
+func readKeySource(source string) ([]byte, error) {
+	switch {
+	case strings.HasPrefix(source, "file:"):
+		return os.ReadFile(strings.TrimPrefix(source, "file:"))
+	case strings.HasPrefix(source, "env:"):
+		name := strings.TrimPrefix(source, "env:")
+		value := os.Getenv(name)
+		if value == "" {
+			return nil, fmt.Errorf("environment variable %s is empty", name)
+		}
+		return []byte(value), nil
+	default:
+		return nil, fmt.Errorf("%w: unknown source %q", ErrInvalidKeysConfig, source)
+	}
+}
+
+func keyMatchesAlg(alg string, key crypto.PublicKey) bool {
+	switch alg {
+	case "HS256":
+		_, ok := key.([]byte)
+		return ok
+	case "RS256":
+		_, ok := key.(*rsa.PublicKey)
+		return ok
+	case "ES256":
+		k, ok := key.(*ecdsa.PublicKey)
+		return ok && k.Curve == elliptic.P256()
+	case "EdDSA":
+		_, ok := key.(ed25519.PublicKey)
+		return ok
+	default:
+		return false
+	}
+}
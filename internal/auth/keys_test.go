@@ -0,0 +1,208 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/require"
+)
+
+func writePEM(t *testing.T, blockType string, der []byte) string {
+	path := filepath.Join(t.TempDir(), "key.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	require.Nil(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func useKeySet(t *testing.T, ks *KeySet) {
+	previous := CurrentKeySet()
+	SetKeySet(ks)
+	t.Cleanup(func() { SetKeySet(previous) })
+}
+
+func TestKeySet_Algorithms(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.Nil(t, err)
+
+	tests := []struct {
+		alg string
+		key interface{}
+	}{
+		{alg: "HS256", key: []byte("a-long-enough-secret")},
+		{alg: "RS256", key: rsaKey},
+		{alg: "ES256", key: ecKey},
+		{alg: "EdDSA", key: edKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.alg, func(t *testing.T) {
+			ks := NewKeySet()
+			require.Nil(t, ks.Add("k1", tt.alg, tt.key))
+			require.Nil(t, ks.SetActive("k1"))
+			useKeySet(t, ks)
+
+			token, err := ks.Sign(validClaims())
+			require.Nil(t, err)
+
+			parsed, _, err := new(jwt.Parser).ParseUnverified(token, &Claims{})
+			require.Nil(t, err)
+			require.Equal(t, "k1", parsed.Header["kid"])
+			require.Equal(t, tt.alg, parsed.Header["alg"])
+
+			claims, err := ParseToken(token)
+			require.Nil(t, err)
+			require.Equal(t, "user1", claims.Username)
+		})
+	}
+}
+
+func TestKeySet_Add(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+
+	ks := NewKeySet()
+	require.ErrorIs(t, ks.Add("k1", "ES256", rsaKey), ErrKeyAlgMismatch)
+	require.ErrorIs(t, ks.Add("k1", "none", rsaKey), ErrUnsupportedKey)
+	require.ErrorIs(t, ks.Add("k1", "RS256", "not-a-key"), ErrUnsupportedKey)
+
+	// A public key can verify but not sign
+	require.Nil(t, ks.Add("k1", "RS256", &rsaKey.PublicKey))
+	require.ErrorIs(t, ks.SetActive("k1"), ErrNoActiveKey)
+}
+
+func TestKeySet_Rotation(t *testing.T) {
+	oldKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+	_, newKey, err := ed25519.GenerateKey(rand.Reader)
+	require.Nil(t, err)
+
+	ks := NewKeySet()
+	require.Nil(t, ks.Add("old", "RS256", oldKey))
+	require.Nil(t, ks.Add("new", "EdDSA", newKey))
+	require.Nil(t, ks.SetActive("old"))
+	useKeySet(t, ks)
+
+	oldToken, err := ks.Sign(validClaims())
+	require.Nil(t, err)
+
+	// Switch the signing key, tokens of the old key keep verifying
+	require.Nil(t, ks.SetActive("new"))
+	newToken, err := ks.Sign(validClaims())
+	require.Nil(t, err)
+
+	_, err = ParseToken(oldToken)
+	require.Nil(t, err)
+	_, err = ParseToken(newToken)
+	require.Nil(t, err)
+
+	// Tokens of a key that is no longer in the set are rejected
+	retired := NewKeySet()
+	require.Nil(t, retired.Add("new", "EdDSA", newKey))
+	require.Nil(t, retired.SetActive("new"))
+	useKeySet(t, retired)
+
+	_, err = ParseToken(oldToken)
+	require.Equal(t, ErrTokenSignatureInvalid, err)
+}
+
+func TestLoadKeySetFromEnv(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+
+	rsaPath := writePEM(t, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey))
+	ecDER, err := x509.MarshalPKIXPublicKey(&ecKey.PublicKey)
+	require.Nil(t, err)
+	ecPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: ecDER})
+
+	t.Run("no configuration", func(t *testing.T) {
+		t.Setenv("JWT_KEYS", "")
+		t.Setenv("JWT_SECRET", "")
+		t.Setenv("JWT_INSECURE_DEV", "")
+		_, err := LoadKeySetFromEnv()
+		require.ErrorIs(t, err, ErrNoKeysConfigured)
+	})
+
+	t.Run("insecure dev key", func(t *testing.T) {
+		t.Setenv("JWT_KEYS", "")
+		t.Setenv("JWT_SECRET", "")
+		t.Setenv("JWT_INSECURE_DEV", "1")
+		ks, err := LoadKeySetFromEnv()
+		require.Nil(t, err)
+
+		active, err := ks.Active()
+		require.Nil(t, err)
+		require.Equal(t, DevKeyID, active.ID)
+
+		// Each start signs with a fresh random key
+		other, err := LoadKeySetFromEnv()
+		require.Nil(t, err)
+		otherActive, err := other.Active()
+		require.Nil(t, err)
+		require.NotEqual(t, active.signer, otherActive.signer)
+	})
+
+	t.Run("secret", func(t *testing.T) {
+		t.Setenv("JWT_KEYS", "")
+		t.Setenv("JWT_SECRET", "a-long-enough-secret")
+		ks, err := LoadKeySetFromEnv()
+		require.Nil(t, err)
+
+		active, err := ks.Active()
+		require.Nil(t, err)
+		require.Equal(t, DefaultKeyID, active.ID)
+		require.Empty(t, ks.JWKS().Keys)
+	})
+
+	t.Run("file and env keys", func(t *testing.T) {
+		t.Setenv("JWT_KEYS", "next:ES256:env:NEXT_KEY,current:RS256:file:"+rsaPath)
+		t.Setenv("JWT_ACTIVE_KID", "")
+		t.Setenv("NEXT_KEY", string(ecPEM))
+		ks, err := LoadKeySetFromEnv()
+		require.Nil(t, err)
+
+		// The verification only key is skipped when picking the signer
+		active, err := ks.Active()
+		require.Nil(t, err)
+		require.Equal(t, "current", active.ID)
+
+		jwks := ks.JWKS()
+		require.Len(t, jwks.Keys, 2)
+		require.Equal(t, "current", jwks.Keys[0].Kid)
+		require.Equal(t, "RSA", jwks.Keys[0].Kty)
+		require.Equal(t, "AQAB", jwks.Keys[0].E)
+		require.Equal(t, "next", jwks.Keys[1].Kid)
+		require.Equal(t, "EC", jwks.Keys[1].Kty)
+		require.Equal(t, "P-256", jwks.Keys[1].Crv)
+	})
+
+	t.Run("invalid entry", func(t *testing.T) {
+		t.Setenv("JWT_KEYS", "current:RS256")
+		_, err := LoadKeySetFromEnv()
+		require.ErrorIs(t, err, ErrInvalidKeysConfig)
+	})
+}
+
+func TestParseToken_UnknownKeyID(t *testing.T) {
+	other := NewHMACKeySet("other", []byte("secret"))
+	token, err := other.Sign(&Claims{StandardClaims: jwt.StandardClaims{ExpiresAt: time.Now().Add(time.Hour).Unix()}})
+	require.Nil(t, err)
+
+	_, err = ParseToken(token)
+	require.Equal(t, ErrTokenSignatureInvalid, err)
+}
@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gofrs/uuid"
+)
+
+var (
+	// MFAChallengeAudience is the "aud" claim of the tokens handed out
+	// by the password step of a login with two factors. They are only
+	// accepted by the second step, never as access tokens.
+	MFAChallengeAudience = "task-manager-mfa"
+
+	// MFAChallengeTTL is the time a user has to enter the second factor
+	MFAChallengeTTL = 5 * time.Minute
+)
+
+// ErrTokenMFAChallenge is returned by ParseToken for an MFA challenge
+// token, whose login is not complete yet
+var ErrTokenMFAChallenge = errors.New("token is an MFA challenge, the second login step is missing")
+
+// mfaRequiredRoles are the roles whose permissions are only granted to
+// principals that logged in with a second factor
+var mfaRequiredRoles = map[string]bool{RoleAdmin: true}
+
+// MFAChallengeClaims are the claims of an MFA challenge token
+type MFAChallengeClaims struct {
+	jwt.StandardClaims
+}
+
+// Valid verifies the time based claims, the issuer and the audience
+func (c *MFAChallengeClaims) Valid() error {
+	return verifyServiceClaims(&c.StandardClaims, MFAChallengeAudience)
+}
+
+// GenerateMFAChallengeToken signs a challenge for the user, who passed
+// the password step of the login
+func GenerateMFAChallengeToken(userID uuid.UUID) (string, time.Time, error) {
+	now := jwt.TimeFunc()
+	expiresAt := now.Add(MFAChallengeTTL)
+	jti, err := uuid.NewV4()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	claims := &MFAChallengeClaims{
+		StandardClaims: jwt.StandardClaims{
+			Id:        jti.String(),
+			Subject:   userID.String(),
+			Issuer:    Issuer,
+			Audience:  MFAChallengeAudience,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: expiresAt.Unix(),
+		},
+	}
+	token, err := CurrentKeySet().Sign(claims)
+	return token, expiresAt, err
+}
+
+// ParseMFAChallengeToken verifies a challenge token and returns its claims
+func ParseMFAChallengeToken(tokenString string) (*MFAChallengeClaims, error) {
+	claims := &MFAChallengeClaims{}
+	ks := CurrentKeySet()
+	parser := &jwt.Parser{ValidMethods: ks.Algorithms()}
+	if _, err := parser.ParseWithClaims(tokenString, claims, ks.Keyfunc); err != nil {
+		return nil, translateError(err)
+	}
+	return claims, nil
+}
+
+// RequiresMFA reports whether one of the roles of the principal is only
+// granted after a login with a second factor, which the principal lacks
+func (p *Principal) RequiresMFA() bool {
+	if p.MFA {
+		return false
+	}
+	for _, role := range p.Roles {
+		if mfaRequiredRoles[role] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+var (
+	// OIDCStateAudience is the "aud" claim of the tokens carrying the state
+	// of an OpenID Connect login between the redirect and the callback
+	OIDCStateAudience = "task-manager-oidc-state"
+
+	// OIDCStateTTL bounds the time a user has to log in at the provider
+	OIDCStateTTL = 10 * time.Minute
+)
+
+// OIDCStateClaims hold the values a login callback is checked against.
+// They are kept in a cookie of the browser, so no server side session is
+// needed.
+type OIDCStateClaims struct {
+	State        string `json:"state"`
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"code_verifier"`
+	jwt.StandardClaims
+}
+
+// Valid verifies the time based claims, the issuer and the audience
+func (c *OIDCStateClaims) Valid() error {
+	return verifyServiceClaims(&c.StandardClaims, OIDCStateAudience)
+}
+
+// GenerateOIDCStateToken signs the state of a login started at the provider
+func GenerateOIDCStateToken(state, nonce, codeVerifier string) (string, error) {
+	now := jwt.TimeFunc()
+	claims := &OIDCStateClaims{
+		State:        state,
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		StandardClaims: jwt.StandardClaims{
+			Issuer:    Issuer,
+			Audience:  OIDCStateAudience,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(OIDCStateTTL).Unix(),
+		},
+	}
+	return CurrentKeySet().Sign(claims)
+}
+
+// ParseOIDCStateToken verifies a state token and returns its claims
+func ParseOIDCStateToken(tokenString string) (*OIDCStateClaims, error) {
+	claims := &OIDCStateClaims{}
+	ks := CurrentKeySet()
+	parser := &jwt.Parser{ValidMethods: ks.Algorithms()}
+	if _, err := parser.ParseWithClaims(tokenString, claims, ks.Keyfunc); err != nil {
+		return nil, translateError(err)
+	}
+	return claims, nil
+}
@@ -0,0 +1,16 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashPassword(t *testing.T) {
+	hash, err := HashPassword("password1")
+	require.Nil(t, err)
+	require.NotEqual(t, "password1", hash)
+
+	require.True(t, CheckPassword(hash, "password1"))
+	require.False(t, CheckPassword(hash, "password2"))
+}
@@ -0,0 +1,22 @@
+package auth
+
+import "strings"
+
+// PersonalAccessTokenPrefix starts every personal access token, which
+// tells them apart from JWTs and makes them easy to spot in leaked logs
+const PersonalAccessTokenPrefix = "tmpat_"
+
+// GeneratePersonalAccessToken returns a new random personal access token
+func GeneratePersonalAccessToken() (string, error) {
+	token, err := GenerateOpaqueToken(32)
+	if err != nil {
+		return "", err
+	}
+	return PersonalAccessTokenPrefix + token, nil
+}
+
+// IsPersonalAccessToken reports whether the bearer token is a personal
+// access token rather than a JWT
+func IsPersonalAccessToken(token string) bool {
+	return strings.HasPrefix(token, PersonalAccessTokenPrefix)
+}
@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// PrincipalKey is the gin.Context key under which the authenticated
+// principal is stored by the auth middleware
+const PrincipalKey = "principal"
+
+// Kinds of credentials a principal can authenticate with
+const (
+	TokenTypeAccess   = "access"
+	TokenTypePersonal = "personal"
+)
+
+type principalCtxKey struct{}
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	Subject   string
+	Username  string
+	Roles     []string
+	TokenID   string
+	TokenType string
+	ExpiresAt time.Time
+
+	// MFA is set when the login was completed with a second factor
+	MFA bool
+
+	// Scopes restricts the permissions granted by the roles. It is only
+	// set for personal access tokens, access tokens are not restricted.
+	Scopes []string
+}
+
+// NewPrincipal builds the principal described by verified claims
+func NewPrincipal(claims *Claims) *Principal {
+	return &Principal{
+		Subject:   claims.Subject,
+		Username:  claims.Username,
+		Roles:     claims.Roles,
+		TokenID:   claims.Id,
+		TokenType: TokenTypeAccess,
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0),
+		MFA:       claims.MFA,
+	}
+}
+
+// HasRole reports whether the principal was granted the role
+func (p *Principal) HasRole(role string) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// UserID returns the subject as a user ID, or uuid.Nil when the subject
+// is not one
+func (p *Principal) UserID() uuid.UUID {
+	return uuid.FromStringOrNil(p.Subject)
+}
+
+// WithPrincipal returns a copy of ctx carrying the principal
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalCtxKey{}, p)
+}
+
+// PrincipalFromContext returns the principal stored in ctx, if any
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalCtxKey{}).(*Principal)
+	return p, ok && p != nil
+}
@@ -0,0 +1,102 @@
+package auth
+
+import "task-manager/internal/model"
+
+// Roles a user can be granted
+const (
+	RoleAdmin  = "admin"
+	RoleMember = "member"
+	RoleViewer = "viewer"
+)
+
+// Permissions checked by the routes
+const (
+	PermTasksRead   = "tasks:read"
+	PermTasksWrite  = "tasks:write"
+	PermTasksDelete = "tasks:delete"
+	PermUsersManage = "users:manage"
+)
+
+var rolePermissions = map[string][]string{
+	RoleAdmin:  {PermTasksRead, PermTasksWrite, PermTasksDelete, PermUsersManage},
+	RoleMember: {PermTasksRead, PermTasksWrite},
+	RoleViewer: {PermTasksRead},
+}
+
+// workspaceRolePermissions are the permissions on the tasks of a workspace,
+// granted by the role of the member instead of the global role
+var workspaceRolePermissions = map[string][]string{
+	model.WorkspaceRoleOwner:  {PermTasksRead, PermTasksWrite, PermTasksDelete},
+	model.WorkspaceRoleAdmin:  {PermTasksRead, PermTasksWrite, PermTasksDelete},
+	model.WorkspaceRoleMember: {PermTasksRead, PermTasksWrite},
+}
+
+// RolePermissions returns the permissions granted by the role
+func RolePermissions(role string) []string {
+	return rolePermissions[role]
+}
+
+// Can reports whether one of the roles of the principal grants the
+// permission. Personal access tokens must also have been given the
+// permission as a scope, and some roles need a login with a second
+// factor, see RequiresMFA.
+func (p *Principal) Can(permission string) bool {
+	if p.TokenType == TokenTypePersonal && !p.HasScope(permission) {
+		return false
+	}
+	for _, role := range p.Roles {
+		if mfaRequiredRoles[role] && !p.MFA {
+			continue
+		}
+		for _, granted := range rolePermissions[role] {
+			if granted == permission {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CanInWorkspace reports whether the workspace role grants the permission.
+// Within a workspace the workspace role replaces the global role, a global
+// admin only deletes tasks as a workspace owner or admin. Global viewers
+// stay read-only whatever their workspace role, and personal access tokens
+// must still have been given the permission as a scope.
+func (p *Principal) CanInWorkspace(role, permission string) bool {
+	if p.TokenType == TokenTypePersonal && !p.HasScope(permission) {
+		return false
+	}
+	if permission != PermTasksRead && !p.canWriteAnywhere() {
+		return false
+	}
+	for _, granted := range workspaceRolePermissions[role] {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// canWriteAnywhere reports whether one of the global roles of the
+// principal grants tasks:write, which viewers lack
+func (p *Principal) canWriteAnywhere() bool {
+	for _, role := range p.Roles {
+		for _, granted := range rolePermissions[role] {
+			if granted == PermTasksWrite {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HasScope reports whether the scopes of the principal include the
+// permission
+func (p *Principal) HasScope(permission string) bool {
+	for _, scope := range p.Scopes {
+		if scope == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// rfc6238Secret is the SHA1 secret of the test vectors of RFC 6238,
+// "12345678901234567890", in base32
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCode(t *testing.T) {
+	// The vectors of RFC 6238, appendix B, truncated to 6 digits
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		code, err := TOTPCode(rfc6238Secret, time.Unix(tt.unix, 0))
+		require.Nil(t, err)
+		require.Equal(t, tt.code, code, "at %d", tt.unix)
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	code, err := TOTPCode(rfc6238Secret, now)
+	require.Nil(t, err)
+
+	// Test case 1: the current period
+	counter, ok := ValidateTOTP(rfc6238Secret, code, now)
+	require.True(t, ok)
+	require.Equal(t, now.Unix()/30, counter)
+
+	// Test case 2: within the allowed drift
+	_, ok = ValidateTOTP(rfc6238Secret, code, now.Add(TOTPPeriod))
+	require.True(t, ok)
+
+	// Test case 3: too late
+	_, ok = ValidateTOTP(rfc6238Secret, code, now.Add(2*TOTPPeriod))
+	require.False(t, ok)
+
+	// Test case 4: wrong code
+	_, ok = ValidateTOTP(rfc6238Secret, "000000", now)
+	require.False(t, ok)
+}
+
+func TestTOTPURI(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	require.Nil(t, err)
+	require.Len(t, secret, 32)
+
+	uri := TOTPURI(secret, "alice")
+	require.True(t, strings.HasPrefix(uri, "otpauth://totp/task-manager:alice?"))
+	require.Contains(t, uri, "secret="+secret)
+	require.Contains(t, uri, "issuer=task-manager")
+}
+
+func TestGenerateRecoveryCode(t *testing.T) {
+	code, err := GenerateRecoveryCode()
+	require.Nil(t, err)
+	require.Regexp(t, regexp.MustCompile(`^[a-z2-7]{5}-[a-z2-7]{5}$`), code)
+}
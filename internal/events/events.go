@@ -0,0 +1,51 @@
+package events
+
+import (
+	"context"
+	"sync"
+)
+
+// Event is something that happened in the application, published on a Bus
+// for the components that react to it
+type Event interface {
+	// Name identifies the kind of event, handlers subscribe to a name
+	Name() string
+}
+
+// Handler reacts to an event. Handlers run synchronously in the publishing
+// goroutine, slow work should be handed off.
+type Handler func(context.Context, Event)
+
+// Bus dispatches the published events to the handlers subscribed to them.
+// The zero value is ready to use.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe registers the handler for the events of the name
+func (b *Bus) Subscribe(name string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.handlers == nil {
+		b.handlers = make(map[string][]Handler)
+	}
+	b.handlers[name] = append(b.handlers[name], handler)
+}
+
+// Publish calls the handlers subscribed to the event, in the order they
+// subscribed
+func (b *Bus) Publish(ctx context.Context, event Event) {
+	b.mu.RLock()
+	handlers := b.handlers[event.Name()]
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(ctx, event)
+	}
+}
@@ -0,0 +1,37 @@
+package events
+
+import (
+	"context"
+	"task-manager/internal/model"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBus(t *testing.T) {
+	// Test case 1
+	t.Run("Bus: handlers are called in order", func(t *testing.T) {
+		bus := NewBus()
+
+		var calls []string
+		bus.Subscribe(TaskOverdueEvent, func(ctx context.Context, e Event) {
+			calls = append(calls, "first:"+e.(TaskOverdue).Task.Title)
+		})
+		bus.Subscribe(TaskOverdueEvent, func(ctx context.Context, e Event) {
+			calls = append(calls, "second:"+e.(TaskOverdue).Task.Title)
+		})
+
+		bus.Publish(context.Background(), TaskOverdue{Task: model.Task{Title: "report"}})
+		require.Equal(t, []string{"first:report", "second:report"}, calls)
+	})
+
+	// Test case 2
+	t.Run("Bus: events without subscribers", func(t *testing.T) {
+		var bus Bus
+		bus.Subscribe("other", func(ctx context.Context, e Event) {
+			t.Fatal("unexpected call")
+		})
+
+		bus.Publish(context.Background(), TaskOverdue{})
+	})
+}
@@ -0,0 +1,16 @@
+package events
+
+import "task-manager/internal/model"
+
+// Names of the task events
+const (
+	TaskOverdueEvent = "task.overdue"
+)
+
+// TaskOverdue is published once when a task passes its due date without
+// being completed
+type TaskOverdue struct {
+	Task model.Task
+}
+
+func (TaskOverdue) Name() string { return TaskOverdueEvent }
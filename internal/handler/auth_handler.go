@@ -0,0 +1,255 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"task-manager/internal/auth"
+	"task-manager/internal/model"
+	"task-manager/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+)
+
+type (
+	IAuthHandler interface {
+		Register(*gin.Context)
+		Login(*gin.Context)
+		Refresh(*gin.Context)
+		Logout(*gin.Context)
+	}
+
+	AuthHandler struct {
+		UserService  service.IUserService
+		TokenService service.ITokenService
+	}
+)
+
+const (
+	ErrInvalidCredentials  = "invalid username or password"
+	ErrUserAlreadyExists   = "user already exists"
+	ErrInvalidRefreshToken = "refresh token is invalid"
+	ErrRefreshTokenReused  = "refresh token reuse detected, please log in again"
+)
+
+func NewAuthHandler(userService service.IUserService, tokenService service.ITokenService) *AuthHandler {
+	return &AuthHandler{UserService: userService, TokenService: tokenService}
+}
+
+/*
+	Handler functions
+*/
+
+func (h *AuthHandler) Register(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req model.RegisterRequest
+
+	// Bind the JSON body to the register request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errMsg := handleValidationError(err)
+		c.JSON(http.StatusBadRequest, &model.Response{Messages: errMsg})
+		return
+	}
+
+	// Create the user, the password is hashed by the service
+	user := model.User{Username: req.Username, Email: req.Email}
+	if err := h.UserService.CreateUser(ctx, &user, req.Password); err != nil {
+		if errors.Is(err, service.ErrUserAlreadyExists) {
+			c.JSON(http.StatusConflict, &model.Response{Message: ErrUserAlreadyExists})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	c.JSON(http.StatusCreated, user)
+}
+
+func (h *AuthHandler) Login(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req model.LoginRequest
+
+	// Bind the JSON body to the login request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errMsg := handleValidationError(err)
+		c.JSON(http.StatusBadRequest, &model.Response{Messages: errMsg})
+		return
+	}
+
+	// Check the credentials
+	user, err := h.UserService.Authenticate(ctx, req.Username, req.Password)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCredentials) {
+			c.JSON(http.StatusUnauthorized, &model.Response{Message: ErrInvalidCredentials})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	// Issue the tokens, or the challenge of the second step
+	completeLogin(c, h.TokenService, user)
+}
+
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req model.RefreshRequest
+
+	// Bind the JSON body to the refresh request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errMsg := handleValidationError(err)
+		c.JSON(http.StatusBadRequest, &model.Response{Messages: errMsg})
+		return
+	}
+
+	// Rotate the refresh token, a reused token kills its whole family
+	userID, refreshToken, err := h.TokenService.RotateRefreshToken(ctx, req.RefreshToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrRefreshTokenInvalid):
+			c.JSON(http.StatusUnauthorized, &model.Response{Code: "invalid_refresh_token", Message: ErrInvalidRefreshToken})
+		case errors.Is(err, service.ErrRefreshTokenReused):
+			c.JSON(http.StatusUnauthorized, &model.Response{Code: "refresh_token_reused", Message: ErrRefreshTokenReused})
+		default:
+			c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+		}
+		return
+	}
+
+	user, err := h.UserService.GetUserByID(ctx, userID)
+	if err != nil {
+		if !gorm.IsRecordNotFoundError(err) {
+			c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+			return
+		}
+
+		// The user was deleted, the family of the token dies with it
+		if err := h.TokenService.RevokeRefreshToken(ctx, userID, refreshToken); err != nil && !errors.Is(err, service.ErrRefreshTokenInvalid) {
+			c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+			return
+		}
+		c.JSON(http.StatusUnauthorized, &model.Response{Code: "invalid_refresh_token", Message: ErrInvalidRefreshToken})
+		return
+	}
+
+	resp, err := tokenResponse(user, refreshToken)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *AuthHandler) Logout(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req model.LogoutRequest
+
+	// The body is optional, it only carries the refresh token to revoke
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: ErrInvalidJSONBody})
+		return
+	}
+
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, &model.Response{Message: http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	// Revoke the access token used for this request. Personal access
+	// tokens outlive the session, they are revoked on their own.
+	if principal.TokenType != auth.TokenTypePersonal {
+		if err := h.TokenService.RevokeAccessToken(ctx, principal.TokenID, principal.ExpiresAt); err != nil {
+			c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+			return
+		}
+	}
+
+	// Revoke the refresh token family, if one was given
+	if req.RefreshToken != "" {
+		if err := h.revokeRefreshToken(ctx, principal, req.RefreshToken); err != nil {
+			c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, &model.Response{Message: "Logged out successfully"})
+}
+
+/*
+	Suporting functions
+*/
+
+// revokeRefreshToken revokes the family of the refresh token. Unknown
+// tokens are ignored so that logging out twice is not an error.
+func (h *AuthHandler) revokeRefreshToken(ctx context.Context, principal *auth.Principal, token string) error {
+	userID, err := uuid.FromString(principal.Subject)
+	if err != nil {
+		return nil
+	}
+
+	err = h.TokenService.RevokeRefreshToken(ctx, userID, token)
+	if errors.Is(err, service.ErrRefreshTokenInvalid) {
+		return nil
+	}
+	return err
+}
+
+// completeLogin answers a successful first login step. Users with
+// two-factor authentication get a challenge for the second step, the
+// others their tokens.
+func completeLogin(c *gin.Context, tokenService service.ITokenService, user *model.User) {
+	if !user.TOTPEnabled {
+		issueTokens(c, tokenService, user)
+		return
+	}
+
+	mfaToken, _, err := auth.GenerateMFAChallengeToken(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	c.JSON(http.StatusOK, &model.MFAChallengeResponse{
+		MFARequired: true,
+		MFAToken:    mfaToken,
+		ExpiresIn:   int64(auth.MFAChallengeTTL.Seconds()),
+	})
+}
+
+// issueTokens answers a completed login with the access and refresh tokens
+func issueTokens(c *gin.Context, tokenService service.ITokenService, user *model.User) {
+	refreshToken, err := tokenService.IssueRefreshToken(c.Request.Context(), user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	resp, err := tokenResponse(user, refreshToken)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// tokenResponse issues an access token for the user and pairs it with
+// the refresh token
+func tokenResponse(user *model.User, refreshToken string) (*model.TokenResponse, error) {
+	accessToken, err := auth.GenerateToken(user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.TokenResponse{
+		AccessToken:  accessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(auth.AccessTokenTTL.Seconds()),
+		RefreshToken: refreshToken,
+	}, nil
+}
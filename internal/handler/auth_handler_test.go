@@ -0,0 +1,268 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"task-manager/internal/auth"
+	"task-manager/internal/mocks"
+	"task-manager/internal/model"
+	"task-manager/internal/service"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newJSONContext(t *testing.T, method, url string, body any) (*gin.Context, *httptest.ResponseRecorder) {
+	payload, err := json.Marshal(body)
+	require.Nil(t, err)
+
+	req, err := http.NewRequest(method, url, bytes.NewReader(payload))
+	require.Nil(t, err)
+	req = req.WithContext(context.Background())
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	return c, w
+}
+
+func Test_Register(t *testing.T) {
+	userService := new(mocks.IUserService)
+	tokenService := new(mocks.ITokenService)
+	authHandler := NewAuthHandler(userService, tokenService)
+
+	// Test case 1
+	t.Run("Register: input validation error", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/auth/register",
+			model.RegisterRequest{Username: "user1", Email: "not-an-email", Password: "short"})
+
+		authHandler.Register(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		expectedResp := `{"messages":{"email":"it must be a valid email address","password":"it must be at least 8 characters long"}}`
+		require.Equal(t, expectedResp, w.Body.String())
+	})
+
+	// Test case 2
+	t.Run("Register: user already exists", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/auth/register",
+			model.RegisterRequest{Username: "user1", Email: "user1@example.com", Password: "password1"})
+
+		userService.On("CreateUser", mock.Anything, mock.AnythingOfType("*model.User"), "password1").
+			Return(service.ErrUserAlreadyExists).Once()
+
+		authHandler.Register(c)
+
+		require.Equal(t, http.StatusConflict, w.Code)
+		require.Equal(t, `{"message":"user already exists"}`, w.Body.String())
+	})
+
+	// Test case 3
+	t.Run("Register: success", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/auth/register",
+			model.RegisterRequest{Username: "user1", Email: "user1@example.com", Password: "password1"})
+
+		userService.On("CreateUser", mock.Anything, mock.AnythingOfType("*model.User"), "password1").
+			Return(nil).Once()
+
+		authHandler.Register(c)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		var user map[string]any
+		require.Nil(t, json.Unmarshal(w.Body.Bytes(), &user))
+		require.Equal(t, "user1", user["username"])
+		require.NotContains(t, user, "password_hash")
+	})
+}
+
+func Test_Login(t *testing.T) {
+	userService := new(mocks.IUserService)
+	tokenService := new(mocks.ITokenService)
+	authHandler := NewAuthHandler(userService, tokenService)
+
+	// Test case 1
+	t.Run("Login: invalid credentials", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/auth/login",
+			model.LoginRequest{Username: "user1", Password: "wrong-password"})
+
+		userService.On("Authenticate", mock.Anything, "user1", "wrong-password").
+			Return(nil, service.ErrInvalidCredentials).Once()
+
+		authHandler.Login(c)
+
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+		require.Equal(t, `{"message":"invalid username or password"}`, w.Body.String())
+	})
+
+	// Test case 2
+	t.Run("Login: error", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/auth/login",
+			model.LoginRequest{Username: "user1", Password: "password1"})
+
+		userService.On("Authenticate", mock.Anything, "user1", "password1").
+			Return(nil, errMock).Once()
+
+		authHandler.Login(c)
+
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+		require.Equal(t, `{"message":"Internal Server Error"}`, w.Body.String())
+	})
+
+	// Test case 3
+	t.Run("Login: success", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/auth/login",
+			model.LoginRequest{Username: "user1", Password: "password1"})
+
+		user := &model.User{ID: uuid1, Username: "user1"}
+		userService.On("Authenticate", mock.Anything, "user1", "password1").
+			Return(user, nil).Once()
+		tokenService.On("IssueRefreshToken", mock.Anything, uuid1).
+			Return("refresh-1", nil).Once()
+
+		authHandler.Login(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp model.TokenResponse
+		require.Nil(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Equal(t, "Bearer", resp.TokenType)
+		require.Equal(t, "refresh-1", resp.RefreshToken)
+
+		claims, err := auth.ParseToken(resp.AccessToken)
+		require.Nil(t, err)
+		require.Equal(t, uuid1.String(), claims.Subject)
+	})
+
+	// Test case 4
+	t.Run("Login: two-factor authentication enabled", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/auth/login",
+			model.LoginRequest{Username: "user1", Password: "password1"})
+
+		user := &model.User{ID: uuid1, Username: "user1", TOTPEnabled: true}
+		userService.On("Authenticate", mock.Anything, "user1", "password1").
+			Return(user, nil).Once()
+
+		authHandler.Login(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp model.MFAChallengeResponse
+		require.Nil(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.True(t, resp.MFARequired)
+
+		claims, err := auth.ParseMFAChallengeToken(resp.MFAToken)
+		require.Nil(t, err)
+		require.Equal(t, uuid1.String(), claims.Subject)
+	})
+}
+
+func Test_Refresh(t *testing.T) {
+	userService := new(mocks.IUserService)
+	tokenService := new(mocks.ITokenService)
+	authHandler := NewAuthHandler(userService, tokenService)
+
+	// Test case 1
+	t.Run("Refresh: invalid token", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/auth/refresh", model.RefreshRequest{RefreshToken: "unknown"})
+
+		tokenService.On("RotateRefreshToken", mock.Anything, "unknown").
+			Return(uuid.Nil, "", service.ErrRefreshTokenInvalid).Once()
+
+		authHandler.Refresh(c)
+
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+		require.Equal(t, `{"code":"invalid_refresh_token","message":"refresh token is invalid"}`, w.Body.String())
+	})
+
+	// Test case 2
+	t.Run("Refresh: reused token", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/auth/refresh", model.RefreshRequest{RefreshToken: "rotated"})
+
+		tokenService.On("RotateRefreshToken", mock.Anything, "rotated").
+			Return(uuid.Nil, "", service.ErrRefreshTokenReused).Once()
+
+		authHandler.Refresh(c)
+
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+		require.Equal(t, `{"code":"refresh_token_reused","message":"refresh token reuse detected, please log in again"}`, w.Body.String())
+	})
+
+	// Test case 3
+	t.Run("Refresh: success", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/auth/refresh", model.RefreshRequest{RefreshToken: "refresh-1"})
+
+		tokenService.On("RotateRefreshToken", mock.Anything, "refresh-1").
+			Return(uuid1, "refresh-2", nil).Once()
+		userService.On("GetUserByID", mock.Anything, uuid1).
+			Return(&model.User{ID: uuid1, Username: "user1"}, nil).Once()
+
+		authHandler.Refresh(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp model.TokenResponse
+		require.Nil(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Equal(t, "refresh-2", resp.RefreshToken)
+		require.NotEmpty(t, resp.AccessToken)
+	})
+
+	// Test case 4
+	t.Run("Refresh: deleted user", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/auth/refresh", model.RefreshRequest{RefreshToken: "refresh-1"})
+
+		tokenService.On("RotateRefreshToken", mock.Anything, "refresh-1").
+			Return(uuid1, "refresh-2", nil).Once()
+		userService.On("GetUserByID", mock.Anything, uuid1).
+			Return(nil, gorm.ErrRecordNotFound).Once()
+		tokenService.On("RevokeRefreshToken", mock.Anything, uuid1, "refresh-2").Return(nil).Once()
+
+		authHandler.Refresh(c)
+
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+		require.Equal(t, `{"code":"invalid_refresh_token","message":"refresh token is invalid"}`, w.Body.String())
+		tokenService.AssertExpectations(t)
+	})
+}
+
+func Test_Logout(t *testing.T) {
+	userService := new(mocks.IUserService)
+	tokenService := new(mocks.ITokenService)
+	authHandler := NewAuthHandler(userService, tokenService)
+
+	principal := &auth.Principal{Subject: uuid1.String(), TokenID: "jti-1", ExpiresAt: time.Unix(1700000000, 0)}
+
+	// Test case 1
+	t.Run("Logout: success", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/auth/logout", model.LogoutRequest{RefreshToken: "refresh-1"})
+		c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
+
+		tokenService.On("RevokeAccessToken", mock.Anything, "jti-1", principal.ExpiresAt).
+			Return(nil).Once()
+		tokenService.On("RevokeRefreshToken", mock.Anything, uuid1, "refresh-1").
+			Return(nil).Once()
+
+		authHandler.Logout(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, `{"message":"Logged out successfully"}`, w.Body.String())
+	})
+
+	// Test case 2
+	t.Run("Logout: error", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/auth/logout", model.LogoutRequest{})
+		c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
+
+		tokenService.On("RevokeAccessToken", mock.Anything, "jti-1", principal.ExpiresAt).
+			Return(errMock).Once()
+
+		authHandler.Logout(c)
+
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+		require.Equal(t, `{"message":"Internal Server Error"}`, w.Body.String())
+	})
+}
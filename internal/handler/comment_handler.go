@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"task-manager/internal/model"
+	"task-manager/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+type (
+	ICommentHandler interface {
+		GetComments(*gin.Context)
+		CreateComment(*gin.Context)
+		UpdateComment(*gin.Context)
+		DeleteComment(*gin.Context)
+		GetCommentHistory(*gin.Context)
+	}
+
+	CommentHandler struct {
+		CommentService service.ICommentService
+	}
+)
+
+func NewCommentHandler(commentService service.ICommentService) *CommentHandler {
+	return &CommentHandler{CommentService: commentService}
+}
+
+/*
+	Handler functions
+*/
+
+func (h *CommentHandler) GetComments(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the task ID
+	taskId, err := uuid.FromString(c.Param("taskId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Fetch the comment threads of the task
+	comments, err := h.CommentService.GetComments(ctx, taskId)
+	if err != nil {
+		handleCommentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, comments)
+}
+
+func (h *CommentHandler) CreateComment(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the task ID
+	taskId, err := uuid.FromString(c.Param("taskId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Bind the JSON body to the comment request
+	var req model.CommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errMsg := handleValidationError(err)
+		c.JSON(http.StatusBadRequest, &model.Response{Messages: errMsg})
+		return
+	}
+
+	// Post the comment, notifying the users it mentions
+	comment := model.Comment{Body: req.Body, ParentID: req.ParentID}
+	if err := h.CommentService.CreateComment(ctx, taskId, &comment); err != nil {
+		handleCommentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, comment)
+}
+
+func (h *CommentHandler) UpdateComment(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the task and comment IDs
+	taskId, err := uuid.FromString(c.Param("taskId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+	commentId, err := uuid.FromString(c.Param("commentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Bind the JSON body to the update request
+	var req model.UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errMsg := handleValidationError(err)
+		c.JSON(http.StatusBadRequest, &model.Response{Messages: errMsg})
+		return
+	}
+
+	// Edit the comment, its previous body is kept in its history
+	comment, err := h.CommentService.UpdateComment(ctx, taskId, commentId, req.Body)
+	if err != nil {
+		handleCommentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, comment)
+}
+
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the task and comment IDs
+	taskId, err := uuid.FromString(c.Param("taskId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+	commentId, err := uuid.FromString(c.Param("commentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Delete the comment along with its replies
+	if err := h.CommentService.DeleteComment(ctx, taskId, commentId); err != nil {
+		handleCommentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, &model.Response{Message: "Comment deleted successfully"})
+}
+
+func (h *CommentHandler) GetCommentHistory(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the task and comment IDs
+	taskId, err := uuid.FromString(c.Param("taskId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+	commentId, err := uuid.FromString(c.Param("commentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Fetch the previous bodies of the comment
+	revisions, err := h.CommentService.GetCommentHistory(ctx, taskId, commentId)
+	if err != nil {
+		handleCommentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, revisions)
+}
+
+/*
+	Suporting functions
+*/
+
+// handleCommentError writes the response of a failed comment operation
+func handleCommentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrNestedReply):
+		c.JSON(http.StatusBadRequest, &model.Response{Code: "nested_reply", Message: err.Error()})
+	case errors.Is(err, service.ErrNotCommentAuthor):
+		c.JSON(http.StatusForbidden, &model.Response{Code: "forbidden", Message: err.Error()})
+	case errors.Is(err, service.ErrCommentNotFound):
+		c.JSON(http.StatusNotFound, &model.Response{Message: err.Error()})
+	case strings.EqualFold(err.Error(), "record not found"):
+		c.JSON(http.StatusNotFound, &model.Response{Message: ErrTaskNotFound})
+	default:
+		c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+	}
+}
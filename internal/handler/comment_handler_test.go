@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"net/http"
+	"task-manager/internal/mocks"
+	"task-manager/internal/model"
+	"task-manager/internal/service"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CreateComment(t *testing.T) {
+	commentService := new(mocks.ICommentService)
+	commentHandler := NewCommentHandler(commentService)
+	parentID, _ := uuid.NewV7()
+
+	// Test case 1
+	t.Run("CreateComment: empty body", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/tasks/"+uuid1.String()+"/comments", model.CommentRequest{})
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		commentHandler.CreateComment(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Equal(t, `{"messages":{"body":"this is a required field"}}`, w.Body.String())
+	})
+
+	// Test case 2
+	t.Run("CreateComment: reply to a reply", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/tasks/"+uuid1.String()+"/comments", model.CommentRequest{Body: "Agreed", ParentID: &parentID})
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		commentService.On("CreateComment", mock.Anything, uuid1, mock.AnythingOfType("*model.Comment")).
+			Return(service.ErrNestedReply).Once()
+
+		commentHandler.CreateComment(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Equal(t, `{"code":"nested_reply","message":"replies can only answer a top level comment"}`, w.Body.String())
+	})
+
+	// Test case 3
+	t.Run("CreateComment: task not found", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/tasks/"+uuid1.String()+"/comments", model.CommentRequest{Body: "Any update?"})
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		commentService.On("CreateComment", mock.Anything, uuid1, mock.AnythingOfType("*model.Comment")).
+			Return(errMockNotFound).Once()
+
+		commentHandler.CreateComment(c)
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+		require.Equal(t, `{"message":"task not found"}`, w.Body.String())
+	})
+
+	// Test case 4
+	t.Run("CreateComment: success", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/tasks/"+uuid1.String()+"/comments", model.CommentRequest{Body: "@alice see above", ParentID: &parentID})
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		commentService.On("CreateComment", mock.Anything, uuid1, mock.MatchedBy(func(comment *model.Comment) bool {
+			return comment.Body == "@alice see above" && comment.ParentID != nil && *comment.ParentID == parentID
+		})).Return(nil).Once()
+
+		commentHandler.CreateComment(c)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		require.Contains(t, w.Body.String(), `"parent_id":"`+parentID.String()+`"`)
+	})
+}
+
+func Test_UpdateComment(t *testing.T) {
+	commentService := new(mocks.ICommentService)
+	commentHandler := NewCommentHandler(commentService)
+
+	// Test case 1
+	t.Run("UpdateComment: not the author", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPut, "/tasks/"+uuid1.String()+"/comments/"+uuid1.String(), model.UpdateCommentRequest{Body: "Edited"})
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()}, gin.Param{Key: "commentId", Value: uuid1.String()})
+
+		commentService.On("UpdateComment", mock.Anything, uuid1, uuid1, "Edited").
+			Return(nil, service.ErrNotCommentAuthor).Once()
+
+		commentHandler.UpdateComment(c)
+
+		require.Equal(t, http.StatusForbidden, w.Code)
+		require.Equal(t, `{"code":"forbidden","message":"only the author can change the comment"}`, w.Body.String())
+	})
+
+	// Test case 2
+	t.Run("UpdateComment: comment not found", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPut, "/tasks/"+uuid1.String()+"/comments/"+uuid1.String(), model.UpdateCommentRequest{Body: "Edited"})
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()}, gin.Param{Key: "commentId", Value: uuid1.String()})
+
+		commentService.On("UpdateComment", mock.Anything, uuid1, uuid1, "Edited").
+			Return(nil, service.ErrCommentNotFound).Once()
+
+		commentHandler.UpdateComment(c)
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+		require.Equal(t, `{"message":"comment not found"}`, w.Body.String())
+	})
+
+	// Test case 3
+	t.Run("UpdateComment: invalid comment ID", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPut, "/tasks/"+uuid1.String()+"/comments/1", model.UpdateCommentRequest{Body: "Edited"})
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()}, gin.Param{Key: "commentId", Value: "1"})
+
+		commentHandler.UpdateComment(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func Test_DeleteComment(t *testing.T) {
+	commentService := new(mocks.ICommentService)
+	commentHandler := NewCommentHandler(commentService)
+
+	// Test case 1
+	t.Run("DeleteComment: success", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodDelete, "/tasks/"+uuid1.String()+"/comments/"+uuid1.String(), nil)
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()}, gin.Param{Key: "commentId", Value: uuid1.String()})
+
+		commentService.On("DeleteComment", mock.Anything, uuid1, uuid1).Return(nil).Once()
+
+		commentHandler.DeleteComment(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, `{"message":"Comment deleted successfully"}`, w.Body.String())
+	})
+}
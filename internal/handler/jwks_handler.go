@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"net/http"
+	"task-manager/internal/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	IJWKSHandler interface {
+		GetJWKS(*gin.Context)
+	}
+
+	JWKSHandler struct {
+	}
+)
+
+func NewJWKSHandler() *JWKSHandler {
+	return &JWKSHandler{}
+}
+
+/*
+	Handler functions
+*/
+
+// GetJWKS publishes the public keys other services use to verify the
+// tokens issued by this one
+func (h *JWKSHandler) GetJWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, auth.CurrentKeySet().JWKS())
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"task-manager/internal/auth"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWKS(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.Nil(t, err)
+
+	ks := auth.NewKeySet()
+	require.Nil(t, ks.Add("k1", "EdDSA", key))
+	require.Nil(t, ks.SetActive("k1"))
+
+	previous := auth.CurrentKeySet()
+	auth.SetKeySet(ks)
+	defer auth.SetKeySet(previous)
+
+	// Setup
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/.well-known/jwks.json", NewJWKSHandler().GetJWKS)
+
+	// Test
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/.well-known/jwks.json", nil)
+	router.ServeHTTP(w, req)
+
+	// Assert
+	require.Equal(t, http.StatusOK, w.Code)
+	var jwks auth.JWKS
+	require.Nil(t, json.Unmarshal(w.Body.Bytes(), &jwks))
+	require.Len(t, jwks.Keys, 1)
+	require.Equal(t, "OKP", jwks.Keys[0].Kty)
+	require.Equal(t, "Ed25519", jwks.Keys[0].Crv)
+	require.Equal(t, "k1", jwks.Keys[0].Kid)
+}
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"task-manager/internal/model"
+	"task-manager/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+type (
+	ILabelHandler interface {
+		GetLabels(*gin.Context)
+		CreateLabel(*gin.Context)
+		UpdateLabel(*gin.Context)
+		DeleteLabel(*gin.Context)
+	}
+
+	LabelHandler struct {
+		LabelService service.ILabelService
+	}
+)
+
+const (
+	ErrLabelNotFound = "label not found"
+)
+
+func NewLabelHandler(labelService service.ILabelService) *LabelHandler {
+	return &LabelHandler{LabelService: labelService}
+}
+
+/*
+	Handler functions
+*/
+
+func (h *LabelHandler) GetLabels(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Fetch the labels of the tenant
+	labels, err := h.LabelService.GetLabels(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	c.JSON(http.StatusOK, labels)
+}
+
+func (h *LabelHandler) CreateLabel(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Bind the JSON body to the label request
+	var req model.LabelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errMsg := handleValidationError(err)
+		c.JSON(http.StatusBadRequest, &model.Response{Messages: errMsg})
+		return
+	}
+
+	// Create the label
+	label := model.Label{Name: req.Name, Color: strings.ToLower(req.Color)}
+	if err := h.LabelService.CreateLabel(ctx, &label); err != nil {
+		handleLabelError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, label)
+}
+
+func (h *LabelHandler) UpdateLabel(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the label ID
+	labelId, err := uuid.FromString(c.Param("labelId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Bind the JSON body to the label request
+	var req model.LabelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errMsg := handleValidationError(err)
+		c.JSON(http.StatusBadRequest, &model.Response{Messages: errMsg})
+		return
+	}
+
+	// Rename or recolor the label
+	label := model.Label{Name: req.Name, Color: strings.ToLower(req.Color)}
+	if err := h.LabelService.UpdateLabel(ctx, labelId, &label); err != nil {
+		handleLabelError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, &model.Response{Message: "Label updated successfully"})
+}
+
+func (h *LabelHandler) DeleteLabel(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the label ID
+	labelId, err := uuid.FromString(c.Param("labelId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Delete the label, it is detached from its tasks
+	if err := h.LabelService.DeleteLabel(ctx, labelId); err != nil {
+		handleLabelError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, &model.Response{Message: "Label deleted successfully"})
+}
+
+/*
+	Suporting functions
+*/
+
+// handleLabelError writes the response of a failed label operation
+func handleLabelError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrLabelExists):
+		c.JSON(http.StatusConflict, &model.Response{Message: err.Error()})
+	case strings.EqualFold(err.Error(), "record not found"):
+		c.JSON(http.StatusNotFound, &model.Response{Message: ErrLabelNotFound})
+	default:
+		c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+	}
+}
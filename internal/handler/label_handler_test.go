@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"net/http"
+	"task-manager/internal/mocks"
+	"task-manager/internal/model"
+	"task-manager/internal/service"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CreateLabel(t *testing.T) {
+	labelService := new(mocks.ILabelService)
+	labelHandler := NewLabelHandler(labelService)
+
+	// Test case 1
+	t.Run("CreateLabel: input validation error", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/labels/", model.LabelRequest{Name: "bug", Color: "red"})
+
+		labelHandler.CreateLabel(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Equal(t, `{"messages":{"color":"it must be a hex color, e.g. #1f6feb"}}`, w.Body.String())
+	})
+
+	// Test case 2
+	t.Run("CreateLabel: name taken", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/labels/", model.LabelRequest{Name: "bug", Color: "#D73A4A"})
+
+		labelService.On("CreateLabel", mock.Anything, mock.AnythingOfType("*model.Label")).
+			Return(service.ErrLabelExists).Once()
+
+		labelHandler.CreateLabel(c)
+
+		require.Equal(t, http.StatusConflict, w.Code)
+		require.Equal(t, `{"message":"a label with this name already exists"}`, w.Body.String())
+	})
+
+	// Test case 3
+	t.Run("CreateLabel: success", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/labels/", model.LabelRequest{Name: "bug", Color: "#D73A4A"})
+
+		labelService.On("CreateLabel", mock.Anything, mock.MatchedBy(func(label *model.Label) bool {
+			return label.Name == "bug" && label.Color == "#d73a4a"
+		})).Return(nil).Once()
+
+		labelHandler.CreateLabel(c)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		require.Contains(t, w.Body.String(), `"name":"bug","color":"#d73a4a"`)
+	})
+}
+
+func Test_UpdateLabel(t *testing.T) {
+	labelService := new(mocks.ILabelService)
+	labelHandler := NewLabelHandler(labelService)
+
+	// Test case 1
+	t.Run("UpdateLabel: invalid label id", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPut, "/labels/abc", model.LabelRequest{Name: "bug", Color: "#d73a4a"})
+		c.Params = append(c.Params, gin.Param{Key: "labelId", Value: "abc"})
+
+		labelHandler.UpdateLabel(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	// Test case 2
+	t.Run("UpdateLabel: not found", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPut, "/labels/"+uuid1.String(), model.LabelRequest{Name: "bug", Color: "#d73a4a"})
+		c.Params = append(c.Params, gin.Param{Key: "labelId", Value: uuid1.String()})
+
+		labelService.On("UpdateLabel", mock.Anything, uuid1, mock.AnythingOfType("*model.Label")).
+			Return(errMockNotFound).Once()
+
+		labelHandler.UpdateLabel(c)
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+		require.Equal(t, `{"message":"label not found"}`, w.Body.String())
+	})
+
+	// Test case 3
+	t.Run("UpdateLabel: success", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPut, "/labels/"+uuid1.String(), model.LabelRequest{Name: "infra", Color: "#0e8a16"})
+		c.Params = append(c.Params, gin.Param{Key: "labelId", Value: uuid1.String()})
+
+		labelService.On("UpdateLabel", mock.Anything, uuid1, &model.Label{Name: "infra", Color: "#0e8a16"}).
+			Return(nil).Once()
+
+		labelHandler.UpdateLabel(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, `{"message":"Label updated successfully"}`, w.Body.String())
+	})
+}
+
+func Test_DeleteLabel(t *testing.T) {
+	labelService := new(mocks.ILabelService)
+	labelHandler := NewLabelHandler(labelService)
+
+	// Test case 1
+	t.Run("DeleteLabel: error", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodDelete, "/labels/"+uuid1.String(), nil)
+		c.Params = append(c.Params, gin.Param{Key: "labelId", Value: uuid1.String()})
+
+		labelService.On("DeleteLabel", mock.Anything, uuid1).
+			Return(errMock).Once()
+
+		labelHandler.DeleteLabel(c)
+
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	// Test case 2
+	t.Run("DeleteLabel: success", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodDelete, "/labels/"+uuid1.String(), nil)
+		c.Params = append(c.Params, gin.Param{Key: "labelId", Value: uuid1.String()})
+
+		labelService.On("DeleteLabel", mock.Anything, uuid1).
+			Return(nil).Once()
+
+		labelHandler.DeleteLabel(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, `{"message":"Label deleted successfully"}`, w.Body.String())
+	})
+}
@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"task-manager/internal/auth"
+	"task-manager/internal/model"
+	"task-manager/internal/service"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+type (
+	IMFAHandler interface {
+		EnrollTOTP(*gin.Context)
+		ConfirmTOTP(*gin.Context)
+		DisableTOTP(*gin.Context)
+		RegenerateRecoveryCodes(*gin.Context)
+		LoginMFA(*gin.Context)
+	}
+
+	MFAHandler struct {
+		MFAService   service.IMFAService
+		TokenService service.ITokenService
+	}
+)
+
+const (
+	ErrInvalidMFAToken = "MFA token is invalid or expired, please log in again"
+	ErrInvalidMFACode  = "invalid authentication code, please log in again"
+)
+
+func NewMFAHandler(mfaService service.IMFAService, tokenService service.ITokenService) *MFAHandler {
+	return &MFAHandler{MFAService: mfaService, TokenService: tokenService}
+}
+
+/*
+	Handler functions
+*/
+
+func (h *MFAHandler) EnrollTOTP(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Generate a new secret, it is enabled by ConfirmTOTP
+	secret, uri, err := h.MFAService.EnrollTOTP(ctx)
+	if err != nil {
+		handleMFAError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, &model.TOTPEnrollmentResponse{Secret: secret, URI: uri})
+}
+
+func (h *MFAHandler) ConfirmTOTP(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Bind the JSON body to the code request
+	var req model.TOTPCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errMsg := handleValidationError(err)
+		c.JSON(http.StatusBadRequest, &model.Response{Messages: errMsg})
+		return
+	}
+
+	// Enable two-factor authentication, and hand out the recovery codes
+	codes, err := h.MFAService.ConfirmTOTP(ctx, req.Code)
+	if err != nil {
+		handleMFAError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, &model.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+func (h *MFAHandler) DisableTOTP(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Bind the JSON body to the code request
+	var req model.TOTPCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errMsg := handleValidationError(err)
+		c.JSON(http.StatusBadRequest, &model.Response{Messages: errMsg})
+		return
+	}
+
+	if err := h.MFAService.DisableTOTP(ctx, req.Code); err != nil {
+		handleMFAError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, &model.Response{Message: "Two-factor authentication disabled"})
+}
+
+func (h *MFAHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Bind the JSON body to the code request
+	var req model.TOTPCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errMsg := handleValidationError(err)
+		c.JSON(http.StatusBadRequest, &model.Response{Messages: errMsg})
+		return
+	}
+
+	codes, err := h.MFAService.RegenerateRecoveryCodes(ctx, req.Code)
+	if err != nil {
+		handleMFAError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, &model.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// LoginMFA is the second step of a login with two factors. It exchanges
+// the challenge of the password step and a TOTP or recovery code for the
+// tokens.
+func (h *MFAHandler) LoginMFA(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Bind the JSON body to the second step request
+	var req model.MFALoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errMsg := handleValidationError(err)
+		c.JSON(http.StatusBadRequest, &model.Response{Messages: errMsg})
+		return
+	}
+
+	claims, err := auth.ParseMFAChallengeToken(req.MFAToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &model.Response{Message: ErrInvalidMFAToken})
+		return
+	}
+
+	// A challenge takes a single attempt, a wrong code starts the login
+	// over, which keeps the codes from being guessed
+	if err := h.TokenService.ConsumeToken(ctx, claims.Id, time.Unix(claims.ExpiresAt, 0)); err != nil {
+		if errors.Is(err, service.ErrTokenAlreadyUsed) {
+			c.JSON(http.StatusUnauthorized, &model.Response{Message: ErrInvalidMFAToken})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	user, err := h.MFAService.VerifySecondFactor(ctx, uuid.FromStringOrNil(claims.Subject), req.Code, req.RecoveryCode)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidMFACode) || errors.Is(err, service.ErrTOTPNotEnrolled) {
+			c.JSON(http.StatusUnauthorized, &model.Response{Message: ErrInvalidMFACode})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	issueTokens(c, h.TokenService, user)
+}
+
+/*
+	Suporting functions
+*/
+
+// handleMFAError writes the response of a failed enrollment operation
+func handleMFAError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrUnauthenticated):
+		c.JSON(http.StatusUnauthorized, &model.Response{Message: http.StatusText(http.StatusUnauthorized)})
+	case errors.Is(err, service.ErrPersonalAccessTokenNotAllowed):
+		c.JSON(http.StatusForbidden, &model.Response{Code: "forbidden", Message: "personal access tokens cannot manage two-factor authentication"})
+	case errors.Is(err, service.ErrTOTPAlreadyEnabled), errors.Is(err, service.ErrTOTPNotEnrolled):
+		c.JSON(http.StatusConflict, &model.Response{Message: err.Error()})
+	case errors.Is(err, service.ErrInvalidMFACode):
+		c.JSON(http.StatusBadRequest, &model.Response{Message: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+	}
+}
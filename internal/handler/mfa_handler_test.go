@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"task-manager/internal/auth"
+	"task-manager/internal/mocks"
+	"task-manager/internal/model"
+	"task-manager/internal/service"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_EnrollTOTP(t *testing.T) {
+	mfaService := new(mocks.IMFAService)
+	tokenService := new(mocks.ITokenService)
+	mfaHandler := NewMFAHandler(mfaService, tokenService)
+
+	// Test case 1
+	t.Run("EnrollTOTP: already enabled", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/auth/mfa/totp", nil)
+
+		mfaService.On("EnrollTOTP", mock.Anything).
+			Return("", "", service.ErrTOTPAlreadyEnabled).Once()
+
+		mfaHandler.EnrollTOTP(c)
+
+		require.Equal(t, http.StatusConflict, w.Code)
+		require.Equal(t, `{"message":"two-factor authentication is already enabled"}`, w.Body.String())
+	})
+
+	// Test case 2
+	t.Run("EnrollTOTP: success", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/auth/mfa/totp", nil)
+
+		mfaService.On("EnrollTOTP", mock.Anything).
+			Return("SECRET", "otpauth://totp/task-manager:user1?secret=SECRET", nil).Once()
+
+		mfaHandler.EnrollTOTP(c)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		require.Equal(t, `{"secret":"SECRET","otpauth_uri":"otpauth://totp/task-manager:user1?secret=SECRET"}`, w.Body.String())
+	})
+
+	// Test case 3
+	t.Run("ConfirmTOTP: invalid code format", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/auth/mfa/totp/confirm", model.TOTPCodeRequest{Code: "12ab"})
+
+		mfaHandler.ConfirmTOTP(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	// Test case 4
+	t.Run("ConfirmTOTP: success", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/auth/mfa/totp/confirm", model.TOTPCodeRequest{Code: "123456"})
+
+		mfaService.On("ConfirmTOTP", mock.Anything, "123456").
+			Return([]string{"aaaaa-bbbbb", "ccccc-ddddd"}, nil).Once()
+
+		mfaHandler.ConfirmTOTP(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, `{"recovery_codes":["aaaaa-bbbbb","ccccc-ddddd"]}`, w.Body.String())
+	})
+}
+
+func Test_LoginMFA(t *testing.T) {
+	mfaService := new(mocks.IMFAService)
+	tokenService := new(mocks.ITokenService)
+	mfaHandler := NewMFAHandler(mfaService, tokenService)
+
+	// Test case 1
+	t.Run("LoginMFA: access token instead of a challenge", func(t *testing.T) {
+		accessToken, err := auth.GenerateToken(&model.User{ID: uuid1, Username: "user1"})
+		require.Nil(t, err)
+		c, w := newJSONContext(t, http.MethodPost, "/auth/login/mfa", model.MFALoginRequest{MFAToken: accessToken, Code: "123456"})
+
+		mfaHandler.LoginMFA(c)
+
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+		require.Equal(t, `{"message":"MFA token is invalid or expired, please log in again"}`, w.Body.String())
+	})
+
+	// Test case 2
+	t.Run("LoginMFA: challenge already used", func(t *testing.T) {
+		mfaToken, _, err := auth.GenerateMFAChallengeToken(uuid1)
+		require.Nil(t, err)
+		c, w := newJSONContext(t, http.MethodPost, "/auth/login/mfa", model.MFALoginRequest{MFAToken: mfaToken, Code: "123456"})
+
+		tokenService.On("ConsumeToken", mock.Anything, mock.Anything, mock.Anything).Return(service.ErrTokenAlreadyUsed).Once()
+
+		mfaHandler.LoginMFA(c)
+
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+		require.Equal(t, `{"message":"MFA token is invalid or expired, please log in again"}`, w.Body.String())
+	})
+
+	// Test case 3
+	t.Run("LoginMFA: wrong code", func(t *testing.T) {
+		mfaToken, _, err := auth.GenerateMFAChallengeToken(uuid1)
+		require.Nil(t, err)
+		c, w := newJSONContext(t, http.MethodPost, "/auth/login/mfa", model.MFALoginRequest{MFAToken: mfaToken, Code: "000000"})
+
+		tokenService.On("ConsumeToken", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+		mfaService.On("VerifySecondFactor", mock.Anything, uuid1, "000000", "").
+			Return(nil, service.ErrInvalidMFACode).Once()
+
+		mfaHandler.LoginMFA(c)
+
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+		require.Equal(t, `{"message":"invalid authentication code, please log in again"}`, w.Body.String())
+	})
+
+	// Test case 4
+	t.Run("LoginMFA: success with a recovery code", func(t *testing.T) {
+		mfaToken, _, err := auth.GenerateMFAChallengeToken(uuid1)
+		require.Nil(t, err)
+		c, w := newJSONContext(t, http.MethodPost, "/auth/login/mfa", model.MFALoginRequest{MFAToken: mfaToken, RecoveryCode: "aaaaa-bbbbb"})
+
+		user := &model.User{ID: uuid1, Username: "user1", Role: auth.RoleAdmin, TOTPEnabled: true}
+		tokenService.On("ConsumeToken", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+		mfaService.On("VerifySecondFactor", mock.Anything, uuid1, "", "aaaaa-bbbbb").
+			Return(user, nil).Once()
+		tokenService.On("IssueRefreshToken", mock.Anything, uuid1).Return("refresh-1", nil).Once()
+
+		mfaHandler.LoginMFA(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp model.TokenResponse
+		require.Nil(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+		claims, err := auth.ParseToken(resp.AccessToken)
+		require.Nil(t, err)
+		require.True(t, auth.NewPrincipal(claims).Can(auth.PermUsersManage))
+	})
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"task-manager/internal/model"
+	"task-manager/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+type (
+	INotificationHandler interface {
+		GetNotifications(*gin.Context)
+		MarkNotificationRead(*gin.Context)
+	}
+
+	NotificationHandler struct {
+		NotificationService service.INotificationService
+	}
+)
+
+func NewNotificationHandler(notificationService service.INotificationService) *NotificationHandler {
+	return &NotificationHandler{NotificationService: notificationService}
+}
+
+/*
+	Handler functions
+*/
+
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Bind the query to the notification filter
+	var filter model.NotificationFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: ErrInvalidQuery})
+		return
+	}
+
+	// Fetch the inbox of the caller
+	notifications, err := h.NotificationService.GetNotifications(ctx, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	c.JSON(http.StatusOK, notifications)
+}
+
+func (h *NotificationHandler) MarkNotificationRead(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the notification ID
+	notificationId, err := uuid.FromString(c.Param("notificationId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Mark the notification of the caller as read
+	notification, err := h.NotificationService.MarkRead(ctx, notificationId)
+	switch {
+	case errors.Is(err, service.ErrNotificationNotFound):
+		c.JSON(http.StatusNotFound, &model.Response{Message: err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	c.JSON(http.StatusOK, notification)
+}
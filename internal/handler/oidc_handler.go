@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+	"task-manager/internal/auth"
+	"task-manager/internal/model"
+	"task-manager/internal/oidc"
+	"task-manager/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	IOIDCHandler interface {
+		Login(*gin.Context)
+		Callback(*gin.Context)
+	}
+
+	OIDCHandler struct {
+		Provider     *oidc.Provider
+		UserService  service.IUserService
+		TokenService service.ITokenService
+	}
+)
+
+const (
+	ErrOIDCInvalidState = "login state is invalid or expired, please start over"
+	ErrOIDCLoginFailed  = "login with the identity provider failed"
+)
+
+// oidcStateCookie carries the signed state of a login from the redirect
+// to the provider until the callback
+const oidcStateCookie = "oidc_state"
+
+func NewOIDCHandler(provider *oidc.Provider, userService service.IUserService, tokenService service.ITokenService) *OIDCHandler {
+	return &OIDCHandler{Provider: provider, UserService: userService, TokenService: tokenService}
+}
+
+/*
+	Handler functions
+*/
+
+// Login redirects the user to the provider. The state, the nonce and the
+// PKCE verifier of the login are kept in a signed cookie.
+func (h *OIDCHandler) Login(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	state, err := auth.GenerateOpaqueToken(16)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	nonce, err := auth.GenerateOpaqueToken(16)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	verifier, err := oidc.GenerateCodeVerifier()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	stateToken, err := auth.GenerateOIDCStateToken(state, nonce, verifier)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	// The provider is discovered on first use, it may be unreachable
+	authURL, err := h.Provider.AuthCodeURL(ctx, state, nonce, oidc.CodeChallengeS256(verifier))
+	if err != nil {
+		c.JSON(http.StatusBadGateway, &model.Response{Message: ErrOIDCLoginFailed})
+		return
+	}
+
+	h.setStateCookie(c, stateToken, int(auth.OIDCStateTTL.Seconds()))
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// Callback completes the login at the provider: it checks the state,
+// redeems the code, verifies the ID token and issues the tokens of this
+// service. Users with two-factor authentication still have to pass it.
+func (h *OIDCHandler) Callback(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// The state cookie is single use
+	stateToken, _ := c.Cookie(oidcStateCookie)
+	h.setStateCookie(c, "", -1)
+
+	if e := c.Query("error"); e != "" {
+		c.JSON(http.StatusUnauthorized, &model.Response{Code: e, Message: ErrOIDCLoginFailed})
+		return
+	}
+
+	loginState, err := auth.ParseOIDCStateToken(stateToken)
+	if err != nil || subtle.ConstantTimeCompare([]byte(loginState.State), []byte(c.Query("state"))) != 1 {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: ErrOIDCInvalidState})
+		return
+	}
+
+	// Redeem the code and verify the ID token
+	idToken, err := h.Provider.Exchange(ctx, c.Query("code"), loginState.CodeVerifier)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &model.Response{Message: ErrOIDCLoginFailed})
+		return
+	}
+	claims, err := h.Provider.VerifyIDToken(ctx, idToken, loginState.Nonce)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &model.Response{Message: ErrOIDCLoginFailed})
+		return
+	}
+
+	// Map the account of the provider to a local user
+	user, err := h.UserService.ProvisionExternalUser(ctx, &model.ExternalProfile{
+		Issuer:        claims.Issuer,
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Username:      claims.PreferredUsername,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrEmailRequired):
+			c.JSON(http.StatusForbidden, &model.Response{Message: err.Error()})
+		case errors.Is(err, service.ErrUserAlreadyExists):
+			c.JSON(http.StatusConflict, &model.Response{Message: ErrUserAlreadyExists})
+		default:
+			c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+		}
+		return
+	}
+
+	// Issue the tokens, or the challenge of the second step
+	completeLogin(c, h.TokenService, user)
+}
+
+/*
+	Suporting functions
+*/
+
+func (h *OIDCHandler) setStateCookie(c *gin.Context, value string, maxAge int) {
+	secure := c.Request.TLS != nil || strings.HasPrefix(h.Provider.Config.RedirectURL, "https://")
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, value, maxAge, "/auth/oidc", "", secure, true)
+}
@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"task-manager/internal/mocks"
+	"task-manager/internal/model"
+	"task-manager/internal/oidc"
+	"task-manager/internal/oidc/oidctest"
+	"task-manager/internal/service"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newOIDCRouter(t *testing.T, userService *mocks.IUserService, tokenService *mocks.ITokenService) (*gin.Engine, *oidctest.Server) {
+	idp, err := oidctest.NewServer("task-manager", "client-secret")
+	require.Nil(t, err)
+	t.Cleanup(idp.Close)
+
+	provider := oidc.NewProvider(idp.Config("http://localhost:8080/auth/oidc/callback"))
+	oidcHandler := NewOIDCHandler(provider, userService, tokenService)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/auth/oidc/login", oidcHandler.Login)
+	router.GET("/auth/oidc/callback", oidcHandler.Callback)
+	return router, idp
+}
+
+// startOIDCLogin runs the login up to the redirect back from the provider,
+// and returns the state cookie with the callback query
+func startOIDCLogin(t *testing.T, router *gin.Engine, idp *oidctest.Server) (*http.Cookie, url.Values) {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/auth/oidc/login", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusFound, w.Code)
+
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	require.True(t, cookies[0].HttpOnly)
+
+	code, state, err := idp.Login(w.Header().Get("Location"))
+	require.Nil(t, err)
+	return cookies[0], url.Values{"code": {code}, "state": {state}}
+}
+
+func callback(router *gin.Engine, cookie *http.Cookie, query url.Values) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/auth/oidc/callback?"+query.Encode(), nil)
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func Test_OIDCLogin(t *testing.T) {
+	userService := new(mocks.IUserService)
+	tokenService := new(mocks.ITokenService)
+	router, idp := newOIDCRouter(t, userService, tokenService)
+
+	// Test case 1
+	t.Run("OIDC: state mismatch", func(t *testing.T) {
+		cookie, query := startOIDCLogin(t, router, idp)
+		query.Set("state", "forged")
+
+		w := callback(router, cookie, query)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Equal(t, `{"message":"login state is invalid or expired, please start over"}`, w.Body.String())
+	})
+
+	// Test case 2
+	t.Run("OIDC: missing state cookie", func(t *testing.T) {
+		_, query := startOIDCLogin(t, router, idp)
+
+		w := callback(router, nil, query)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	// Test case 3
+	t.Run("OIDC: denied at the provider", func(t *testing.T) {
+		cookie, _ := startOIDCLogin(t, router, idp)
+
+		w := callback(router, cookie, url.Values{"error": {"access_denied"}})
+
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+		require.Equal(t, `{"code":"access_denied","message":"login with the identity provider failed"}`, w.Body.String())
+	})
+
+	// Test case 4
+	t.Run("OIDC: no email shared", func(t *testing.T) {
+		idp.SetUser(map[string]interface{}{"sub": "idp-7"})
+		cookie, query := startOIDCLogin(t, router, idp)
+
+		userService.On("ProvisionExternalUser", mock.Anything, mock.MatchedBy(func(p *model.ExternalProfile) bool {
+			return p.Subject == "idp-7"
+		})).Return(nil, service.ErrEmailRequired).Once()
+
+		w := callback(router, cookie, query)
+
+		require.Equal(t, http.StatusForbidden, w.Code)
+		require.Equal(t, `{"message":"the identity provider did not share an email address"}`, w.Body.String())
+	})
+
+	// Test case 5
+	t.Run("OIDC: success", func(t *testing.T) {
+		idp.SetUser(map[string]interface{}{
+			"sub":                "idp-42",
+			"email":              "alice@example.com",
+			"email_verified":     true,
+			"preferred_username": "alice",
+		})
+		cookie, query := startOIDCLogin(t, router, idp)
+
+		user := &model.User{ID: uuid1, Username: "alice", Email: "alice@example.com", Role: "member"}
+		userService.On("ProvisionExternalUser", mock.Anything, &model.ExternalProfile{
+			Issuer:        idp.URL,
+			Subject:       "idp-42",
+			Email:         "alice@example.com",
+			EmailVerified: true,
+			Username:      "alice",
+		}).Return(user, nil).Once()
+		tokenService.On("IssueRefreshToken", mock.Anything, uuid1).Return("refresh-token", nil).Once()
+
+		w := callback(router, cookie, query)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), `"token_type":"Bearer"`)
+		require.Contains(t, w.Body.String(), `"refresh_token":"refresh-token"`)
+
+		// The code cannot be redeemed twice
+		w = callback(router, cookie, query)
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
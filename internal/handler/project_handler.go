@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"task-manager/internal/model"
+	"task-manager/internal/service"
+	"task-manager/internal/workflow"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+type (
+	IProjectHandler interface {
+		GetProjects(*gin.Context)
+		CreateProject(*gin.Context)
+		GetProjectByID(*gin.Context)
+		UpdateWorkflow(*gin.Context)
+		DeleteProject(*gin.Context)
+	}
+
+	ProjectHandler struct {
+		ProjectService service.IProjectService
+	}
+)
+
+const (
+	ErrProjectNotFound = "project not found"
+)
+
+func NewProjectHandler(projectService service.IProjectService) *ProjectHandler {
+	return &ProjectHandler{ProjectService: projectService}
+}
+
+/*
+	Handler functions
+*/
+
+func (h *ProjectHandler) GetProjects(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Fetch the projects of the tenant
+	projects, err := h.ProjectService.GetProjects(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	c.JSON(http.StatusOK, projects)
+}
+
+func (h *ProjectHandler) CreateProject(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Bind the JSON body to the project request
+	var req model.CreateProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errMsg := handleValidationError(err)
+		c.JSON(http.StatusBadRequest, &model.Response{Messages: errMsg})
+		return
+	}
+
+	// Create the project, with the default workflow unless one is given
+	project := model.Project{Name: req.Name}
+	if req.Workflow != nil {
+		project.Workflow = *req.Workflow
+	}
+	if err := h.ProjectService.CreateProject(ctx, &project); err != nil {
+		handleProjectError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, project)
+}
+
+func (h *ProjectHandler) GetProjectByID(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the project ID
+	projectId, err := uuid.FromString(c.Param("projectId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Fetch the project from the database
+	project, err := h.ProjectService.GetProjectByID(ctx, projectId)
+	if err != nil {
+		handleProjectError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, project)
+}
+
+func (h *ProjectHandler) UpdateWorkflow(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the project ID
+	projectId, err := uuid.FromString(c.Param("projectId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Bind the JSON body to the workflow
+	var w model.Workflow
+	if err := c.ShouldBindJSON(&w); err != nil {
+		errMsg := handleValidationError(err)
+		c.JSON(http.StatusBadRequest, &model.Response{Messages: errMsg})
+		return
+	}
+
+	// Replace the workflow, the tasks keep their statuses
+	project, err := h.ProjectService.UpdateWorkflow(ctx, projectId, w)
+	if err != nil {
+		handleProjectError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, project)
+}
+
+func (h *ProjectHandler) DeleteProject(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the project ID
+	projectId, err := uuid.FromString(c.Param("projectId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Delete the project once its tasks are gone
+	if err := h.ProjectService.DeleteProject(ctx, projectId); err != nil {
+		handleProjectError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, &model.Response{Message: "Project deleted successfully"})
+}
+
+/*
+	Suporting functions
+*/
+
+// handleProjectError writes the response of a failed project operation
+func handleProjectError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, workflow.ErrInvalidWorkflow):
+		c.JSON(http.StatusBadRequest, &model.Response{Code: "invalid_workflow", Message: err.Error()})
+	case errors.Is(err, service.ErrProjectHasTasks), errors.Is(err, service.ErrProjectHasRecurrences), errors.Is(err, service.ErrStatusInUse):
+		c.JSON(http.StatusConflict, &model.Response{Message: err.Error()})
+	case strings.EqualFold(err.Error(), "record not found"):
+		c.JSON(http.StatusNotFound, &model.Response{Message: ErrProjectNotFound})
+	default:
+		c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+	}
+}
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"net/http"
+	"task-manager/internal/mocks"
+	"task-manager/internal/model"
+	"task-manager/internal/service"
+	"task-manager/internal/workflow"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var board = model.Workflow{
+	Statuses: []model.WorkflowStatus{
+		{Key: "backlog", Name: "Backlog", Category: model.CategoryTodo},
+		{Key: "review", Name: "Review", Category: model.CategoryActive},
+		{Key: "shipped", Name: "Shipped", Category: model.CategoryDone},
+	},
+	Transitions: []model.WorkflowTransition{{From: "backlog", To: "review"}, {From: "review", To: "shipped"}},
+}
+
+func Test_CreateProject(t *testing.T) {
+	projectService := new(mocks.IProjectService)
+	projectHandler := NewProjectHandler(projectService)
+
+	// Test case 1
+	t.Run("CreateProject: input validation error", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/projects/", model.CreateProjectRequest{})
+
+		projectHandler.CreateProject(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Equal(t, `{"messages":{"name":"this is a required field"}}`, w.Body.String())
+	})
+
+	// Test case 2
+	t.Run("CreateProject: invalid workflow", func(t *testing.T) {
+		invalid := model.Workflow{Statuses: []model.WorkflowStatus{{Key: "backlog", Name: "Backlog", Category: model.CategoryTodo}}}
+		c, w := newJSONContext(t, http.MethodPost, "/projects/", model.CreateProjectRequest{Name: "Launch", Workflow: &invalid})
+
+		_, err := workflow.New(invalid)
+		projectService.On("CreateProject", mock.Anything, mock.AnythingOfType("*model.Project")).
+			Return(err).Once()
+
+		projectHandler.CreateProject(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Equal(t, `{"code":"invalid_workflow","message":"invalid workflow: a todo and a done status are required"}`, w.Body.String())
+	})
+
+	// Test case 3
+	t.Run("CreateProject: success", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/projects/", model.CreateProjectRequest{Name: "Launch", Workflow: &board})
+
+		projectService.On("CreateProject", mock.Anything, mock.MatchedBy(func(project *model.Project) bool {
+			return project.Name == "Launch" && len(project.Workflow.Statuses) == 3
+		})).Return(nil).Once()
+
+		projectHandler.CreateProject(c)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		require.Contains(t, w.Body.String(), `"key":"review","name":"Review","category":"active"`)
+	})
+}
+
+func Test_UpdateWorkflow(t *testing.T) {
+	projectService := new(mocks.IProjectService)
+	projectHandler := NewProjectHandler(projectService)
+
+	// Test case 1
+	t.Run("UpdateWorkflow: invalid category", func(t *testing.T) {
+		invalid := model.Workflow{Statuses: []model.WorkflowStatus{{Key: "backlog", Name: "Backlog", Category: "later"}}}
+		c, w := newJSONContext(t, http.MethodPut, "/projects/"+uuid1.String()+"/workflow", invalid)
+		c.Params = append(c.Params, gin.Param{Key: "projectId", Value: uuid1.String()})
+
+		projectHandler.UpdateWorkflow(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Equal(t, `{"messages":{"category":"it must be one of the following [todo, active, done]"}}`, w.Body.String())
+	})
+
+	// Test case 2
+	t.Run("UpdateWorkflow: status in use", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPut, "/projects/"+uuid1.String()+"/workflow", board)
+		c.Params = append(c.Params, gin.Param{Key: "projectId", Value: uuid1.String()})
+
+		projectService.On("UpdateWorkflow", mock.Anything, uuid1, mock.AnythingOfType("model.Workflow")).
+			Return(nil, service.ErrStatusInUse).Once()
+
+		projectHandler.UpdateWorkflow(c)
+
+		require.Equal(t, http.StatusConflict, w.Code)
+		require.Equal(t, `{"message":"statuses still used by tasks cannot be removed"}`, w.Body.String())
+	})
+
+	// Test case 3
+	t.Run("UpdateWorkflow: not found", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPut, "/projects/"+uuid1.String()+"/workflow", board)
+		c.Params = append(c.Params, gin.Param{Key: "projectId", Value: uuid1.String()})
+
+		projectService.On("UpdateWorkflow", mock.Anything, uuid1, mock.AnythingOfType("model.Workflow")).
+			Return(nil, errMockNotFound).Once()
+
+		projectHandler.UpdateWorkflow(c)
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+		require.Equal(t, `{"message":"project not found"}`, w.Body.String())
+	})
+}
+
+func Test_DeleteProject(t *testing.T) {
+	projectService := new(mocks.IProjectService)
+	projectHandler := NewProjectHandler(projectService)
+
+	// Test case 1
+	t.Run("DeleteProject: has tasks", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodDelete, "/projects/"+uuid1.String(), nil)
+		c.Params = append(c.Params, gin.Param{Key: "projectId", Value: uuid1.String()})
+
+		projectService.On("DeleteProject", mock.Anything, uuid1).
+			Return(service.ErrProjectHasTasks).Once()
+
+		projectHandler.DeleteProject(c)
+
+		require.Equal(t, http.StatusConflict, w.Code)
+		require.Equal(t, `{"message":"project still has tasks"}`, w.Body.String())
+	})
+}
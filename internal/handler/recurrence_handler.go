@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"task-manager/internal/model"
+	"task-manager/internal/recurrence"
+	"task-manager/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+type (
+	IRecurrenceHandler interface {
+		GetRecurrences(*gin.Context)
+		CreateRecurrence(*gin.Context)
+		GetRecurrenceByID(*gin.Context)
+		UpdateRecurrence(*gin.Context)
+		SkipOccurrence(*gin.Context)
+		DeleteRecurrence(*gin.Context)
+	}
+
+	RecurrenceHandler struct {
+		RecurrenceService service.IRecurrenceService
+	}
+)
+
+func NewRecurrenceHandler(recurrenceService service.IRecurrenceService) *RecurrenceHandler {
+	return &RecurrenceHandler{RecurrenceService: recurrenceService}
+}
+
+/*
+	Handler functions
+*/
+
+func (h *RecurrenceHandler) GetRecurrences(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Fetch the recurring tasks of the tenant
+	recurrences, err := h.RecurrenceService.GetRecurrences(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	c.JSON(http.StatusOK, recurrences)
+}
+
+func (h *RecurrenceHandler) CreateRecurrence(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Bind the JSON body to the recurrence request
+	var req model.RecurrenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errMsg := handleValidationError(err)
+		c.JSON(http.StatusBadRequest, &model.Response{Messages: errMsg})
+		return
+	}
+
+	// Create the template, its tasks are created by the scheduler
+	r := newRecurrence(req)
+	if err := h.RecurrenceService.CreateRecurrence(ctx, &r); err != nil {
+		handleRecurrenceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, r)
+}
+
+func (h *RecurrenceHandler) GetRecurrenceByID(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the recurrence ID
+	recurrenceId, err := uuid.FromString(c.Param("recurrenceId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Fetch the recurring task from the database
+	r, err := h.RecurrenceService.GetRecurrenceByID(ctx, recurrenceId)
+	if err != nil {
+		handleRecurrenceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, r)
+}
+
+func (h *RecurrenceHandler) UpdateRecurrence(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the recurrence ID
+	recurrenceId, err := uuid.FromString(c.Param("recurrenceId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Bind the JSON body to the recurrence request
+	var req model.RecurrenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errMsg := handleValidationError(err)
+		c.JSON(http.StatusBadRequest, &model.Response{Messages: errMsg})
+		return
+	}
+
+	// Replace the template, the tasks already created are kept
+	r := newRecurrence(req)
+	if err := h.RecurrenceService.UpdateRecurrence(ctx, recurrenceId, &r); err != nil {
+		handleRecurrenceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, r)
+}
+
+func (h *RecurrenceHandler) SkipOccurrence(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the recurrence ID
+	recurrenceId, err := uuid.FromString(c.Param("recurrenceId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Bind the JSON body to the skip request
+	var req model.SkipOccurrenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errMsg := handleValidationError(err)
+		c.JSON(http.StatusBadRequest, &model.Response{Messages: errMsg})
+		return
+	}
+
+	// Add the date to the exception dates of the template
+	r, err := h.RecurrenceService.SkipOccurrence(ctx, recurrenceId, req.Date)
+	if err != nil {
+		handleRecurrenceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, r)
+}
+
+func (h *RecurrenceHandler) DeleteRecurrence(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the recurrence ID
+	recurrenceId, err := uuid.FromString(c.Param("recurrenceId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Stop the recurring task, its tasks are kept
+	if err := h.RecurrenceService.DeleteRecurrence(ctx, recurrenceId); err != nil {
+		handleRecurrenceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, &model.Response{Message: "Recurring task deleted successfully"})
+}
+
+/*
+	Suporting functions
+*/
+
+// newRecurrence returns the template described by the request, in UTC and
+// with a medium priority by default
+func newRecurrence(req model.RecurrenceRequest) model.Recurrence {
+	r := model.Recurrence{
+		Title:       req.Title,
+		Description: req.Description,
+		Priority:    req.Priority,
+		ProjectID:   req.ProjectID,
+		RRule:       req.RRule,
+		Timezone:    req.Timezone,
+		StartAt:     req.StartAt,
+		ExceptDates: model.DateList(req.ExceptDates),
+	}
+	if r.Priority == "" {
+		r.Priority = model.PriorityMedium
+	}
+	if r.Timezone == "" {
+		r.Timezone = "UTC"
+	}
+	return r
+}
+
+// handleRecurrenceError writes the response of a failed recurring task
+// operation
+func handleRecurrenceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, recurrence.ErrInvalidRule):
+		c.JSON(http.StatusBadRequest, &model.Response{Code: "invalid_rrule", Message: err.Error()})
+	case errors.Is(err, service.ErrScheduleEnded):
+		c.JSON(http.StatusBadRequest, &model.Response{Code: "schedule_ended", Message: err.Error()})
+	case errors.Is(err, service.ErrProjectNotFound):
+		c.JSON(http.StatusBadRequest, &model.Response{Code: "invalid_project", Message: err.Error()})
+	case errors.Is(err, service.ErrRecurrenceNotFound):
+		c.JSON(http.StatusNotFound, &model.Response{Message: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+	}
+}
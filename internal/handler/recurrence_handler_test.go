@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"net/http"
+	"task-manager/internal/mocks"
+	"task-manager/internal/model"
+	"task-manager/internal/recurrence"
+	"task-manager/internal/service"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var weeklyReport = model.RecurrenceRequest{
+	Title:       "Weekly report",
+	Description: "Send the weekly report",
+	RRule:       "FREQ=WEEKLY;BYDAY=FR",
+	Timezone:    "Europe/Berlin",
+	StartAt:     time.Date(2026, 3, 6, 16, 0, 0, 0, time.UTC),
+}
+
+func Test_CreateRecurrence(t *testing.T) {
+	recurrenceService := new(mocks.IRecurrenceService)
+	recurrenceHandler := NewRecurrenceHandler(recurrenceService)
+
+	// Test case 1
+	t.Run("CreateRecurrence: input validation error", func(t *testing.T) {
+		invalid := weeklyReport
+		invalid.Timezone = "Mars/Olympus"
+		invalid.ExceptDates = []string{"06/03/2026"}
+		c, w := newJSONContext(t, http.MethodPost, "/recurrences/", invalid)
+
+		recurrenceHandler.CreateRecurrence(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Equal(t, `{"messages":{"exceptdates[0]":"it must be formatted as 2006-01-02","timezone":"it must be an IANA timezone, e.g. Europe/Berlin"}}`, w.Body.String())
+	})
+
+	// Test case 2
+	t.Run("CreateRecurrence: invalid rule", func(t *testing.T) {
+		invalid := weeklyReport
+		invalid.RRule = "FREQ=HOURLY"
+		c, w := newJSONContext(t, http.MethodPost, "/recurrences/", invalid)
+
+		_, err := recurrence.Parse(invalid.RRule)
+		recurrenceService.On("CreateRecurrence", mock.Anything, mock.AnythingOfType("*model.Recurrence")).
+			Return(err).Once()
+
+		recurrenceHandler.CreateRecurrence(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Equal(t, `{"code":"invalid_rrule","message":"invalid recurrence rule: FREQ=HOURLY is not supported"}`, w.Body.String())
+	})
+
+	// Test case 3
+	t.Run("CreateRecurrence: schedule ended", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/recurrences/", weeklyReport)
+
+		recurrenceService.On("CreateRecurrence", mock.Anything, mock.AnythingOfType("*model.Recurrence")).
+			Return(service.ErrScheduleEnded).Once()
+
+		recurrenceHandler.CreateRecurrence(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Equal(t, `{"code":"schedule_ended","message":"the rule has no occurrence from now on"}`, w.Body.String())
+	})
+
+	// Test case 4
+	t.Run("CreateRecurrence: success", func(t *testing.T) {
+		req := weeklyReport
+		req.Timezone = ""
+		c, w := newJSONContext(t, http.MethodPost, "/recurrences/", req)
+
+		recurrenceService.On("CreateRecurrence", mock.Anything, mock.MatchedBy(func(r *model.Recurrence) bool {
+			return r.Title == "Weekly report" && r.Timezone == "UTC" && r.Priority == model.PriorityMedium
+		})).Return(nil).Once()
+
+		recurrenceHandler.CreateRecurrence(c)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		require.Contains(t, w.Body.String(), `"rrule":"FREQ=WEEKLY;BYDAY=FR","timezone":"UTC"`)
+	})
+}
+
+func Test_SkipOccurrence(t *testing.T) {
+	recurrenceService := new(mocks.IRecurrenceService)
+	recurrenceHandler := NewRecurrenceHandler(recurrenceService)
+
+	// Test case 1
+	t.Run("SkipOccurrence: invalid date", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/recurrences/"+uuid1.String()+"/skip", model.SkipOccurrenceRequest{Date: "2026-13-01"})
+		c.Params = append(c.Params, gin.Param{Key: "recurrenceId", Value: uuid1.String()})
+
+		recurrenceHandler.SkipOccurrence(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Equal(t, `{"messages":{"date":"it must be formatted as 2006-01-02"}}`, w.Body.String())
+	})
+
+	// Test case 2
+	t.Run("SkipOccurrence: not found", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/recurrences/"+uuid1.String()+"/skip", model.SkipOccurrenceRequest{Date: "2026-12-25"})
+		c.Params = append(c.Params, gin.Param{Key: "recurrenceId", Value: uuid1.String()})
+
+		recurrenceService.On("SkipOccurrence", mock.Anything, uuid1, "2026-12-25").
+			Return(nil, service.ErrRecurrenceNotFound).Once()
+
+		recurrenceHandler.SkipOccurrence(c)
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+		require.Equal(t, `{"message":"recurring task not found"}`, w.Body.String())
+	})
+
+	// Test case 3
+	t.Run("SkipOccurrence: success", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/recurrences/"+uuid1.String()+"/skip", model.SkipOccurrenceRequest{Date: "2026-12-25"})
+		c.Params = append(c.Params, gin.Param{Key: "recurrenceId", Value: uuid1.String()})
+
+		recurrenceService.On("SkipOccurrence", mock.Anything, uuid1, "2026-12-25").
+			Return(&model.Recurrence{ID: uuid1, ExceptDates: model.DateList{"2026-12-25"}}, nil).Once()
+
+		recurrenceHandler.SkipOccurrence(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), `"except_dates":["2026-12-25"]`)
+	})
+}
+
+func Test_DeleteRecurrence(t *testing.T) {
+	recurrenceService := new(mocks.IRecurrenceService)
+	recurrenceHandler := NewRecurrenceHandler(recurrenceService)
+
+	// Test case 1
+	t.Run("DeleteRecurrence: invalid ID", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodDelete, "/recurrences/abc", nil)
+		c.Params = append(c.Params, gin.Param{Key: "recurrenceId", Value: "abc"})
+
+		recurrenceHandler.DeleteRecurrence(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	// Test case 2
+	t.Run("DeleteRecurrence: success", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodDelete, "/recurrences/"+uuid1.String(), nil)
+		c.Params = append(c.Params, gin.Param{Key: "recurrenceId", Value: uuid1.String()})
+
+		recurrenceService.On("DeleteRecurrence", mock.Anything, uuid1).Return(nil).Once()
+
+		recurrenceHandler.DeleteRecurrence(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, `{"message":"Recurring task deleted successfully"}`, w.Body.String())
+	})
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"task-manager/internal/model"
+	"task-manager/internal/notify"
+	"task-manager/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+type (
+	IReminderHandler interface {
+		GetReminders(*gin.Context)
+		CreateReminder(*gin.Context)
+		DeleteReminder(*gin.Context)
+	}
+
+	ReminderHandler struct {
+		ReminderService service.IReminderService
+	}
+)
+
+func NewReminderHandler(reminderService service.IReminderService) *ReminderHandler {
+	return &ReminderHandler{ReminderService: reminderService}
+}
+
+/*
+	Handler functions
+*/
+
+func (h *ReminderHandler) GetReminders(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the task ID
+	taskId, err := uuid.FromString(c.Param("taskId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Fetch the reminders of the caller on the task
+	reminders, err := h.ReminderService.GetReminders(ctx, taskId)
+	if err != nil {
+		handleReminderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reminders)
+}
+
+func (h *ReminderHandler) CreateReminder(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the task ID
+	taskId, err := uuid.FromString(c.Param("taskId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Bind the JSON body to the reminder request
+	var req model.ReminderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errMsg := handleValidationError(err)
+		c.JSON(http.StatusBadRequest, &model.Response{Messages: errMsg})
+		return
+	}
+
+	// Remind the caller at the time, or before the due date of the task
+	reminder := model.Reminder{Channel: req.Channel, RemindAt: req.RemindAt, BeforeMinutes: req.BeforeMinutes}
+	if err := h.ReminderService.CreateReminder(ctx, taskId, &reminder); err != nil {
+		handleReminderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, reminder)
+}
+
+func (h *ReminderHandler) DeleteReminder(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the task and reminder IDs
+	taskId, err := uuid.FromString(c.Param("taskId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+	reminderId, err := uuid.FromString(c.Param("reminderId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Delete the reminder of the caller
+	if err := h.ReminderService.DeleteReminder(ctx, taskId, reminderId); err != nil {
+		handleReminderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, &model.Response{Message: "Reminder deleted successfully"})
+}
+
+/*
+	Suporting functions
+*/
+
+// handleReminderError writes the response of a failed reminder operation
+func handleReminderError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrNoDueDate):
+		c.JSON(http.StatusBadRequest, &model.Response{Code: "no_due_date", Message: err.Error()})
+	case errors.Is(err, notify.ErrChannelDisabled):
+		c.JSON(http.StatusBadRequest, &model.Response{Code: "channel_disabled", Message: err.Error()})
+	case errors.Is(err, service.ErrReminderNotFound):
+		c.JSON(http.StatusNotFound, &model.Response{Message: err.Error()})
+	case strings.EqualFold(err.Error(), "record not found"):
+		c.JSON(http.StatusNotFound, &model.Response{Message: ErrTaskNotFound})
+	default:
+		c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+	}
+}
@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"net/http"
+	"task-manager/internal/mocks"
+	"task-manager/internal/model"
+	"task-manager/internal/notify"
+	"task-manager/internal/service"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CreateReminder(t *testing.T) {
+	reminderService := new(mocks.IReminderService)
+	reminderHandler := NewReminderHandler(reminderService)
+	remindAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	oneDay := 1440
+
+	tests := []struct {
+		name string
+		body model.ReminderRequest
+		want string
+	}{
+		{name: "no time", body: model.ReminderRequest{}, want: `{"messages":{"beforeminutes":"this is a required field without remindat","remindat":"this is a required field without beforeminutes"}}`},
+		{name: "both times", body: model.ReminderRequest{RemindAt: &remindAt, BeforeMinutes: &oneDay}, want: `{"messages":{"remindat":"it cannot be given along with beforeminutes"}}`},
+		{name: "unknown channel", body: model.ReminderRequest{Channel: "sms", BeforeMinutes: &oneDay}, want: `{"messages":{"channel":"it must be one of the following [inbox, email, webhook]"}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run("CreateReminder: "+tt.name, func(t *testing.T) {
+			c, w := newJSONContext(t, http.MethodPost, "/tasks/"+uuid1.String()+"/reminders", tt.body)
+			c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+			reminderHandler.CreateReminder(c)
+
+			require.Equal(t, http.StatusBadRequest, w.Code)
+			require.Equal(t, tt.want, w.Body.String())
+		})
+	}
+
+	// Test case 4
+	t.Run("CreateReminder: no due date", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/tasks/"+uuid1.String()+"/reminders", model.ReminderRequest{BeforeMinutes: &oneDay})
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		reminderService.On("CreateReminder", mock.Anything, uuid1, mock.AnythingOfType("*model.Reminder")).
+			Return(service.ErrNoDueDate).Once()
+
+		reminderHandler.CreateReminder(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Equal(t, `{"code":"no_due_date","message":"the task has no due date"}`, w.Body.String())
+	})
+
+	// Test case 5
+	t.Run("CreateReminder: channel disabled", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/tasks/"+uuid1.String()+"/reminders", model.ReminderRequest{Channel: notify.ChannelEmail, RemindAt: &remindAt})
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		reminderService.On("CreateReminder", mock.Anything, uuid1, mock.AnythingOfType("*model.Reminder")).
+			Return(notify.ErrChannelDisabled).Once()
+
+		reminderHandler.CreateReminder(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Equal(t, `{"code":"channel_disabled","message":"notify: the channel is not configured"}`, w.Body.String())
+	})
+
+	// Test case 6
+	t.Run("CreateReminder: task not found", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/tasks/"+uuid1.String()+"/reminders", model.ReminderRequest{RemindAt: &remindAt})
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		reminderService.On("CreateReminder", mock.Anything, uuid1, mock.AnythingOfType("*model.Reminder")).
+			Return(errMockNotFound).Once()
+
+		reminderHandler.CreateReminder(c)
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+		require.Equal(t, `{"message":"task not found"}`, w.Body.String())
+	})
+
+	// Test case 7
+	t.Run("CreateReminder: success", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/tasks/"+uuid1.String()+"/reminders", model.ReminderRequest{BeforeMinutes: &oneDay})
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		reminderService.On("CreateReminder", mock.Anything, uuid1, mock.MatchedBy(func(r *model.Reminder) bool {
+			return r.RemindAt == nil && *r.BeforeMinutes == oneDay
+		})).Return(nil).Once()
+
+		reminderHandler.CreateReminder(c)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		require.Contains(t, w.Body.String(), `"before_minutes":1440`)
+	})
+}
+
+func Test_DeleteReminder(t *testing.T) {
+	reminderService := new(mocks.IReminderService)
+	reminderHandler := NewReminderHandler(reminderService)
+
+	// Test case 1
+	t.Run("DeleteReminder: not found", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodDelete, "/tasks/"+uuid1.String()+"/reminders/"+uuid1.String(), nil)
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()}, gin.Param{Key: "reminderId", Value: uuid1.String()})
+
+		reminderService.On("DeleteReminder", mock.Anything, uuid1, uuid1).
+			Return(service.ErrReminderNotFound).Once()
+
+		reminderHandler.DeleteReminder(c)
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+		require.Equal(t, `{"message":"reminder not found"}`, w.Body.String())
+	})
+}
+
+func Test_MarkNotificationRead(t *testing.T) {
+	notificationService := new(mocks.INotificationService)
+	notificationHandler := NewNotificationHandler(notificationService)
+
+	// Test case 1
+	t.Run("MarkNotificationRead: not found", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/notifications/"+uuid1.String()+"/read", nil)
+		c.Params = append(c.Params, gin.Param{Key: "notificationId", Value: uuid1.String()})
+
+		notificationService.On("MarkRead", mock.Anything, uuid1).
+			Return(nil, service.ErrNotificationNotFound).Once()
+
+		notificationHandler.MarkNotificationRead(c)
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+		require.Equal(t, `{"message":"notification not found"}`, w.Body.String())
+	})
+
+	// Test case 2
+	t.Run("MarkNotificationRead: success", func(t *testing.T) {
+		now := time.Now()
+		c, w := newJSONContext(t, http.MethodPost, "/notifications/"+uuid1.String()+"/read", nil)
+		c.Params = append(c.Params, gin.Param{Key: "notificationId", Value: uuid1.String()})
+
+		notificationService.On("MarkRead", mock.Anything, uuid1).
+			Return(&model.Notification{ID: uuid1, Subject: "Reminder: Ship", ReadAt: &now}, nil).Once()
+
+		notificationHandler.MarkNotificationRead(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), `"subject":"Reminder: Ship"`)
+	})
+}
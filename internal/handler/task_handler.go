@@ -1,13 +1,22 @@
 package handler
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 	"strings"
+	"task-manager/internal/auth"
 	"task-manager/internal/model"
+	"task-manager/internal/patch"
 	"task-manager/internal/service"
+	"task-manager/internal/workflow"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofrs/uuid"
 )
@@ -18,7 +27,29 @@ type (
 		CreateTask(*gin.Context)
 		GetTaskByID(*gin.Context)
 		UpdateTaskByID(*gin.Context)
+		PatchTaskByID(*gin.Context)
 		DeleteTaskByID(*gin.Context)
+		GetTrash(*gin.Context)
+		RestoreTask(*gin.Context)
+		GetTaskHistory(*gin.Context)
+		GetTaskRevision(*gin.Context)
+		RevertTask(*gin.Context)
+		StartTask(*gin.Context)
+		CompleteTask(*gin.Context)
+		ReopenTask(*gin.Context)
+		GetSubtasks(*gin.Context)
+		AddDependency(*gin.Context)
+		RemoveDependency(*gin.Context)
+		GetTaskGrants(*gin.Context)
+		GrantTask(*gin.Context)
+		RevokeTaskGrant(*gin.Context)
+		GetAssignedTasks(*gin.Context)
+		GetTaskAssignees(*gin.Context)
+		GetTaskAssignmentLog(*gin.Context)
+		AssignTask(*gin.Context)
+		UnassignTask(*gin.Context)
+		AttachLabels(*gin.Context)
+		DetachLabel(*gin.Context)
 	}
 
 	TaskHandler struct {
@@ -29,10 +60,16 @@ type (
 const (
 	ErrInvalidJSONBody       = "invalid JSON body"
 	ErrInvalidRequestBody    = "invalid request body"
+	ErrInvalidQuery          = "invalid query parameters"
 	ErrTaskNotFound          = "task not found"
 	ErrTaskAlreadyCompleted  = "task already completed"
 	ErrTaskAlreadyInProgress = "task already in progress"
 	ErrTaskAlreadyPending    = "task already pending"
+	ErrGrantNotFound         = "grant not found"
+	ErrAssigneeNotFound      = "assignee not found"
+	ErrUnsupportedPatch      = "patches must be application/merge-patch+json or application/json-patch+json"
+	ErrIfMatchRequired       = "If-Match header with the ETag of the task is required"
+	ErrVersionMismatch       = "task was changed since it was read, fetch it again and retry"
 )
 
 func NewTaskHandler(taskService service.ITaskService) *TaskHandler {
@@ -46,8 +83,20 @@ func NewTaskHandler(taskService service.ITaskService) *TaskHandler {
 func (h *TaskHandler) GetTasks(c *gin.Context) {
 	ctx := c.Request.Context()
 
-	// Fetch all tasks from the database
-	tasks, err := h.TaskService.GetAllTasks(ctx)
+	// Bind the query to the task filter
+	var filter model.TaskFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		errMsg := handleValidationError(err)
+		if len(errMsg) == 0 {
+			c.JSON(http.StatusBadRequest, &model.Response{Message: ErrInvalidQuery})
+			return
+		}
+		c.JSON(http.StatusBadRequest, &model.Response{Messages: errMsg})
+		return
+	}
+
+	// Fetch the matching tasks from the database
+	tasks, err := h.TaskService.GetAllTasks(ctx, filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
 		return
@@ -67,12 +116,33 @@ func (h *TaskHandler) CreateTask(c *gin.Context) {
 		return
 	}
 
+	// Tasks only reach the trash through DeleteTask
+	task.DeletedAt = nil
+
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, &model.Response{Message: http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	// Validate the status against the workflow of the project
+	if task.Status != "" && !h.validStatus(c, task.ProjectID, task.Status) {
+		return
+	}
+
+	// The caller owns the tasks they create
 	task.ID, _ = uuid.NewV7()
+	task.OwnerID = principal.UserID()
+	task.CreatedBy = principal.UserID()
+	if task.Priority == "" {
+		task.Priority = model.PriorityMedium
+	}
 	if err := h.TaskService.CreateTask(ctx, &task); err != nil {
-		c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+		h.handleTaskError(c, err)
 		return
 	}
 
+	c.Header("ETag", etag(task.Version))
 	c.JSON(http.StatusCreated, task)
 }
 
@@ -100,6 +170,7 @@ func (h *TaskHandler) GetTaskByID(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", etag(task.Version))
 	c.JSON(http.StatusOK, task)
 }
 
@@ -117,7 +188,7 @@ func (h *TaskHandler) UpdateTaskByID(c *gin.Context) {
 	}
 
 	// Fetch the task from the database
-	_, err = h.TaskService.GetTaskByID(ctx, taskId)
+	current, err := h.TaskService.GetTaskByID(ctx, taskId)
 	if err != nil {
 		if strings.EqualFold(err.Error(), "record not found") {
 			c.JSON(http.StatusNotFound, &model.Response{Message: ErrTaskNotFound})
@@ -135,16 +206,132 @@ func (h *TaskHandler) UpdateTaskByID(c *gin.Context) {
 		return
 	}
 
+	// Tasks only reach the trash through DeleteTask
+	task.DeletedAt = nil
+
+	// Validate the status against the workflow of the task
+	if task.Status != "" && task.Status != current.Status && !h.validStatus(c, current.ProjectID, task.Status) {
+		return
+	}
+
+	// Bind the query to the update options
+	var opts model.UpdateTaskOptions
+	if err := c.ShouldBindQuery(&opts); err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: ErrInvalidQuery})
+		return
+	}
+
+	// The update must be based on the current version of the task
+	var ok bool
+	if opts.Version, ok = ifMatchVersion(c); !ok {
+		return
+	}
+
 	// Update the task in the database
-	if err := h.TaskService.UpdateTask(ctx, taskId, &task); err != nil {
-		c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+	updated, err := h.TaskService.UpdateTask(ctx, taskId, &task, opts)
+	if err != nil {
+		h.handleTaskError(c, err)
 		return
 	}
 
 	// Return the updated task
+	c.Header("ETag", etag(updated.Version))
 	c.JSON(http.StatusOK, &model.Response{Message: "Task updated successfully"})
 }
 
+// PatchTaskByID applies a JSON Merge Patch or a JSON Patch, told apart by
+// the content type, to the patchable fields of the task
+func (h *TaskHandler) PatchTaskByID(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the task ID
+	taskId, err := uuid.FromString(c.Param("taskId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Check the patch format
+	contentType := c.ContentType()
+	if contentType != patch.MergePatchType && contentType != patch.JSONPatchType {
+		c.Header("Accept-Patch", patch.MergePatchType+", "+patch.JSONPatchType)
+		c.JSON(http.StatusUnsupportedMediaType, &model.Response{Message: ErrUnsupportedPatch})
+		return
+	}
+
+	// Bind the query to the update options
+	var opts model.UpdateTaskOptions
+	if err := c.ShouldBindQuery(&opts); err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: ErrInvalidQuery})
+		return
+	}
+
+	// The patch must be based on the current version of the task
+	var ok bool
+	if opts.Version, ok = ifMatchVersion(c); !ok {
+		return
+	}
+
+	// Fetch the task from the database
+	current, err := h.TaskService.GetTaskByID(ctx, taskId)
+	if err != nil {
+		if strings.EqualFold(err.Error(), "record not found") {
+			c.JSON(http.StatusNotFound, &model.Response{Message: ErrTaskNotFound})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	// Apply the patch to the patchable fields of the task
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+	doc, err := json.Marshal(model.NewTaskPatch(current))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	if contentType == patch.MergePatchType {
+		doc, err = patch.MergePatch(doc, body)
+	} else {
+		doc, err = patch.Apply(doc, body)
+	}
+	if err != nil {
+		handlePatchError(c, err)
+		return
+	}
+
+	// Validate the patched task, fields that cannot be patched are refused
+	var fields model.TaskPatch
+	decoder := json.NewDecoder(bytes.NewReader(doc))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&fields); err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Code: "invalid_patch", Message: err.Error()})
+		return
+	}
+	if err := binding.Validator.ValidateStruct(&fields); err != nil {
+		errMsg := handleValidationError(err)
+		c.JSON(http.StatusBadRequest, &model.Response{Messages: errMsg})
+		return
+	}
+	if fields.Status != current.Status && !h.validStatus(c, current.ProjectID, fields.Status) {
+		return
+	}
+
+	// Store the patched fields
+	task, err := h.TaskService.PatchTask(ctx, taskId, fields, opts)
+	if err != nil {
+		h.handleTaskError(c, err)
+		return
+	}
+
+	c.Header("ETag", etag(task.Version))
+	c.JSON(http.StatusOK, task)
+}
+
 func (h *TaskHandler) DeleteTaskByID(c *gin.Context) {
 	ctx := c.Request.Context()
 
@@ -158,20 +345,661 @@ func (h *TaskHandler) DeleteTaskByID(c *gin.Context) {
 		return
 	}
 
+	// Bind the query to the delete options
+	var opts model.DeleteTaskOptions
+	if err := c.ShouldBindQuery(&opts); err != nil {
+		errMsg := handleValidationError(err)
+		c.JSON(http.StatusBadRequest, &model.Response{Messages: errMsg})
+		return
+	}
+
+	// The deletion must be based on the current version of the task
+	var ok bool
+	if opts.Version, ok = ifMatchVersion(c); !ok {
+		return
+	}
+
 	// Delete the task from the database
-	if err := h.TaskService.DeleteTask(ctx, taskId); err != nil {
-		c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+	if err := h.TaskService.DeleteTask(ctx, taskId, opts); err != nil {
+		h.handleTaskError(c, err)
 		return
 	}
 
-	// Successfully delete the task
+	// Successfully delete the task, it stays in the trash until purged
 	c.JSON(http.StatusOK, &model.Response{Message: "Task deleted successfully"})
 }
 
+func (h *TaskHandler) StartTask(c *gin.Context) {
+	h.transitionTask(c, model.TaskActionStart)
+}
+
+func (h *TaskHandler) CompleteTask(c *gin.Context) {
+	h.transitionTask(c, model.TaskActionComplete)
+}
+
+func (h *TaskHandler) ReopenTask(c *gin.Context) {
+	h.transitionTask(c, model.TaskActionReopen)
+}
+
+func (h *TaskHandler) GetTrash(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Fetch the deleted tasks
+	tasks, err := h.TaskService.GetTrash(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	c.JSON(http.StatusOK, tasks)
+}
+
+func (h *TaskHandler) RestoreTask(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the task ID
+	taskId, err := uuid.FromString(c.Param("taskId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Take the task out of the trash
+	task, err := h.TaskService.RestoreTask(ctx, taskId)
+	if err != nil {
+		h.handleTaskError(c, err)
+		return
+	}
+
+	c.Header("ETag", etag(task.Version))
+	c.JSON(http.StatusOK, task)
+}
+
+func (h *TaskHandler) GetTaskHistory(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the task ID
+	taskId, err := uuid.FromString(c.Param("taskId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Fetch the revisions of the task
+	revisions, err := h.TaskService.GetTaskHistory(ctx, taskId)
+	if err != nil {
+		h.handleTaskError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, revisions)
+}
+
+func (h *TaskHandler) GetTaskRevision(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the task ID and the revision
+	taskId, err := uuid.FromString(c.Param("taskId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+	rev, err := strconv.Atoi(c.Param("rev"))
+	if err != nil || rev < 1 {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Fetch the revision along with the task after it
+	revision, err := h.TaskService.GetTaskRevision(ctx, taskId, rev)
+	if err != nil {
+		h.handleTaskError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, revision)
+}
+
+// RevertTask puts the task back to a revision, the revert is itself
+// recorded as a new revision
+func (h *TaskHandler) RevertTask(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the task ID and the revision
+	taskId, err := uuid.FromString(c.Param("taskId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+	rev, err := strconv.Atoi(c.Param("rev"))
+	if err != nil || rev < 1 {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Bind the query to the update options
+	var opts model.UpdateTaskOptions
+	if err := c.ShouldBindQuery(&opts); err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: ErrInvalidQuery})
+		return
+	}
+
+	// The revert must be based on the current version of the task
+	var ok bool
+	if opts.Version, ok = ifMatchVersion(c); !ok {
+		return
+	}
+
+	// Revert the task
+	task, err := h.TaskService.RevertTask(ctx, taskId, rev, opts)
+	if err != nil {
+		h.handleTaskError(c, err)
+		return
+	}
+
+	c.Header("ETag", etag(task.Version))
+	c.JSON(http.StatusOK, task)
+}
+
+func (h *TaskHandler) GetSubtasks(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the task ID
+	taskId, err := uuid.FromString(c.Param("taskId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Fetch the subtasks of the task
+	tasks, err := h.TaskService.GetSubtasks(ctx, taskId)
+	if err != nil {
+		h.handleTaskError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tasks)
+}
+
+func (h *TaskHandler) AddDependency(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the task ID
+	taskId, err := uuid.FromString(c.Param("taskId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Bind the JSON body to the dependency request
+	var req model.DependencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errMsg := handleValidationError(err)
+		c.JSON(http.StatusBadRequest, &model.Response{Messages: errMsg})
+		return
+	}
+
+	// Block the task by the other task
+	if err := h.TaskService.AddDependency(ctx, taskId, req.BlockedByID); err != nil {
+		h.handleTaskError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, &model.Response{Message: "Dependency added successfully"})
+}
+
+func (h *TaskHandler) RemoveDependency(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the task and blocker IDs
+	taskId, err := uuid.FromString(c.Param("taskId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+	blockerId, err := uuid.FromString(c.Param("blockerId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Remove the dependency
+	if err := h.TaskService.RemoveDependency(ctx, taskId, blockerId); err != nil {
+		h.handleTaskError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, &model.Response{Message: "Dependency removed successfully"})
+}
+
+func (h *TaskHandler) GetTaskGrants(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the task ID
+	taskId, err := uuid.FromString(c.Param("taskId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Fetch the grants of the task
+	grants, err := h.TaskService.GetTaskGrants(ctx, taskId)
+	if err != nil {
+		if strings.EqualFold(err.Error(), "record not found") {
+			c.JSON(http.StatusNotFound, &model.Response{Message: ErrTaskNotFound})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	c.JSON(http.StatusOK, grants)
+}
+
+func (h *TaskHandler) GrantTask(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the task ID
+	taskId, err := uuid.FromString(c.Param("taskId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Bind the JSON body to the grant request
+	var req model.GrantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errMsg := handleValidationError(err)
+		c.JSON(http.StatusBadRequest, &model.Response{Messages: errMsg})
+		return
+	}
+
+	// Grant the user access to the task
+	if err := h.TaskService.GrantTask(ctx, taskId, req.UserID); err != nil {
+		h.handleGrantError(c, err, ErrTaskNotFound)
+		return
+	}
+
+	c.JSON(http.StatusCreated, &model.Response{Message: "Task access granted successfully"})
+}
+
+func (h *TaskHandler) RevokeTaskGrant(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the task and user IDs
+	taskId, err := uuid.FromString(c.Param("taskId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+	userId, err := uuid.FromString(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Revoke the access of the user to the task
+	if err := h.TaskService.RevokeTaskGrant(ctx, taskId, userId); err != nil {
+		h.handleGrantError(c, err, ErrGrantNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, &model.Response{Message: "Task access revoked successfully"})
+}
+
+func (h *TaskHandler) GetAssignedTasks(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Bind the query to the task filter
+	var filter model.TaskFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		errMsg := handleValidationError(err)
+		if len(errMsg) == 0 {
+			c.JSON(http.StatusBadRequest, &model.Response{Message: ErrInvalidQuery})
+			return
+		}
+		c.JSON(http.StatusBadRequest, &model.Response{Messages: errMsg})
+		return
+	}
+
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, &model.Response{Message: http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	// Fetch the matching tasks assigned to the caller
+	filter.AssigneeID = principal.UserID()
+	tasks, err := h.TaskService.GetAllTasks(ctx, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	c.JSON(http.StatusOK, tasks)
+}
+
+func (h *TaskHandler) GetTaskAssignees(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the task ID
+	taskId, err := uuid.FromString(c.Param("taskId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Fetch the assignees of the task
+	assignees, err := h.TaskService.GetTaskAssignees(ctx, taskId)
+	if err != nil {
+		h.handleAssigneeError(c, err, ErrTaskNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, assignees)
+}
+
+func (h *TaskHandler) GetTaskAssignmentLog(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the task ID
+	taskId, err := uuid.FromString(c.Param("taskId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Fetch the assignment changes of the task
+	logs, err := h.TaskService.GetTaskAssignmentLog(ctx, taskId)
+	if err != nil {
+		h.handleAssigneeError(c, err, ErrTaskNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, logs)
+}
+
+func (h *TaskHandler) AssignTask(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the task ID
+	taskId, err := uuid.FromString(c.Param("taskId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Bind the JSON body to the assignees request
+	var req model.AssigneesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errMsg := handleValidationError(err)
+		c.JSON(http.StatusBadRequest, &model.Response{Messages: errMsg})
+		return
+	}
+
+	// Assign the users to the task
+	if err := h.TaskService.AssignTask(ctx, taskId, req.UserIDs); err != nil {
+		h.handleAssigneeError(c, err, ErrTaskNotFound)
+		return
+	}
+
+	c.JSON(http.StatusCreated, &model.Response{Message: "Task assigned successfully"})
+}
+
+func (h *TaskHandler) UnassignTask(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the task ID
+	taskId, err := uuid.FromString(c.Param("taskId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Bind the JSON body to the assignees request
+	var req model.AssigneesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errMsg := handleValidationError(err)
+		c.JSON(http.StatusBadRequest, &model.Response{Messages: errMsg})
+		return
+	}
+
+	// Remove the users from the assignees of the task
+	if err := h.TaskService.UnassignTask(ctx, taskId, req.UserIDs); err != nil {
+		h.handleAssigneeError(c, err, ErrAssigneeNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, &model.Response{Message: "Task unassigned successfully"})
+}
+
+func (h *TaskHandler) AttachLabels(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the task ID
+	taskId, err := uuid.FromString(c.Param("taskId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Bind the JSON body to the labels request
+	var req model.AttachLabelsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errMsg := handleValidationError(err)
+		c.JSON(http.StatusBadRequest, &model.Response{Messages: errMsg})
+		return
+	}
+
+	// Attach the labels to the task
+	if err := h.TaskService.AttachLabels(ctx, taskId, req.LabelIDs); err != nil {
+		h.handleTaskLabelError(c, err, ErrTaskNotFound)
+		return
+	}
+
+	c.JSON(http.StatusCreated, &model.Response{Message: "Labels attached successfully"})
+}
+
+func (h *TaskHandler) DetachLabel(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the task and label IDs
+	taskId, err := uuid.FromString(c.Param("taskId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+	labelId, err := uuid.FromString(c.Param("labelId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Detach the label from the task
+	if err := h.TaskService.DetachLabel(ctx, taskId, labelId); err != nil {
+		h.handleTaskLabelError(c, err, ErrLabelNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, &model.Response{Message: "Label detached successfully"})
+}
+
 /*
 	Suporting functions
 */
 
+// transitionTask applies the status action to the task of the URL
+func (h *TaskHandler) transitionTask(c *gin.Context, action string) {
+	ctx := c.Request.Context()
+
+	// Validate the task ID
+	taskId, err := uuid.FromString(c.Param("taskId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Bind the query to the update options
+	var opts model.UpdateTaskOptions
+	if err := c.ShouldBindQuery(&opts); err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: ErrInvalidQuery})
+		return
+	}
+
+	// Move the task to its new status
+	task, err := h.TaskService.TransitionTask(ctx, taskId, action, opts)
+	if err != nil {
+		h.handleTaskError(c, err)
+		return
+	}
+
+	c.Header("ETag", etag(task.Version))
+	c.JSON(http.StatusOK, task)
+}
+
+// handleTaskLabelError writes the response of a failed label attachment
+func (h *TaskHandler) handleTaskLabelError(c *gin.Context, err error, notFoundMsg string) {
+	switch {
+	case errors.Is(err, service.ErrLabelMismatch):
+		c.JSON(http.StatusBadRequest, &model.Response{Code: "invalid_label", Message: err.Error()})
+	case strings.EqualFold(err.Error(), "record not found"):
+		c.JSON(http.StatusNotFound, &model.Response{Message: notFoundMsg})
+	default:
+		c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+	}
+}
+
+// handleAssigneeError writes the response of a failed assignment change
+func (h *TaskHandler) handleAssigneeError(c *gin.Context, err error, notFoundMsg string) {
+	switch {
+	case errors.Is(err, service.ErrAssigneeNotMember), errors.Is(err, service.ErrAssigneeNoAccess):
+		c.JSON(http.StatusBadRequest, &model.Response{Code: "invalid_assignee", Message: err.Error()})
+	case strings.EqualFold(err.Error(), "record not found"):
+		c.JSON(http.StatusNotFound, &model.Response{Message: notFoundMsg})
+	default:
+		c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+	}
+}
+
+// handleTaskError writes the response of a failed task change, which
+// may be refused because of the parent, the subtasks or the dependencies
+// of the task
+func (h *TaskHandler) handleTaskError(c *gin.Context, err error) {
+	var transitionErr *workflow.TransitionError
+	switch {
+	case errors.As(err, &transitionErr):
+		c.JSON(http.StatusConflict, &model.Response{Code: "invalid_transition", Message: transitionMessage(transitionErr)})
+	case errors.Is(err, workflow.ErrNotDone), errors.Is(err, workflow.ErrIllegalTransition):
+		c.JSON(http.StatusConflict, &model.Response{Code: "invalid_transition", Message: err.Error()})
+	case errors.Is(err, workflow.ErrUnknownStatus):
+		c.JSON(http.StatusBadRequest, &model.Response{Messages: map[string]string{"status": "it must be a status of the workflow"}})
+	case errors.Is(err, service.ErrProjectNotFound):
+		c.JSON(http.StatusBadRequest, &model.Response{Code: "invalid_project", Message: err.Error()})
+	case errors.Is(err, service.ErrVersionMismatch):
+		c.JSON(http.StatusPreconditionFailed, &model.Response{Code: "version_mismatch", Message: ErrVersionMismatch})
+	case errors.Is(err, service.ErrBlockerNotFound), errors.Is(err, service.ErrDependencyCycle):
+		c.JSON(http.StatusBadRequest, &model.Response{Code: "invalid_dependency", Message: err.Error()})
+	case errors.Is(err, service.ErrDependencyExists):
+		c.JSON(http.StatusConflict, &model.Response{Message: err.Error()})
+	case errors.Is(err, service.ErrTaskBlocked):
+		c.JSON(http.StatusConflict, &model.Response{Code: "blocked", Message: "task is blocked by open tasks, complete them first or retry with ?force=true"})
+	case errors.Is(err, service.ErrParentNotFound), errors.Is(err, service.ErrTaskCycle), errors.Is(err, service.ErrTaskTooDeep):
+		c.JSON(http.StatusBadRequest, &model.Response{Code: "invalid_parent", Message: err.Error()})
+	case errors.Is(err, service.ErrRevisionNotFound):
+		c.JSON(http.StatusNotFound, &model.Response{Message: err.Error()})
+	case errors.Is(err, service.ErrParentDeleted):
+		c.JSON(http.StatusConflict, &model.Response{Code: "parent_deleted", Message: err.Error()})
+	case errors.Is(err, service.ErrTaskHasSubtasks):
+		c.JSON(http.StatusConflict, &model.Response{Code: "has_subtasks", Message: "task has subtasks, delete them with ?subtasks=cascade or keep them with ?subtasks=orphan"})
+	case strings.EqualFold(err.Error(), "record not found"):
+		c.JSON(http.StatusNotFound, &model.Response{Message: ErrTaskNotFound})
+	default:
+		c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+	}
+}
+
+// transitionMessage describes the refused transition, a no-op transition
+// tells the status the task already has
+func transitionMessage(err *workflow.TransitionError) string {
+	if err.From != err.To {
+		return err.Error()
+	}
+	switch err.Category {
+	case model.CategoryDone:
+		return ErrTaskAlreadyCompleted
+	case model.CategoryActive:
+		return ErrTaskAlreadyInProgress
+	default:
+		return ErrTaskAlreadyPending
+	}
+}
+
+// validStatus reports whether the status belongs to the workflow of the
+// project, and writes the error response when it does not
+func (h *TaskHandler) validStatus(c *gin.Context, projectID *uuid.UUID, status string) bool {
+	sm, err := h.TaskService.GetWorkflow(c.Request.Context(), projectID)
+	if err != nil {
+		h.handleTaskError(c, err)
+		return false
+	}
+	if !sm.Known(status) {
+		msg := fmt.Sprintf("it must be one of the following [%s]", strings.Join(sm.Statuses(), ", "))
+		c.JSON(http.StatusBadRequest, &model.Response{Messages: map[string]string{"status": msg}})
+		return false
+	}
+	return true
+}
+
+// ifMatchVersion reads the version of the task the request is based on
+// from the If-Match header, "*" matches any version. The header is
+// required, the response is written when it is missing or malformed.
+func ifMatchVersion(c *gin.Context) (int, bool) {
+	header := c.GetHeader("If-Match")
+	switch header {
+	case "":
+		c.JSON(http.StatusPreconditionRequired, &model.Response{Code: "precondition_required", Message: ErrIfMatchRequired})
+		return 0, false
+	case "*":
+		return 0, true
+	}
+
+	version, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(header, "W/"), `"`))
+	if err != nil || version < 1 {
+		c.JSON(http.StatusPreconditionFailed, &model.Response{Code: "version_mismatch", Message: ErrVersionMismatch})
+		return 0, false
+	}
+	return version, true
+}
+
+// etag returns the ETag of the version of a task
+func etag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// handlePatchError writes the response of a patch that cannot be applied
+func handlePatchError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, patch.ErrTestFailed):
+		c.JSON(http.StatusConflict, &model.Response{Code: "test_failed", Message: err.Error()})
+	case errors.Is(err, patch.ErrPathNotFound):
+		c.JSON(http.StatusUnprocessableEntity, &model.Response{Code: "invalid_patch", Message: err.Error()})
+	default:
+		c.JSON(http.StatusBadRequest, &model.Response{Code: "invalid_patch", Message: err.Error()})
+	}
+}
+
+// handleGrantError writes the response of a failed grant change
+func (h *TaskHandler) handleGrantError(c *gin.Context, err error, notFoundMsg string) {
+	switch {
+	case errors.Is(err, service.ErrNotTaskOwner):
+		c.JSON(http.StatusForbidden, &model.Response{Code: "forbidden", Message: err.Error()})
+	case strings.EqualFold(err.Error(), "record not found"):
+		c.JSON(http.StatusNotFound, &model.Response{Message: notFoundMsg})
+	default:
+		c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+	}
+}
+
 // handleValidationError customizes the error message when validation fails
 func handleValidationError(err error) map[string]string {
 	// Cast the error to a ValidationErrors type
@@ -184,8 +1012,20 @@ func handleValidationError(err error) map[string]string {
 	for _, e := range validationErrors {
 		field := strings.ToLower(e.Field())
 		switch e.Tag() {
+		case "datetime":
+			errorsMap[field] = fmt.Sprintf("it must be formatted as %s", e.Param())
 		case "email":
 			errorsMap[field] = "it must be a valid email address"
+		case "excluded_with":
+			errorsMap[field] = fmt.Sprintf("it cannot be given along with %s", strings.ToLower(e.Param()))
+		case "gte":
+			errorsMap[field] = fmt.Sprintf("it must be at least %s", e.Param())
+		case "gtfield":
+			errorsMap[field] = fmt.Sprintf("it must be after %s", strings.ToLower(e.Param()))
+		case "hexcolor":
+			errorsMap[field] = "it must be a hex color, e.g. #1f6feb"
+		case "lte":
+			errorsMap[field] = fmt.Sprintf("it must be at most %s", e.Param())
 		case "max":
 			errorsMap[field] = fmt.Sprintf("it must be at most %s characters long", e.Param())
 		case "min":
@@ -198,6 +1038,10 @@ func handleValidationError(err error) map[string]string {
 			errorsMap[field] = "it must be a valid phone number"
 		case "required":
 			errorsMap[field] = "this is a required field"
+		case "required_without":
+			errorsMap[field] = fmt.Sprintf("this is a required field without %s", strings.ToLower(e.Param()))
+		case "timezone":
+			errorsMap[field] = "it must be an IANA timezone, e.g. Europe/Berlin"
 		default:
 			errorsMap[field] = "invalid value provided"
 		}
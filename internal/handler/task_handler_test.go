@@ -10,9 +10,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"regexp"
+	"task-manager/internal/auth"
 	"task-manager/internal/mocks"
 	"task-manager/internal/model"
+	"task-manager/internal/service"
+	"task-manager/internal/workflow"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
@@ -26,6 +30,8 @@ var (
 	errMock         = errors.New("internal error")
 	errMockNotFound = errors.New("record not found")
 	uuid1, _        = uuid.NewV7()
+	principal1      = &auth.Principal{Subject: uuid1.String(), Username: "user1", Roles: []string{auth.RoleMember}}
+	principalCtx    = auth.WithPrincipal(context.Background(), principal1)
 
 	// for validation
 	nameValidatePattern        = regexp.MustCompile(`^[A-Za-zÀ-ÿ]+([ -][A-Za-zÀ-ÿ]+)*$`)
@@ -48,6 +54,9 @@ func init() {
 		v.RegisterValidation("name", NameValidator)   // for Name regex validation
 		v.RegisterValidation("phone", PhoneValidator) // for Phone regex validation
 	}
+
+	// There is no default signing key, so tests sign with a fixed secret
+	auth.SetKeySet(auth.NewHMACKeySet(auth.DefaultKeyID, []byte("secret")))
 }
 
 func Test_GetTasks(t *testing.T) {
@@ -66,7 +75,7 @@ func Test_GetTasks(t *testing.T) {
 		c, _ := gin.CreateTestContext(w)
 		c.Request = req
 
-		taskService.On("GetAllTasks", mock.Anything).
+		taskService.On("GetAllTasks", mock.Anything, model.TaskFilter{}).
 			Return(nil, errMock).Once()
 
 		// Call the GetTasks function
@@ -88,7 +97,7 @@ func Test_GetTasks(t *testing.T) {
 		c.Request = req
 
 		var tasks = []model.Task{{ID: uuid1, Title: "Task 1", Description: "Description 1", Status: "pending"}}
-		taskService.On("GetAllTasks", mock.Anything).
+		taskService.On("GetAllTasks", mock.Anything, model.TaskFilter{}).
 			Return(tasks, nil).Once()
 
 		// Call the GetTasks function
@@ -104,11 +113,101 @@ func Test_GetTasks(t *testing.T) {
 		require.Nil(t, err)
 		require.Equal(t, tasks, respObj)
 	})
+
+	// Test case 3
+	t.Run("GetTasks: filter and sort", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/tasks/?priority=urgent&overdue=true&due_before=2026-03-01T00:00:00Z&sort=-priority", nil)
+
+		overdue := true
+		filter := model.TaskFilter{
+			Priority:  model.PriorityUrgent,
+			DueBefore: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+			Overdue:   &overdue,
+			Sort:      "-priority",
+		}
+		taskService.On("GetAllTasks", mock.Anything, filter).
+			Return([]model.Task{}, nil).Once()
+
+		taskHandler.GetTasks(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, `[]`, w.Body.String())
+	})
+
+	// Test case 4
+	t.Run("GetTasks: invalid filter", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/tasks/?priority=soon&sort=title", nil)
+
+		taskHandler.GetTasks(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		expectedResp := `{"messages":{"priority":"it must be one of the following [low, medium, high, urgent]","sort":"it must be one of the following [created_at, -created_at, due_at, -due_at, priority, -priority]"}}`
+		require.Equal(t, expectedResp, w.Body.String())
+	})
+
+	// Test case 5
+	t.Run("GetTasks: invalid due date", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/tasks/?due_after=tomorrow", nil)
+
+		taskHandler.GetTasks(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Equal(t, `{"message":"invalid query parameters"}`, w.Body.String())
+	})
+
+	// Test case 6
+	t.Run("GetTasks: label filter", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/tasks/?label=backend&label=bug&label_match=all", nil)
+
+		filter := model.TaskFilter{Labels: []string{"backend", "bug"}, LabelMatch: "all"}
+		taskService.On("GetAllTasks", mock.Anything, filter).
+			Return([]model.Task{}, nil).Once()
+
+		taskHandler.GetTasks(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		taskService.AssertExpectations(t)
+	})
+
+	// Test case 7
+	t.Run("GetTasks: overdue flag", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+
+		past := time.Now().Add(-time.Hour)
+		tasks := []model.Task{
+			{ID: uuid1, Title: "Late", Status: model.TaskStatusPending, DueAt: &past},
+			{ID: uuid1, Title: "Done", Status: model.TaskStatusCompleted, StatusCategory: model.CategoryDone, DueAt: &past},
+			{ID: uuid1, Title: "No due date", Status: model.TaskStatusPending},
+		}
+		taskService.On("GetAllTasks", mock.Anything, model.TaskFilter{}).
+			Return(tasks, nil).Once()
+
+		taskHandler.GetTasks(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var respObj []map[string]interface{}
+		require.Nil(t, json.Unmarshal(w.Body.Bytes(), &respObj))
+		require.Len(t, respObj, 3)
+		require.Equal(t, true, respObj[0]["overdue"])
+		require.Equal(t, false, respObj[1]["overdue"])
+		require.Equal(t, false, respObj[2]["overdue"])
+	})
 }
 
 func Test_CreateTask(t *testing.T) {
 	taskService := new(mocks.ITaskService)
 	taskHandler := NewTaskHandler(taskService)
+	taskService.On("GetWorkflow", mock.Anything, mock.Anything).Return(workflow.Default(), nil).Maybe()
 
 	// Test case 1
 	t.Run("CreateTask: input validation error", func(t *testing.T) {
@@ -144,7 +243,7 @@ func Test_CreateTask(t *testing.T) {
 		// Create a new http request
 		req, err := http.NewRequest(http.MethodPost, "/tasks/", bytes.NewReader(body))
 		require.Nil(t, err)
-		req = req.WithContext(context.Background())
+		req = req.WithContext(principalCtx)
 
 		// Create a new gin context
 		w := httptest.NewRecorder()
@@ -174,7 +273,7 @@ func Test_CreateTask(t *testing.T) {
 		// Create a new http request
 		req, err := http.NewRequest(http.MethodPost, "/tasks/", bytes.NewReader(body))
 		require.Nil(t, err)
-		req = req.WithContext(context.Background())
+		req = req.WithContext(principalCtx)
 
 		// Create a new gin context
 		w := httptest.NewRecorder()
@@ -195,6 +294,50 @@ func Test_CreateTask(t *testing.T) {
 		require.Nil(t, err)
 		require.Equal(t, task.Title, respObj.Title)
 		require.Equal(t, task.Description, respObj.Description)
+		require.Equal(t, model.PriorityMedium, respObj.Priority)
+		require.Equal(t, uuid1, respObj.OwnerID)
+		require.Equal(t, uuid1, respObj.CreatedBy)
+	})
+
+	// Test case 4
+	t.Run("CreateTask: status outside the project workflow", func(t *testing.T) {
+		projectService := new(mocks.ITaskService)
+		projectHandler := NewTaskHandler(projectService)
+		board, err := workflow.New(model.Workflow{Statuses: []model.WorkflowStatus{
+			{Key: "backlog", Name: "Backlog", Category: model.CategoryTodo},
+			{Key: "review", Name: "Review", Category: model.CategoryActive},
+			{Key: "shipped", Name: "Shipped", Category: model.CategoryDone},
+		}})
+		require.Nil(t, err)
+
+		c, w := newJSONContext(t, http.MethodPost, "/tasks/", model.Task{Title: "Task 1", Description: "Description 1", Status: "pending", ProjectID: &uuid1})
+		c.Request = c.Request.WithContext(principalCtx)
+
+		projectService.On("GetWorkflow", mock.Anything, &uuid1).Return(board, nil).Once()
+
+		projectHandler.CreateTask(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Equal(t, `{"messages":{"status":"it must be one of the following [backlog, review, shipped]"}}`, w.Body.String())
+		projectService.AssertNotCalled(t, "CreateTask", mock.Anything, mock.Anything)
+	})
+
+	// Test case 5
+	t.Run("CreateTask: deleted_at is ignored", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodPost, "/tasks/", bytes.NewReader([]byte(`{"title":"Task 1","description":"Description 1","deleted_at":"2026-01-01T00:00:00Z"}`)))
+		require.Nil(t, err)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req.WithContext(principalCtx)
+
+		taskService.On("CreateTask", mock.Anything, mock.MatchedBy(func(task *model.Task) bool {
+			return task.DeletedAt == nil
+		})).Return(nil).Once()
+
+		taskHandler.CreateTask(c)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		require.NotContains(t, w.Body.String(), "deleted_at")
 	})
 }
 
@@ -316,6 +459,7 @@ func Test_GetTaskByID(t *testing.T) {
 func Test_UpdateTaskByID(t *testing.T) {
 	taskService := new(mocks.ITaskService)
 	taskHandler := NewTaskHandler(taskService)
+	taskService.On("GetWorkflow", mock.Anything, mock.Anything).Return(workflow.Default(), nil).Maybe()
 
 	// Test case 1
 	t.Run("UpdateTaskByID: invalid task id", func(t *testing.T) {
@@ -323,6 +467,7 @@ func Test_UpdateTaskByID(t *testing.T) {
 		req, err := http.NewRequest(http.MethodPatch, "/tasks/abcd1234", nil)
 		require.Nil(t, err)
 		req = req.WithContext(context.Background())
+		req.Header.Set("If-Match", `"1"`)
 
 		// Create a new gin context
 		w := httptest.NewRecorder()
@@ -347,6 +492,7 @@ func Test_UpdateTaskByID(t *testing.T) {
 		req, err := http.NewRequest(http.MethodPatch, "/tasks/"+uuid1.String(), nil)
 		require.Nil(t, err)
 		req = req.WithContext(context.Background())
+		req.Header.Set("If-Match", `"1"`)
 
 		// Create a new gin context
 		w := httptest.NewRecorder()
@@ -374,6 +520,7 @@ func Test_UpdateTaskByID(t *testing.T) {
 		req, err := http.NewRequest(http.MethodPatch, "/tasks/"+uuid1.String(), nil)
 		require.Nil(t, err)
 		req = req.WithContext(context.Background())
+		req.Header.Set("If-Match", `"1"`)
 
 		// Create a new gin context
 		w := httptest.NewRecorder()
@@ -401,6 +548,7 @@ func Test_UpdateTaskByID(t *testing.T) {
 		req, err := http.NewRequest(http.MethodPatch, "/tasks/"+uuid1.String(), nil)
 		require.Nil(t, err)
 		req = req.WithContext(context.Background())
+		req.Header.Set("If-Match", `"1"`)
 
 		// Create a new gin context
 		w := httptest.NewRecorder()
@@ -429,6 +577,7 @@ func Test_UpdateTaskByID(t *testing.T) {
 		req, err := http.NewRequest(http.MethodPatch, "/tasks/"+uuid1.String(), bytes.NewReader(body))
 		require.Nil(t, err)
 		req = req.WithContext(context.Background())
+		req.Header.Set("If-Match", `"1"`)
 
 		// Create a new gin context
 		w := httptest.NewRecorder()
@@ -438,8 +587,8 @@ func Test_UpdateTaskByID(t *testing.T) {
 
 		taskService.On("GetTaskByID", mock.Anything, mock.AnythingOfType("uuid.UUID")).
 			Return(&task, nil).Once()
-		taskService.On("UpdateTask", mock.Anything, mock.AnythingOfType("uuid.UUID"), mock.AnythingOfType("*model.Task")).
-			Return(errMock).Once()
+		taskService.On("UpdateTask", mock.Anything, mock.AnythingOfType("uuid.UUID"), mock.AnythingOfType("*model.Task"), model.UpdateTaskOptions{Version: 1}).
+			Return(nil, errMock).Once()
 
 		// Call the UpdateTaskByID function
 		taskHandler.UpdateTaskByID(c)
@@ -462,6 +611,7 @@ func Test_UpdateTaskByID(t *testing.T) {
 		req, err := http.NewRequest(http.MethodPatch, "/tasks/"+uuid1.String(), bytes.NewReader(body))
 		require.Nil(t, err)
 		req = req.WithContext(context.Background())
+		req.Header.Set("If-Match", `"1"`)
 
 		// Create a new gin context
 		w := httptest.NewRecorder()
@@ -471,8 +621,8 @@ func Test_UpdateTaskByID(t *testing.T) {
 
 		taskService.On("GetTaskByID", mock.Anything, mock.AnythingOfType("uuid.UUID")).
 			Return(&task, nil).Once()
-		taskService.On("UpdateTask", mock.Anything, mock.AnythingOfType("uuid.UUID"), mock.AnythingOfType("*model.Task")).
-			Return(nil).Once()
+		taskService.On("UpdateTask", mock.Anything, mock.AnythingOfType("uuid.UUID"), mock.AnythingOfType("*model.Task"), model.UpdateTaskOptions{Version: 1}).
+			Return(&task, nil).Once()
 
 		// Call the UpdateTaskByID function
 		taskHandler.UpdateTaskByID(c)
@@ -484,6 +634,212 @@ func Test_UpdateTaskByID(t *testing.T) {
 		expectedResp := `{"message":"Task updated successfully"}`
 		require.Equal(t, expectedResp, resp)
 	})
+
+	// Test case 7
+	t.Run("UpdateTaskByID: deleted_at is ignored", func(t *testing.T) {
+		var task = model.Task{ID: uuid1, Title: "Task 1", Description: "Description 1", Status: "pending"}
+		body := `{"title":"Task 1","description":"Description 1","status":"pending","deleted_at":"2026-01-01T00:00:00Z"}`
+
+		// Create a new http request
+		req, err := http.NewRequest(http.MethodPut, "/tasks/"+uuid1.String(), bytes.NewReader([]byte(body)))
+		require.Nil(t, err)
+		req = req.WithContext(context.Background())
+		req.Header.Set("If-Match", `"1"`)
+
+		// Create a new gin context
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		taskService.On("GetTaskByID", mock.Anything, mock.AnythingOfType("uuid.UUID")).
+			Return(&task, nil).Once()
+		taskService.On("UpdateTask", mock.Anything, uuid1, mock.MatchedBy(func(update *model.Task) bool {
+			return update.Title == "Task 1" && update.DeletedAt == nil
+		}), model.UpdateTaskOptions{Version: 1}).Return(&task, nil).Once()
+
+		// Call the UpdateTaskByID function
+		taskHandler.UpdateTaskByID(c)
+
+		// Check the status code
+		require.Equal(t, http.StatusOK, w.Code)
+	})
+}
+
+func Test_PatchTaskByID(t *testing.T) {
+	taskService := new(mocks.ITaskService)
+	taskHandler := NewTaskHandler(taskService)
+	dueAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	current := &model.Task{ID: uuid1, Title: "Task 1", Description: "Description 1", Status: "pending", Priority: model.PriorityMedium, DueAt: &dueAt, Version: 1}
+
+	newPatchContext := func(contentType, body string) (*gin.Context, *httptest.ResponseRecorder) {
+		req, err := http.NewRequest(http.MethodPatch, "/tasks/"+uuid1.String(), bytes.NewReader([]byte(body)))
+		require.Nil(t, err)
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("If-Match", `"1"`)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req.WithContext(principalCtx)
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+		return c, w
+	}
+
+	// Test case 1
+	t.Run("PatchTaskByID: unsupported media type", func(t *testing.T) {
+		c, w := newPatchContext("application/json", `{"title":"Task 2"}`)
+
+		taskHandler.PatchTaskByID(c)
+
+		require.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+		require.Equal(t, "application/merge-patch+json, application/json-patch+json", w.Header().Get("Accept-Patch"))
+	})
+
+	// Test case 2
+	t.Run("PatchTaskByID: merge patch clears the due date", func(t *testing.T) {
+		c, w := newPatchContext("application/merge-patch+json", `{"due_at":null,"priority":"high"}`)
+
+		taskService.On("GetTaskByID", mock.Anything, uuid1).Return(current, nil).Once()
+		patched := &model.Task{ID: uuid1, Title: "Task 1", Description: "Description 1", Status: "pending", Priority: model.PriorityHigh, Version: 2}
+		taskService.On("PatchTask", mock.Anything, uuid1, model.TaskPatch{
+			Title: "Task 1", Description: "Description 1", Status: "pending", Priority: model.PriorityHigh,
+		}, model.UpdateTaskOptions{Version: 1}).Return(patched, nil).Once()
+
+		taskHandler.PatchTaskByID(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), `"priority":"high","due_at":null`)
+		require.Equal(t, `"2"`, w.Header().Get("ETag"))
+	})
+
+	// Test case 3
+	t.Run("PatchTaskByID: merge patch of a read-only field", func(t *testing.T) {
+		c, w := newPatchContext("application/merge-patch+json", `{"owner_id":"`+uuid1.String()+`"}`)
+
+		taskService.On("GetTaskByID", mock.Anything, uuid1).Return(current, nil).Once()
+
+		taskHandler.PatchTaskByID(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Equal(t, `{"code":"invalid_patch","message":"json: unknown field \"owner_id\""}`, w.Body.String())
+	})
+
+	// Test case 4
+	t.Run("PatchTaskByID: json patch removes a required field", func(t *testing.T) {
+		c, w := newPatchContext("application/json-patch+json", `[{"op":"remove","path":"/title"}]`)
+
+		taskService.On("GetTaskByID", mock.Anything, uuid1).Return(current, nil).Once()
+
+		taskHandler.PatchTaskByID(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Equal(t, `{"messages":{"title":"this is a required field"}}`, w.Body.String())
+	})
+
+	// Test case 5
+	t.Run("PatchTaskByID: json patch test fails", func(t *testing.T) {
+		c, w := newPatchContext("application/json-patch+json", `[{"op":"test","path":"/status","value":"completed"},{"op":"replace","path":"/status","value":"pending"}]`)
+
+		taskService.On("GetTaskByID", mock.Anything, uuid1).Return(current, nil).Once()
+
+		taskHandler.PatchTaskByID(c)
+
+		require.Equal(t, http.StatusConflict, w.Code)
+		require.Equal(t, `{"code":"test_failed","message":"operation 0: test operation failed: /status"}`, w.Body.String())
+	})
+
+	// Test case 6
+	t.Run("PatchTaskByID: json patch of a missing path", func(t *testing.T) {
+		c, w := newPatchContext("application/json-patch+json", `[{"op":"replace","path":"/owner_id","value":"x"}]`)
+
+		taskService.On("GetTaskByID", mock.Anything, uuid1).Return(current, nil).Once()
+
+		taskHandler.PatchTaskByID(c)
+
+		require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+
+	taskService.AssertExpectations(t)
+}
+
+func Test_TaskVersions(t *testing.T) {
+	taskService := new(mocks.ITaskService)
+	taskHandler := NewTaskHandler(taskService)
+	taskService.On("GetWorkflow", mock.Anything, mock.Anything).Return(workflow.Default(), nil).Maybe()
+	task := &model.Task{ID: uuid1, Title: "Task 1", Description: "Description 1", Status: "pending", Version: 3}
+
+	// Test case 1
+	t.Run("GetTaskByID: ETag", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodGet, "/tasks/"+uuid1.String(), nil)
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		taskService.On("GetTaskByID", mock.Anything, uuid1).Return(task, nil).Once()
+
+		taskHandler.GetTaskByID(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, `"3"`, w.Header().Get("ETag"))
+		require.Contains(t, w.Body.String(), `"version":3`)
+	})
+
+	// Test case 2
+	t.Run("UpdateTaskByID: missing If-Match", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPut, "/tasks/"+uuid1.String(), task)
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		taskService.On("GetTaskByID", mock.Anything, uuid1).Return(task, nil).Once()
+
+		taskHandler.UpdateTaskByID(c)
+
+		require.Equal(t, http.StatusPreconditionRequired, w.Code)
+		require.Equal(t, `{"code":"precondition_required","message":"If-Match header with the ETag of the task is required"}`, w.Body.String())
+	})
+
+	// Test case 3
+	t.Run("UpdateTaskByID: stale version", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPut, "/tasks/"+uuid1.String(), task)
+		c.Request.Header.Set("If-Match", `W/"2"`)
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		taskService.On("GetTaskByID", mock.Anything, uuid1).Return(task, nil).Once()
+		taskService.On("UpdateTask", mock.Anything, uuid1, mock.AnythingOfType("*model.Task"), model.UpdateTaskOptions{Version: 2}).
+			Return(nil, service.ErrVersionMismatch).Once()
+
+		taskHandler.UpdateTaskByID(c)
+
+		require.Equal(t, http.StatusPreconditionFailed, w.Code)
+		require.Equal(t, `{"code":"version_mismatch","message":"task was changed since it was read, fetch it again and retry"}`, w.Body.String())
+	})
+
+	// Test case 4
+	t.Run("UpdateTaskByID: any version", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPut, "/tasks/"+uuid1.String(), task)
+		c.Request.Header.Set("If-Match", "*")
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		taskService.On("GetTaskByID", mock.Anything, uuid1).Return(task, nil).Once()
+		// Other updates went through meanwhile, the ETag is the version
+		// that was stored
+		taskService.On("UpdateTask", mock.Anything, uuid1, mock.AnythingOfType("*model.Task"), model.UpdateTaskOptions{}).
+			Return(&model.Task{ID: uuid1, Version: 6}, nil).Once()
+
+		taskHandler.UpdateTaskByID(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, `"6"`, w.Header().Get("ETag"))
+	})
+
+	// Test case 5
+	t.Run("DeleteTaskByID: malformed If-Match", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodDelete, "/tasks/"+uuid1.String(), nil)
+		c.Request.Header.Set("If-Match", `"abc"`)
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		taskHandler.DeleteTaskByID(c)
+
+		require.Equal(t, http.StatusPreconditionFailed, w.Code)
+		taskService.AssertNotCalled(t, "DeleteTask", mock.Anything, mock.Anything, mock.Anything)
+	})
 }
 
 func Test_DeleteTaskByID(t *testing.T) {
@@ -496,6 +852,7 @@ func Test_DeleteTaskByID(t *testing.T) {
 		req, err := http.NewRequest(http.MethodGet, "/tasks/abcd1234", nil)
 		require.Nil(t, err)
 		req = req.WithContext(context.Background())
+		req.Header.Set("If-Match", `"1"`)
 
 		// Create a new gin context
 		w := httptest.NewRecorder()
@@ -520,6 +877,7 @@ func Test_DeleteTaskByID(t *testing.T) {
 		req, err := http.NewRequest(http.MethodGet, "/tasks/"+uuid1.String(), nil)
 		require.Nil(t, err)
 		req = req.WithContext(context.Background())
+		req.Header.Set("If-Match", `"1"`)
 
 		// Create a new gin context
 		w := httptest.NewRecorder()
@@ -527,7 +885,7 @@ func Test_DeleteTaskByID(t *testing.T) {
 		c.Request = req
 		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
 
-		taskService.On("DeleteTask", mock.Anything, mock.AnythingOfType("uuid.UUID")).
+		taskService.On("DeleteTask", mock.Anything, mock.AnythingOfType("uuid.UUID"), model.DeleteTaskOptions{Version: 1}).
 			Return(errMock).Once()
 
 		// Call the DeleteTaskByID function
@@ -542,6 +900,34 @@ func Test_DeleteTaskByID(t *testing.T) {
 	})
 
 	// Test case 3
+	t.Run("DeleteTaskByID: not found", func(t *testing.T) {
+		// Create a new http request
+		req, err := http.NewRequest(http.MethodDelete, "/tasks/"+uuid1.String(), nil)
+		require.Nil(t, err)
+		req = req.WithContext(principalCtx)
+		req.Header.Set("If-Match", `"1"`)
+
+		// Create a new gin context
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		taskService.On("DeleteTask", mock.Anything, mock.AnythingOfType("uuid.UUID"), model.DeleteTaskOptions{Version: 1}).
+			Return(errMockNotFound).Once()
+
+		// Call the DeleteTaskByID function
+		taskHandler.DeleteTaskByID(c)
+
+		// Check the status code
+		require.Equal(t, http.StatusNotFound, w.Code)
+		// Define the expected response
+		resp := w.Body.String()
+		expectedResp := `{"message":"task not found"}`
+		require.Equal(t, expectedResp, resp)
+	})
+
+	// Test case 4
 	t.Run("DeleteTaskByID: success", func(t *testing.T) {
 		var task = model.Task{ID: uuid1, Title: "Task 1", Description: "Description 1", Status: "pending"}
 		body, err := json.Marshal(task)
@@ -551,6 +937,7 @@ func Test_DeleteTaskByID(t *testing.T) {
 		req, err := http.NewRequest(http.MethodGet, "/tasks/"+uuid1.String(), bytes.NewReader(body))
 		require.Nil(t, err)
 		req = req.WithContext(context.Background())
+		req.Header.Set("If-Match", `"1"`)
 
 		// Create a new gin context
 		w := httptest.NewRecorder()
@@ -558,7 +945,7 @@ func Test_DeleteTaskByID(t *testing.T) {
 		c.Request = req
 		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
 
-		taskService.On("DeleteTask", mock.Anything, mock.AnythingOfType("uuid.UUID")).
+		taskService.On("DeleteTask", mock.Anything, mock.AnythingOfType("uuid.UUID"), model.DeleteTaskOptions{Version: 1}).
 			Return(nil).Once()
 
 		// Call the DeleteTaskByID function
@@ -573,6 +960,700 @@ func Test_DeleteTaskByID(t *testing.T) {
 	})
 }
 
+func Test_Trash(t *testing.T) {
+	taskService := new(mocks.ITaskService)
+	taskHandler := NewTaskHandler(taskService)
+	deletedAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Test case 1
+	t.Run("GetTrash: success", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodGet, "/tasks/trash", nil)
+
+		tasks := []model.Task{{ID: uuid1, Title: "Task 1", DeletedAt: &deletedAt}}
+		taskService.On("GetTrash", mock.Anything).Return(tasks, nil).Once()
+
+		taskHandler.GetTrash(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), `"deleted_at":"2030-01-01T00:00:00Z"`)
+	})
+
+	// Test case 2
+	t.Run("RestoreTask: not in the trash", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/tasks/"+uuid1.String()+"/restore", nil)
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		taskService.On("RestoreTask", mock.Anything, uuid1).Return(nil, errMockNotFound).Once()
+
+		taskHandler.RestoreTask(c)
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+		require.Equal(t, `{"message":"task not found"}`, w.Body.String())
+	})
+
+	// Test case 3
+	t.Run("RestoreTask: parent in the trash", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/tasks/"+uuid1.String()+"/restore", nil)
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		taskService.On("RestoreTask", mock.Anything, uuid1).Return(nil, service.ErrParentDeleted).Once()
+
+		taskHandler.RestoreTask(c)
+
+		require.Equal(t, http.StatusConflict, w.Code)
+		require.Equal(t, `{"code":"parent_deleted","message":"the parent task is in the trash, restore it first"}`, w.Body.String())
+	})
+
+	// Test case 4
+	t.Run("RestoreTask: success", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/tasks/"+uuid1.String()+"/restore", nil)
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		task := &model.Task{ID: uuid1, Title: "Task 1", Version: 2}
+		taskService.On("RestoreTask", mock.Anything, uuid1).Return(task, nil).Once()
+
+		taskHandler.RestoreTask(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.NotContains(t, w.Body.String(), `"deleted_at"`)
+		require.Equal(t, `"2"`, w.Header().Get("ETag"))
+	})
+}
+
+func Test_TaskRevisions(t *testing.T) {
+	taskService := new(mocks.ITaskService)
+	taskHandler := NewTaskHandler(taskService)
+
+	// Test case 1
+	t.Run("GetTaskHistory: success", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodGet, "/tasks/"+uuid1.String()+"/history", nil)
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		revisions := []model.TaskRevision{{TaskID: uuid1, Revision: 2, ActorID: uuid1, Changes: model.TaskChanges{
+			"priority": {Old: "medium", New: "high"},
+		}}}
+		taskService.On("GetTaskHistory", mock.Anything, uuid1).Return(revisions, nil).Once()
+
+		taskHandler.GetTaskHistory(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), `"revision":2`)
+		require.Contains(t, w.Body.String(), `"changes":{"priority":{"old":"medium","new":"high"}}`)
+		require.NotContains(t, w.Body.String(), `"snapshot"`)
+	})
+
+	// Test case 2
+	t.Run("GetTaskRevision: invalid revision", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodGet, "/tasks/"+uuid1.String()+"/revisions/0", nil)
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()}, gin.Param{Key: "rev", Value: "0"})
+
+		taskHandler.GetTaskRevision(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	// Test case 3
+	t.Run("GetTaskRevision: not found", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodGet, "/tasks/"+uuid1.String()+"/revisions/9", nil)
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()}, gin.Param{Key: "rev", Value: "9"})
+
+		taskService.On("GetTaskRevision", mock.Anything, uuid1, 9).Return(nil, service.ErrRevisionNotFound).Once()
+
+		taskHandler.GetTaskRevision(c)
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+		require.Equal(t, `{"message":"revision not found"}`, w.Body.String())
+	})
+
+	// Test case 4
+	t.Run("RevertTask: missing If-Match", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/tasks/"+uuid1.String()+"/revisions/1/revert", nil)
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()}, gin.Param{Key: "rev", Value: "1"})
+
+		taskHandler.RevertTask(c)
+
+		require.Equal(t, http.StatusPreconditionRequired, w.Code)
+	})
+
+	// Test case 5
+	t.Run("RevertTask: success", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/tasks/"+uuid1.String()+"/revisions/1/revert", nil)
+		c.Request.Header.Set("If-Match", `"3"`)
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()}, gin.Param{Key: "rev", Value: "1"})
+
+		task := &model.Task{ID: uuid1, Title: "Task 1", Version: 4}
+		taskService.On("RevertTask", mock.Anything, uuid1, 1, model.UpdateTaskOptions{Version: 3}).Return(task, nil).Once()
+
+		taskHandler.RevertTask(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, `"4"`, w.Header().Get("ETag"))
+	})
+}
+
+func Test_TaskHierarchy(t *testing.T) {
+	taskService := new(mocks.ITaskService)
+	taskHandler := NewTaskHandler(taskService)
+	taskService.On("GetWorkflow", mock.Anything, mock.Anything).Return(workflow.Default(), nil).Maybe()
+	parentID, _ := uuid.NewV7()
+
+	// Test case 1
+	t.Run("CreateTask: parent too deep", func(t *testing.T) {
+		task := model.Task{Title: "Task 1", Description: "Description 1", Status: "pending", ParentID: &parentID}
+		c, w := newJSONContext(t, http.MethodPost, "/tasks/", task)
+		c.Request = c.Request.WithContext(principalCtx)
+
+		taskService.On("CreateTask", mock.Anything, mock.MatchedBy(func(task *model.Task) bool {
+			return *task.ParentID == parentID
+		})).Return(service.ErrTaskTooDeep).Once()
+
+		taskHandler.CreateTask(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Equal(t, `{"code":"invalid_parent","message":"task hierarchy is too deep"}`, w.Body.String())
+	})
+
+	// Test case 2
+	t.Run("UpdateTaskByID: cycle", func(t *testing.T) {
+		task := model.Task{Title: "Task 1", Description: "Description 1", Status: "pending", ParentID: &parentID}
+		c, w := newJSONContext(t, http.MethodPut, "/tasks/"+uuid1.String(), task)
+		c.Request.Header.Set("If-Match", `"1"`)
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		taskService.On("GetTaskByID", mock.Anything, uuid1).
+			Return(&model.Task{ID: uuid1}, nil).Once()
+		taskService.On("UpdateTask", mock.Anything, uuid1, mock.AnythingOfType("*model.Task"), model.UpdateTaskOptions{Version: 1}).
+			Return(nil, service.ErrTaskCycle).Once()
+
+		taskHandler.UpdateTaskByID(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Equal(t, `{"code":"invalid_parent","message":"a task cannot be a subtask of itself or of its subtasks"}`, w.Body.String())
+	})
+
+	// Test case 3
+	t.Run("DeleteTaskByID: has subtasks", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodDelete, "/tasks/"+parentID.String(), nil)
+		c.Request.Header.Set("If-Match", `"1"`)
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: parentID.String()})
+
+		taskService.On("DeleteTask", mock.Anything, parentID, model.DeleteTaskOptions{Version: 1}).
+			Return(service.ErrTaskHasSubtasks).Once()
+
+		taskHandler.DeleteTaskByID(c)
+
+		require.Equal(t, http.StatusConflict, w.Code)
+		require.Contains(t, w.Body.String(), `"code":"has_subtasks"`)
+	})
+
+	// Test case 4
+	t.Run("DeleteTaskByID: cascade", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodDelete, "/tasks/"+parentID.String()+"?subtasks=cascade", nil)
+		c.Request.Header.Set("If-Match", `"1"`)
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: parentID.String()})
+
+		taskService.On("DeleteTask", mock.Anything, parentID, model.DeleteTaskOptions{Subtasks: model.SubtasksCascade, Version: 1}).
+			Return(nil).Once()
+
+		taskHandler.DeleteTaskByID(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+	})
+
+	// Test case 5
+	t.Run("DeleteTaskByID: invalid subtasks option", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodDelete, "/tasks/"+parentID.String()+"?subtasks=keep", nil)
+		c.Request.Header.Set("If-Match", `"1"`)
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: parentID.String()})
+
+		taskHandler.DeleteTaskByID(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Equal(t, `{"messages":{"subtasks":"it must be one of the following [cascade, orphan, reject]"}}`, w.Body.String())
+	})
+
+	// Test case 6
+	t.Run("GetSubtasks: success", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodGet, "/tasks/"+parentID.String()+"/subtasks", nil)
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: parentID.String()})
+
+		subtasks := []model.Task{{
+			ID:       uuid1,
+			Title:    "Task 1",
+			Status:   "pending",
+			ParentID: &parentID,
+			Progress: &model.TaskProgress{Subtasks: 4, Completed: 1, Percent: 25},
+		}}
+		taskService.On("GetSubtasks", mock.Anything, parentID).
+			Return(subtasks, nil).Once()
+
+		taskHandler.GetSubtasks(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), `"progress":{"subtasks":4,"completed":1,"percent":25}`)
+	})
+
+	// Test case 7
+	t.Run("GetSubtasks: not found", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodGet, "/tasks/"+parentID.String()+"/subtasks", nil)
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: parentID.String()})
+
+		taskService.On("GetSubtasks", mock.Anything, parentID).
+			Return(nil, errMockNotFound).Once()
+
+		taskHandler.GetSubtasks(c)
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+		require.Equal(t, `{"message":"task not found"}`, w.Body.String())
+	})
+}
+
+func Test_TaskDependencies(t *testing.T) {
+	taskService := new(mocks.ITaskService)
+	taskHandler := NewTaskHandler(taskService)
+	taskService.On("GetWorkflow", mock.Anything, mock.Anything).Return(workflow.Default(), nil).Maybe()
+	blockerID, _ := uuid.NewV7()
+
+	// Test case 1
+	t.Run("AddDependency: cycle", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/tasks/"+uuid1.String()+"/dependencies", model.DependencyRequest{BlockedByID: blockerID})
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		taskService.On("AddDependency", mock.Anything, uuid1, blockerID).
+			Return(service.ErrDependencyCycle).Once()
+
+		taskHandler.AddDependency(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Equal(t, `{"code":"invalid_dependency","message":"dependency would create a cycle"}`, w.Body.String())
+	})
+
+	// Test case 2
+	t.Run("AddDependency: success", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/tasks/"+uuid1.String()+"/dependencies", model.DependencyRequest{BlockedByID: blockerID})
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		taskService.On("AddDependency", mock.Anything, uuid1, blockerID).
+			Return(nil).Once()
+
+		taskHandler.AddDependency(c)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		require.Equal(t, `{"message":"Dependency added successfully"}`, w.Body.String())
+	})
+
+	// Test case 3
+	t.Run("RemoveDependency: not found", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodDelete, "/tasks/"+uuid1.String()+"/dependencies/"+blockerID.String(), nil)
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()}, gin.Param{Key: "blockerId", Value: blockerID.String()})
+
+		taskService.On("RemoveDependency", mock.Anything, uuid1, blockerID).
+			Return(errMockNotFound).Once()
+
+		taskHandler.RemoveDependency(c)
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	// Test case 4
+	t.Run("UpdateTaskByID: blocked", func(t *testing.T) {
+		task := model.Task{Title: "Task 1", Description: "Description 1", Status: "in-progress"}
+		c, w := newJSONContext(t, http.MethodPut, "/tasks/"+uuid1.String(), task)
+		c.Request.Header.Set("If-Match", `"1"`)
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		taskService.On("GetTaskByID", mock.Anything, uuid1).
+			Return(&model.Task{ID: uuid1, BlockedBy: []uuid.UUID{blockerID}}, nil).Once()
+		taskService.On("UpdateTask", mock.Anything, uuid1, mock.AnythingOfType("*model.Task"), model.UpdateTaskOptions{Version: 1}).
+			Return(nil, service.ErrTaskBlocked).Once()
+
+		taskHandler.UpdateTaskByID(c)
+
+		require.Equal(t, http.StatusConflict, w.Code)
+		require.Contains(t, w.Body.String(), `"code":"blocked"`)
+	})
+
+	// Test case 5
+	t.Run("UpdateTaskByID: forced", func(t *testing.T) {
+		task := model.Task{Title: "Task 1", Description: "Description 1", Status: "completed"}
+		c, w := newJSONContext(t, http.MethodPut, "/tasks/"+uuid1.String()+"?force=true", task)
+		c.Request.Header.Set("If-Match", `"1"`)
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		taskService.On("GetTaskByID", mock.Anything, uuid1).
+			Return(&model.Task{ID: uuid1}, nil).Once()
+		taskService.On("UpdateTask", mock.Anything, uuid1, mock.AnythingOfType("*model.Task"), model.UpdateTaskOptions{Force: true, Version: 1}).
+			Return(&model.Task{ID: uuid1, Version: 2}, nil).Once()
+
+		taskHandler.UpdateTaskByID(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+	})
+
+	// Test case 6
+	t.Run("GetTaskByID: dependencies", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodGet, "/tasks/"+uuid1.String(), nil)
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		taskService.On("GetTaskByID", mock.Anything, uuid1).
+			Return(&model.Task{ID: uuid1, BlockedBy: []uuid.UUID{blockerID}}, nil).Once()
+
+		taskHandler.GetTaskByID(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), `"blocked_by":["`+blockerID.String()+`"],"blocks":null`)
+	})
+}
+
+func Test_TransitionTask(t *testing.T) {
+	taskService := new(mocks.ITaskService)
+	taskHandler := NewTaskHandler(taskService)
+
+	tests := []struct {
+		name         string
+		action       string
+		call         func(*gin.Context)
+		err          error
+		expectedCode int
+		expectedResp string
+	}{
+		{
+			name:         "StartTask: already in progress",
+			action:       model.TaskActionStart,
+			call:         taskHandler.StartTask,
+			err:          &workflow.TransitionError{From: model.TaskStatusInProgress, To: model.TaskStatusInProgress, Category: model.CategoryActive},
+			expectedCode: http.StatusConflict,
+			expectedResp: `{"code":"invalid_transition","message":"task already in progress"}`,
+		},
+		{
+			name:         "CompleteTask: already completed",
+			action:       model.TaskActionComplete,
+			call:         taskHandler.CompleteTask,
+			err:          &workflow.TransitionError{From: model.TaskStatusCompleted, To: model.TaskStatusCompleted, Category: model.CategoryDone},
+			expectedCode: http.StatusConflict,
+			expectedResp: `{"code":"invalid_transition","message":"task already completed"}`,
+		},
+		{
+			name:         "CompleteTask: illegal transition",
+			action:       model.TaskActionComplete,
+			call:         taskHandler.CompleteTask,
+			err:          &workflow.TransitionError{From: model.TaskStatusPending, To: model.TaskStatusCompleted, Category: model.CategoryDone},
+			expectedCode: http.StatusConflict,
+			expectedResp: `{"code":"invalid_transition","message":"cannot move a task from pending to completed"}`,
+		},
+		{
+			name:         "ReopenTask: not completed",
+			action:       model.TaskActionReopen,
+			call:         taskHandler.ReopenTask,
+			err:          workflow.ErrNotDone,
+			expectedCode: http.StatusConflict,
+			expectedResp: `{"code":"invalid_transition","message":"task is not completed"}`,
+		},
+		{
+			name:         "StartTask: not found",
+			action:       model.TaskActionStart,
+			call:         taskHandler.StartTask,
+			err:          errMockNotFound,
+			expectedCode: http.StatusNotFound,
+			expectedResp: `{"message":"task not found"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, w := newJSONContext(t, http.MethodPost, "/tasks/"+uuid1.String()+"/"+tt.action, nil)
+			c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+			taskService.On("TransitionTask", mock.Anything, uuid1, tt.action, model.UpdateTaskOptions{}).
+				Return(nil, tt.err).Once()
+
+			tt.call(c)
+
+			require.Equal(t, tt.expectedCode, w.Code)
+			require.Equal(t, tt.expectedResp, w.Body.String())
+		})
+	}
+
+	// Test case 6
+	t.Run("CompleteTask: success", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/tasks/"+uuid1.String()+"/complete?force=true", nil)
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		task := &model.Task{ID: uuid1, Title: "Task 1", Status: model.TaskStatusCompleted}
+		taskService.On("TransitionTask", mock.Anything, uuid1, model.TaskActionComplete, model.UpdateTaskOptions{Force: true}).
+			Return(task, nil).Once()
+
+		taskHandler.CompleteTask(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), `"status":"completed"`)
+	})
+}
+
+func Test_GrantTask(t *testing.T) {
+	taskService := new(mocks.ITaskService)
+	taskHandler := NewTaskHandler(taskService)
+	uuid2, _ := uuid.NewV7()
+
+	// Test case 1
+	t.Run("GrantTask: not the owner", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/tasks/"+uuid1.String()+"/grants", model.GrantRequest{UserID: uuid2})
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		taskService.On("GrantTask", mock.Anything, uuid1, uuid2).
+			Return(service.ErrNotTaskOwner).Once()
+
+		taskHandler.GrantTask(c)
+
+		require.Equal(t, http.StatusForbidden, w.Code)
+		require.Equal(t, `{"code":"forbidden","message":"only the task owner can manage its access"}`, w.Body.String())
+	})
+
+	// Test case 2
+	t.Run("GrantTask: task not visible", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/tasks/"+uuid1.String()+"/grants", model.GrantRequest{UserID: uuid2})
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		taskService.On("GrantTask", mock.Anything, uuid1, uuid2).
+			Return(errMockNotFound).Once()
+
+		taskHandler.GrantTask(c)
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+		require.Equal(t, `{"message":"task not found"}`, w.Body.String())
+	})
+
+	// Test case 3
+	t.Run("GrantTask: success", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/tasks/"+uuid1.String()+"/grants", model.GrantRequest{UserID: uuid2})
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		taskService.On("GrantTask", mock.Anything, uuid1, uuid2).
+			Return(nil).Once()
+
+		taskHandler.GrantTask(c)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		require.Equal(t, `{"message":"Task access granted successfully"}`, w.Body.String())
+	})
+}
+
+func Test_GetAssignedTasks(t *testing.T) {
+	taskService := new(mocks.ITaskService)
+	taskHandler := NewTaskHandler(taskService)
+
+	// Test case 1
+	t.Run("GetAssignedTasks: unauthenticated", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/tasks/assigned", nil)
+
+		taskHandler.GetAssignedTasks(c)
+
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	// Test case 2
+	t.Run("GetAssignedTasks: success", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/tasks/assigned?status=pending", nil).WithContext(principalCtx)
+
+		filter := model.TaskFilter{Status: model.TaskStatusPending, AssigneeID: uuid1}
+		taskService.On("GetAllTasks", mock.Anything, filter).
+			Return([]model.Task{}, nil).Once()
+
+		taskHandler.GetAssignedTasks(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, `[]`, w.Body.String())
+		taskService.AssertExpectations(t)
+	})
+}
+
+func Test_AssignTask(t *testing.T) {
+	taskService := new(mocks.ITaskService)
+	taskHandler := NewTaskHandler(taskService)
+	uuid2, _ := uuid.NewV7()
+
+	// Test case 1
+	t.Run("AssignTask: input validation error", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/tasks/"+uuid1.String()+"/assignees", model.AssigneesRequest{})
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		taskHandler.AssignTask(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Equal(t, `{"messages":{"userids":"this is a required field"}}`, w.Body.String())
+	})
+
+	// Test case 2
+	t.Run("AssignTask: not a workspace member", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/tasks/"+uuid1.String()+"/assignees", model.AssigneesRequest{UserIDs: []uuid.UUID{uuid2}})
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		taskService.On("AssignTask", mock.Anything, uuid1, []uuid.UUID{uuid2}).
+			Return(service.ErrAssigneeNotMember).Once()
+
+		taskHandler.AssignTask(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Equal(t, `{"code":"invalid_assignee","message":"assignees must be members of the task's workspace"}`, w.Body.String())
+	})
+
+	// Test case 3
+	t.Run("AssignTask: task not visible", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/tasks/"+uuid1.String()+"/assignees", model.AssigneesRequest{UserIDs: []uuid.UUID{uuid2}})
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		taskService.On("AssignTask", mock.Anything, uuid1, []uuid.UUID{uuid2}).
+			Return(errMockNotFound).Once()
+
+		taskHandler.AssignTask(c)
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+		require.Equal(t, `{"message":"task not found"}`, w.Body.String())
+	})
+
+	// Test case 4
+	t.Run("AssignTask: success", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/tasks/"+uuid1.String()+"/assignees", model.AssigneesRequest{UserIDs: []uuid.UUID{uuid1, uuid2}})
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		taskService.On("AssignTask", mock.Anything, uuid1, []uuid.UUID{uuid1, uuid2}).
+			Return(nil).Once()
+
+		taskHandler.AssignTask(c)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		require.Equal(t, `{"message":"Task assigned successfully"}`, w.Body.String())
+	})
+}
+
+func Test_UnassignTask(t *testing.T) {
+	taskService := new(mocks.ITaskService)
+	taskHandler := NewTaskHandler(taskService)
+	uuid2, _ := uuid.NewV7()
+
+	// Test case 1
+	t.Run("UnassignTask: not assigned", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodDelete, "/tasks/"+uuid1.String()+"/assignees", model.AssigneesRequest{UserIDs: []uuid.UUID{uuid2}})
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		taskService.On("UnassignTask", mock.Anything, uuid1, []uuid.UUID{uuid2}).
+			Return(errMockNotFound).Once()
+
+		taskHandler.UnassignTask(c)
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+		require.Equal(t, `{"message":"assignee not found"}`, w.Body.String())
+	})
+
+	// Test case 2
+	t.Run("UnassignTask: success", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodDelete, "/tasks/"+uuid1.String()+"/assignees", model.AssigneesRequest{UserIDs: []uuid.UUID{uuid2}})
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		taskService.On("UnassignTask", mock.Anything, uuid1, []uuid.UUID{uuid2}).
+			Return(nil).Once()
+
+		taskHandler.UnassignTask(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, `{"message":"Task unassigned successfully"}`, w.Body.String())
+	})
+}
+
+func Test_GetTaskAssignmentLog(t *testing.T) {
+	taskService := new(mocks.ITaskService)
+	taskHandler := NewTaskHandler(taskService)
+
+	// Test case 1
+	t.Run("GetTaskAssignmentLog: success", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodGet, "/tasks/"+uuid1.String()+"/assignees/log", nil)
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		logs := []model.TaskAssignmentLog{{ID: uuid1, TaskID: uuid1, UserID: uuid1, Action: model.AssignmentAssigned, ActorID: uuid1}}
+		taskService.On("GetTaskAssignmentLog", mock.Anything, uuid1).
+			Return(logs, nil).Once()
+
+		taskHandler.GetTaskAssignmentLog(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var respObj []model.TaskAssignmentLog
+		require.Nil(t, json.Unmarshal(w.Body.Bytes(), &respObj))
+		require.Equal(t, logs, respObj)
+	})
+}
+
+func Test_AttachLabels(t *testing.T) {
+	taskService := new(mocks.ITaskService)
+	taskHandler := NewTaskHandler(taskService)
+	labelID, _ := uuid.NewV7()
+
+	// Test case 1
+	t.Run("AttachLabels: label of another workspace", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/tasks/"+uuid1.String()+"/labels", model.AttachLabelsRequest{LabelIDs: []uuid.UUID{labelID}})
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		taskService.On("AttachLabels", mock.Anything, uuid1, []uuid.UUID{labelID}).
+			Return(service.ErrLabelMismatch).Once()
+
+		taskHandler.AttachLabels(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Equal(t, `{"code":"invalid_label","message":"labels must belong to the workspace, or the owner, of the task"}`, w.Body.String())
+	})
+
+	// Test case 2
+	t.Run("AttachLabels: success", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/tasks/"+uuid1.String()+"/labels", model.AttachLabelsRequest{LabelIDs: []uuid.UUID{labelID}})
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		taskService.On("AttachLabels", mock.Anything, uuid1, []uuid.UUID{labelID}).
+			Return(nil).Once()
+
+		taskHandler.AttachLabels(c)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		require.Equal(t, `{"message":"Labels attached successfully"}`, w.Body.String())
+	})
+}
+
+func Test_DetachLabel(t *testing.T) {
+	taskService := new(mocks.ITaskService)
+	taskHandler := NewTaskHandler(taskService)
+	labelID, _ := uuid.NewV7()
+
+	// Test case 1
+	t.Run("DetachLabel: not attached", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodDelete, "/tasks/"+uuid1.String()+"/labels/"+labelID.String(), nil)
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()}, gin.Param{Key: "labelId", Value: labelID.String()})
+
+		taskService.On("DetachLabel", mock.Anything, uuid1, labelID).
+			Return(errMockNotFound).Once()
+
+		taskHandler.DetachLabel(c)
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+		require.Equal(t, `{"message":"label not found"}`, w.Body.String())
+	})
+
+	// Test case 2
+	t.Run("DetachLabel: success", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodDelete, "/tasks/"+uuid1.String()+"/labels/"+labelID.String(), nil)
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()}, gin.Param{Key: "labelId", Value: labelID.String()})
+
+		taskService.On("DetachLabel", mock.Anything, uuid1, labelID).
+			Return(nil).Once()
+
+		taskHandler.DetachLabel(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, `{"message":"Label detached successfully"}`, w.Body.String())
+	})
+}
+
 func TestHandleValidationError(t *testing.T) {
 	// Define the test cases
 	tests := []struct {
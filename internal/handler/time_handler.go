@@ -0,0 +1,273 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"task-manager/internal/model"
+	"task-manager/internal/service"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+type (
+	ITimeHandler interface {
+		StartTimer(*gin.Context)
+		StopTimer(*gin.Context)
+		StopRunningTimer(*gin.Context)
+		GetRunningTimer(*gin.Context)
+		GetWorkLogs(*gin.Context)
+		CreateWorkLog(*gin.Context)
+		DeleteWorkLog(*gin.Context)
+		GetTimesheet(*gin.Context)
+	}
+
+	TimeHandler struct {
+		TimeService service.ITimeService
+	}
+)
+
+func NewTimeHandler(timeService service.ITimeService) *TimeHandler {
+	return &TimeHandler{TimeService: timeService}
+}
+
+/*
+	Handler functions
+*/
+
+func (h *TimeHandler) StartTimer(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the task ID
+	taskId, err := uuid.FromString(c.Param("taskId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Bind the optional JSON body to the timer request
+	var req model.TimerRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		errMsg := handleValidationError(err)
+		c.JSON(http.StatusBadRequest, &model.Response{Messages: errMsg})
+		return
+	}
+
+	// Start the timer of the caller
+	log, err := h.TimeService.StartTimer(ctx, taskId, req.Note)
+	if err != nil {
+		handleTimeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, log)
+}
+
+func (h *TimeHandler) StopTimer(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the task ID
+	taskId, err := uuid.FromString(c.Param("taskId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Stop the timer of the caller, it becomes a work log
+	log, err := h.TimeService.StopTimer(ctx, taskId)
+	if err != nil {
+		handleTimeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, log)
+}
+
+func (h *TimeHandler) StopRunningTimer(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Stop the timer of the caller, whichever task it runs on
+	log, err := h.TimeService.StopRunningTimer(ctx)
+	if err != nil {
+		handleTimeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, log)
+}
+
+func (h *TimeHandler) GetRunningTimer(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Fetch the running timer of the caller
+	log, err := h.TimeService.GetRunningTimer(ctx)
+	if err != nil {
+		handleTimeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, log)
+}
+
+func (h *TimeHandler) GetWorkLogs(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the task ID
+	taskId, err := uuid.FromString(c.Param("taskId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Fetch the work logs of the task
+	logs, err := h.TimeService.GetWorkLogs(ctx, taskId)
+	if err != nil {
+		handleTimeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, logs)
+}
+
+func (h *TimeHandler) CreateWorkLog(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the task ID
+	taskId, err := uuid.FromString(c.Param("taskId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Bind the JSON body to the work log request
+	var req model.WorkLogRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errMsg := handleValidationError(err)
+		c.JSON(http.StatusBadRequest, &model.Response{Messages: errMsg})
+		return
+	}
+
+	// Record the time of the caller
+	log := model.WorkLog{StartedAt: req.StartedAt, EndedAt: &req.EndedAt, Note: req.Note}
+	if err := h.TimeService.CreateWorkLog(ctx, taskId, &log); err != nil {
+		handleTimeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, log)
+}
+
+func (h *TimeHandler) DeleteWorkLog(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the task and work log IDs
+	taskId, err := uuid.FromString(c.Param("taskId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+	workLogId, err := uuid.FromString(c.Param("workLogId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Delete the work log of the caller
+	if err := h.TimeService.DeleteWorkLog(ctx, taskId, workLogId); err != nil {
+		handleTimeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, &model.Response{Message: "Work log deleted successfully"})
+}
+
+func (h *TimeHandler) GetTimesheet(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Bind the query to the timesheet filter
+	var filter model.TimesheetFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		errMsg := handleValidationError(err)
+		if len(errMsg) == 0 {
+			c.JSON(http.StatusBadRequest, &model.Response{Message: ErrInvalidQuery})
+			return
+		}
+		c.JSON(http.StatusBadRequest, &model.Response{Messages: errMsg})
+		return
+	}
+
+	// Fetch the work logs of the visible tasks
+	timesheet, err := h.TimeService.GetTimesheet(ctx, filter)
+	if err != nil {
+		handleTimeError(c, err)
+		return
+	}
+
+	if filter.Format == "csv" {
+		c.Header("Content-Disposition", `attachment; filename="timesheet.csv"`)
+		c.Data(http.StatusOK, "text/csv; charset=utf-8", timesheetCSV(timesheet))
+		return
+	}
+	c.JSON(http.StatusOK, timesheet)
+}
+
+/*
+	Suporting functions
+*/
+
+// timesheetCSV writes a row per work log, with the hours to bill rounded
+// to the hundredth
+func timesheetCSV(timesheet *model.Timesheet) []byte {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"date", "user", "task_id", "task", "project_id", "started_at", "ended_at", "hours", "note"})
+	for _, e := range timesheet.Entries {
+		projectID := ""
+		if e.ProjectID != nil {
+			projectID = e.ProjectID.String()
+		}
+		w.Write([]string{
+			e.StartedAt.UTC().Format("2006-01-02"),
+			e.Username,
+			e.TaskID.String(),
+			csvSafe(e.TaskTitle),
+			projectID,
+			e.StartedAt.UTC().Format(time.RFC3339),
+			e.EndedAt.UTC().Format(time.RFC3339),
+			strconv.FormatFloat(float64(e.Seconds)/3600, 'f', 2, 64),
+			csvSafe(e.Note),
+		})
+	}
+	w.Flush()
+	return buf.Bytes()
+}
+
+// csvSafe keeps spreadsheets from running the text as a formula
+func csvSafe(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// handleTimeError writes the response of a failed time tracking operation
+func handleTimeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrTimerRunning):
+		c.JSON(http.StatusConflict, &model.Response{Code: "timer_running", Message: err.Error()})
+	case errors.Is(err, service.ErrNoRunningTimer), errors.Is(err, service.ErrWorkLogNotFound):
+		c.JSON(http.StatusNotFound, &model.Response{Message: err.Error()})
+	case errors.Is(err, service.ErrInvalidTimesheet):
+		c.JSON(http.StatusBadRequest, &model.Response{Message: err.Error()})
+	case strings.EqualFold(err.Error(), "record not found"):
+		c.JSON(http.StatusNotFound, &model.Response{Message: ErrTaskNotFound})
+	default:
+		c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+	}
+}
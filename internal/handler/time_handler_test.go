@@ -0,0 +1,206 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"task-manager/internal/mocks"
+	"task-manager/internal/model"
+	"task-manager/internal/service"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_StartTimer(t *testing.T) {
+	timeService := new(mocks.ITimeService)
+	timeHandler := NewTimeHandler(timeService)
+
+	// Test case 1
+	t.Run("StartTimer: timer running", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/tasks/"+uuid1.String()+"/timer/start", model.TimerRequest{})
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		timeService.On("StartTimer", mock.Anything, uuid1, "").
+			Return(nil, service.ErrTimerRunning).Once()
+
+		timeHandler.StartTimer(c)
+
+		require.Equal(t, http.StatusConflict, w.Code)
+		require.Equal(t, `{"code":"timer_running","message":"a timer is already running"}`, w.Body.String())
+	})
+
+	// Test case 2
+	t.Run("StartTimer: success without a body", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/tasks/"+uuid1.String()+"/timer/start", nil)
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		timeService.On("StartTimer", mock.Anything, uuid1, "").
+			Return(&model.WorkLog{ID: uuid1, TaskID: uuid1, Source: model.WorkLogTimer}, nil).Once()
+
+		timeHandler.StartTimer(c)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		require.Contains(t, w.Body.String(), `"ended_at":null`)
+	})
+}
+
+func Test_StopTimer(t *testing.T) {
+	timeService := new(mocks.ITimeService)
+	timeHandler := NewTimeHandler(timeService)
+
+	// Test case 1
+	t.Run("StopTimer: no running timer", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/tasks/"+uuid1.String()+"/timer/stop", nil)
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		timeService.On("StopTimer", mock.Anything, uuid1).
+			Return(nil, service.ErrNoRunningTimer).Once()
+
+		timeHandler.StopTimer(c)
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+		require.Equal(t, `{"message":"no timer is running on this task"}`, w.Body.String())
+	})
+
+	// Test case 2
+	t.Run("StopRunningTimer: success", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/timer/stop", nil)
+
+		timeService.On("StopRunningTimer", mock.Anything).
+			Return(&model.WorkLog{ID: uuid1, TaskID: uuid1, Seconds: 90}, nil).Once()
+
+		timeHandler.StopRunningTimer(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), `"seconds":90`)
+	})
+}
+
+func Test_CreateWorkLog(t *testing.T) {
+	timeService := new(mocks.ITimeService)
+	timeHandler := NewTimeHandler(timeService)
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	// Test case 1
+	t.Run("CreateWorkLog: ends before it starts", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/tasks/"+uuid1.String()+"/worklogs", model.WorkLogRequest{StartedAt: start, EndedAt: start.Add(-time.Hour)})
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		timeHandler.CreateWorkLog(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Equal(t, `{"messages":{"endedat":"it must be after startedat"}}`, w.Body.String())
+	})
+
+	// Test case 2
+	t.Run("CreateWorkLog: task not found", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/tasks/"+uuid1.String()+"/worklogs", model.WorkLogRequest{StartedAt: start, EndedAt: start.Add(time.Hour)})
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		timeService.On("CreateWorkLog", mock.Anything, uuid1, mock.AnythingOfType("*model.WorkLog")).
+			Return(errMockNotFound).Once()
+
+		timeHandler.CreateWorkLog(c)
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+		require.Equal(t, `{"message":"task not found"}`, w.Body.String())
+	})
+
+	// Test case 3
+	t.Run("CreateWorkLog: success", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/tasks/"+uuid1.String()+"/worklogs", model.WorkLogRequest{StartedAt: start, EndedAt: start.Add(90 * time.Minute), Note: "Review"})
+		c.Params = append(c.Params, gin.Param{Key: "taskId", Value: uuid1.String()})
+
+		timeService.On("CreateWorkLog", mock.Anything, uuid1, mock.MatchedBy(func(log *model.WorkLog) bool {
+			return log.StartedAt.Equal(start) && log.EndedAt.Equal(start.Add(90*time.Minute)) && log.Note == "Review"
+		})).Run(func(args mock.Arguments) {
+			args.Get(2).(*model.WorkLog).Seconds = 5400
+		}).Return(nil).Once()
+
+		timeHandler.CreateWorkLog(c)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		require.Contains(t, w.Body.String(), `"seconds":5400`)
+	})
+}
+
+func Test_GetTimesheet(t *testing.T) {
+	timeService := new(mocks.ITimeService)
+	timeHandler := NewTimeHandler(timeService)
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	timesheet := &model.Timesheet{
+		Entries: []model.TimesheetEntry{{
+			WorkLogID: uuid1, UserID: uuid1, Username: "user1", TaskID: uuid1, TaskTitle: "=HYPERLINK(\"x\")",
+			StartedAt: start, EndedAt: start.Add(90 * time.Minute), Seconds: 5400, Note: "Review, part 1",
+		}},
+		Totals:  []model.TimesheetTotal{{UserID: uuid1, Username: "user1", Seconds: 5400}},
+		Seconds: 5400,
+	}
+
+	// Test case 1
+	t.Run("GetTimesheet: invalid date", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/timesheet?from=03/02/2026", nil)
+
+		timeHandler.GetTimesheet(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Equal(t, `{"message":"invalid query parameters"}`, w.Body.String())
+	})
+
+	// Test case 2
+	t.Run("GetTimesheet: json", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/timesheet?user_id="+uuid1.String()+"&from=2026-03-01&to=2026-03-31", nil)
+
+		timeService.On("GetTimesheet", mock.Anything, mock.MatchedBy(func(filter model.TimesheetFilter) bool {
+			return filter.UserID == uuid1.String() && filter.From.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) &&
+				filter.To.Equal(time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC))
+		})).Return(timesheet, nil).Once()
+
+		timeHandler.GetTimesheet(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), `"totals":[{"user_id":"`+uuid1.String()+`","username":"user1","seconds":5400}],"seconds":5400`)
+	})
+
+	// Test case 3
+	t.Run("GetTimesheet: csv", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/timesheet?format=csv", nil)
+
+		timeService.On("GetTimesheet", mock.Anything, mock.AnythingOfType("model.TimesheetFilter")).
+			Return(timesheet, nil).Once()
+
+		timeHandler.GetTimesheet(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+		require.Equal(t, "date,user,task_id,task,project_id,started_at,ended_at,hours,note\n"+
+			"2026-03-02,user1,"+uuid1.String()+",\"'=HYPERLINK(\"\"x\"\")\",,2026-03-02T09:00:00Z,2026-03-02T10:30:00Z,1.50,\"Review, part 1\"\n",
+			w.Body.String())
+	})
+
+	// Test case 4
+	t.Run("GetTimesheet: range ends before it starts", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/timesheet?from=2026-03-31&to=2026-03-01", nil)
+
+		timeService.On("GetTimesheet", mock.Anything, mock.AnythingOfType("model.TimesheetFilter")).
+			Return(nil, service.ErrInvalidTimesheet).Once()
+
+		timeHandler.GetTimesheet(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Equal(t, `{"message":"the end of the range is before its start"}`, w.Body.String())
+	})
+}
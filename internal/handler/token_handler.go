@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"task-manager/internal/model"
+	"task-manager/internal/service"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+type (
+	ITokenHandler interface {
+		GetPersonalAccessTokens(*gin.Context)
+		CreatePersonalAccessToken(*gin.Context)
+		RevokePersonalAccessToken(*gin.Context)
+	}
+
+	TokenHandler struct {
+		TokenService service.ITokenService
+	}
+)
+
+const (
+	ErrPersonalAccessTokenNotFound = "personal access token not found"
+)
+
+func NewTokenHandler(tokenService service.ITokenService) *TokenHandler {
+	return &TokenHandler{TokenService: tokenService}
+}
+
+/*
+	Handler functions
+*/
+
+func (h *TokenHandler) GetPersonalAccessTokens(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Fetch the tokens of the caller
+	pats, err := h.TokenService.GetPersonalAccessTokens(ctx)
+	if err != nil {
+		handleTokenError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, pats)
+}
+
+func (h *TokenHandler) CreatePersonalAccessToken(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Bind the JSON body to the token request
+	var req model.CreatePersonalAccessTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errMsg := handleValidationError(err)
+		c.JSON(http.StatusBadRequest, &model.Response{Messages: errMsg})
+		return
+	}
+
+	pat := model.PersonalAccessToken{Name: req.Name, Scopes: req.Scopes}
+	if req.ExpiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, req.ExpiresInDays)
+		pat.ExpiresAt = &expiresAt
+	}
+
+	// Create the token, it is only shown in this response
+	token, err := h.TokenService.CreatePersonalAccessToken(ctx, &pat)
+	if err != nil {
+		handleTokenError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, &model.PersonalAccessTokenResponse{PersonalAccessToken: pat, Token: token})
+}
+
+func (h *TokenHandler) RevokePersonalAccessToken(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the token ID
+	tokenId, err := uuid.FromString(c.Param("tokenId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Revoke the token
+	if err := h.TokenService.RevokePersonalAccessToken(ctx, tokenId); err != nil {
+		handleTokenError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, &model.Response{Message: "Token revoked successfully"})
+}
+
+/*
+	Suporting functions
+*/
+
+// handleTokenError writes the response of a failed personal access token
+// operation
+func handleTokenError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrUnauthenticated):
+		c.JSON(http.StatusUnauthorized, &model.Response{Message: http.StatusText(http.StatusUnauthorized)})
+	case errors.Is(err, service.ErrPersonalAccessTokenNotAllowed):
+		c.JSON(http.StatusForbidden, &model.Response{Code: "forbidden", Message: err.Error()})
+	case strings.EqualFold(err.Error(), "record not found"):
+		c.JSON(http.StatusNotFound, &model.Response{Message: ErrPersonalAccessTokenNotFound})
+	default:
+		c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+	}
+}
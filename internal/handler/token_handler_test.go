@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"net/http"
+	"task-manager/internal/mocks"
+	"task-manager/internal/model"
+	"task-manager/internal/service"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CreatePersonalAccessToken(t *testing.T) {
+	tokenService := new(mocks.ITokenService)
+	tokenHandler := NewTokenHandler(tokenService)
+
+	// Test case 1
+	t.Run("CreatePersonalAccessToken: unknown scope", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/auth/tokens/", model.CreatePersonalAccessTokenRequest{Name: "ci", Scopes: []string{"users:manage"}})
+
+		tokenHandler.CreatePersonalAccessToken(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Equal(t, `{"messages":{"scopes[0]":"it must be one of the following [tasks:read, tasks:write, tasks:delete]"}}`, w.Body.String())
+	})
+
+	// Test case 2
+	t.Run("CreatePersonalAccessToken: from a personal access token", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/auth/tokens/", model.CreatePersonalAccessTokenRequest{Name: "ci", Scopes: []string{"tasks:read"}})
+
+		tokenService.On("CreatePersonalAccessToken", mock.Anything, mock.Anything).
+			Return("", service.ErrPersonalAccessTokenNotAllowed).Once()
+
+		tokenHandler.CreatePersonalAccessToken(c)
+
+		require.Equal(t, http.StatusForbidden, w.Code)
+		require.Equal(t, `{"code":"forbidden","message":"personal access tokens cannot manage personal access tokens"}`, w.Body.String())
+	})
+
+	// Test case 3
+	t.Run("CreatePersonalAccessToken: success", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/auth/tokens/", model.CreatePersonalAccessTokenRequest{Name: "ci", Scopes: []string{"tasks:read", "tasks:write"}, ExpiresInDays: 30})
+
+		tokenService.On("CreatePersonalAccessToken", mock.Anything, mock.MatchedBy(func(pat *model.PersonalAccessToken) bool {
+			return pat.Name == "ci" && len(pat.Scopes) == 2 && pat.ExpiresAt != nil
+		})).Return("tmpat_secret", nil).Once()
+
+		tokenHandler.CreatePersonalAccessToken(c)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		require.Contains(t, w.Body.String(), `"scopes":["tasks:read","tasks:write"]`)
+		require.Contains(t, w.Body.String(), `"token":"tmpat_secret"`)
+	})
+}
+
+func Test_RevokePersonalAccessToken(t *testing.T) {
+	tokenService := new(mocks.ITokenService)
+	tokenHandler := NewTokenHandler(tokenService)
+
+	// Test case 1
+	t.Run("RevokePersonalAccessToken: not found", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodDelete, "/auth/tokens/"+uuid1.String(), nil)
+		c.Params = append(c.Params, gin.Param{Key: "tokenId", Value: uuid1.String()})
+
+		tokenService.On("RevokePersonalAccessToken", mock.Anything, uuid1).
+			Return(errMockNotFound).Once()
+
+		tokenHandler.RevokePersonalAccessToken(c)
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+		require.Equal(t, `{"message":"personal access token not found"}`, w.Body.String())
+	})
+
+	// Test case 2
+	t.Run("RevokePersonalAccessToken: success", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodDelete, "/auth/tokens/"+uuid1.String(), nil)
+		c.Params = append(c.Params, gin.Param{Key: "tokenId", Value: uuid1.String()})
+
+		tokenService.On("RevokePersonalAccessToken", mock.Anything, uuid1).
+			Return(nil).Once()
+
+		tokenHandler.RevokePersonalAccessToken(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, `{"message":"Token revoked successfully"}`, w.Body.String())
+	})
+}
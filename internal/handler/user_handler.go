@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"task-manager/internal/model"
+	"task-manager/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+type (
+	IUserHandler interface {
+		UpdateUserRole(*gin.Context)
+	}
+
+	UserHandler struct {
+		UserService service.IUserService
+	}
+)
+
+const (
+	ErrUserNotFound = "user not found"
+)
+
+func NewUserHandler(userService service.IUserService) *UserHandler {
+	return &UserHandler{UserService: userService}
+}
+
+/*
+	Handler functions
+*/
+
+func (h *UserHandler) UpdateUserRole(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the user ID
+	userId, err := uuid.FromString(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Bind the JSON body to the role request
+	var req model.UpdateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errMsg := handleValidationError(err)
+		c.JSON(http.StatusBadRequest, &model.Response{Messages: errMsg})
+		return
+	}
+
+	// Update the role, it is carried by the tokens issued from now on
+	if err := h.UserService.UpdateUserRole(ctx, userId, req.Role); err != nil {
+		if strings.EqualFold(err.Error(), "record not found") {
+			c.JSON(http.StatusNotFound, &model.Response{Message: ErrUserNotFound})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	c.JSON(http.StatusOK, &model.Response{Message: "User role updated successfully"})
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"net/http"
+	"task-manager/internal/mocks"
+	"task-manager/internal/model"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_UpdateUserRole(t *testing.T) {
+	userService := new(mocks.IUserService)
+	userHandler := NewUserHandler(userService)
+
+	// Test case 1
+	t.Run("UpdateUserRole: invalid role", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPut, "/users/"+uuid1.String()+"/role", model.UpdateRoleRequest{Role: "owner"})
+		c.Params = append(c.Params, gin.Param{Key: "userId", Value: uuid1.String()})
+
+		userHandler.UpdateUserRole(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Equal(t, `{"messages":{"role":"it must be one of the following [admin, member, viewer]"}}`, w.Body.String())
+	})
+
+	// Test case 2
+	t.Run("UpdateUserRole: not found", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPut, "/users/"+uuid1.String()+"/role", model.UpdateRoleRequest{Role: "viewer"})
+		c.Params = append(c.Params, gin.Param{Key: "userId", Value: uuid1.String()})
+
+		userService.On("UpdateUserRole", mock.Anything, uuid1, "viewer").
+			Return(errMockNotFound).Once()
+
+		userHandler.UpdateUserRole(c)
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+		require.Equal(t, `{"message":"user not found"}`, w.Body.String())
+	})
+
+	// Test case 3
+	t.Run("UpdateUserRole: success", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPut, "/users/"+uuid1.String()+"/role", model.UpdateRoleRequest{Role: "admin"})
+		c.Params = append(c.Params, gin.Param{Key: "userId", Value: uuid1.String()})
+
+		userService.On("UpdateUserRole", mock.Anything, uuid1, "admin").
+			Return(nil).Once()
+
+		userHandler.UpdateUserRole(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, `{"message":"User role updated successfully"}`, w.Body.String())
+	})
+}
@@ -0,0 +1,269 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"task-manager/internal/model"
+	"task-manager/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+type (
+	IWorkspaceHandler interface {
+		GetWorkspaces(*gin.Context)
+		CreateWorkspace(*gin.Context)
+		GetWorkspaceByID(*gin.Context)
+		GetMembers(*gin.Context)
+		AddMember(*gin.Context)
+		UpdateMember(*gin.Context)
+		RemoveMember(*gin.Context)
+		CreateInvitation(*gin.Context)
+		AcceptInvitation(*gin.Context)
+	}
+
+	WorkspaceHandler struct {
+		WorkspaceService service.IWorkspaceService
+	}
+)
+
+const (
+	ErrWorkspaceNotFound = "workspace not found"
+	ErrMemberNotFound    = "member not found"
+)
+
+func NewWorkspaceHandler(workspaceService service.IWorkspaceService) *WorkspaceHandler {
+	return &WorkspaceHandler{WorkspaceService: workspaceService}
+}
+
+/*
+	Handler functions
+*/
+
+func (h *WorkspaceHandler) GetWorkspaces(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Fetch the workspaces of the caller
+	workspaces, err := h.WorkspaceService.GetWorkspaces(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	c.JSON(http.StatusOK, workspaces)
+}
+
+func (h *WorkspaceHandler) CreateWorkspace(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Bind the JSON body to the workspace request
+	var req model.CreateWorkspaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errMsg := handleValidationError(err)
+		c.JSON(http.StatusBadRequest, &model.Response{Messages: errMsg})
+		return
+	}
+
+	// Create the workspace, the caller becomes its owner
+	workspace := model.Workspace{Name: req.Name}
+	if err := h.WorkspaceService.CreateWorkspace(ctx, &workspace); err != nil {
+		c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	c.JSON(http.StatusCreated, workspace)
+}
+
+func (h *WorkspaceHandler) GetWorkspaceByID(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the workspace ID
+	workspaceId, err := uuid.FromString(c.Param("wsId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Fetch the workspace from the database
+	workspace, err := h.WorkspaceService.GetWorkspaceByID(ctx, workspaceId)
+	if err != nil {
+		handleWorkspaceError(c, err, ErrWorkspaceNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, workspace)
+}
+
+func (h *WorkspaceHandler) GetMembers(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the workspace ID
+	workspaceId, err := uuid.FromString(c.Param("wsId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Fetch the members of the workspace
+	members, err := h.WorkspaceService.GetMembers(ctx, workspaceId)
+	if err != nil {
+		handleWorkspaceError(c, err, ErrWorkspaceNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, members)
+}
+
+func (h *WorkspaceHandler) AddMember(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the workspace ID
+	workspaceId, err := uuid.FromString(c.Param("wsId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Bind the JSON body to the member request
+	var req model.AddMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errMsg := handleValidationError(err)
+		c.JSON(http.StatusBadRequest, &model.Response{Messages: errMsg})
+		return
+	}
+
+	// Add the user to the workspace
+	if err := h.WorkspaceService.AddMember(ctx, workspaceId, req.UserID, req.Role); err != nil {
+		handleWorkspaceError(c, err, ErrUserNotFound)
+		return
+	}
+
+	c.JSON(http.StatusCreated, &model.Response{Message: "Member added successfully"})
+}
+
+func (h *WorkspaceHandler) UpdateMember(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the workspace and user IDs
+	workspaceId, err := uuid.FromString(c.Param("wsId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+	userId, err := uuid.FromString(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Bind the JSON body to the member request
+	var req model.UpdateMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errMsg := handleValidationError(err)
+		c.JSON(http.StatusBadRequest, &model.Response{Messages: errMsg})
+		return
+	}
+
+	// Change the role of the member
+	if err := h.WorkspaceService.UpdateMemberRole(ctx, workspaceId, userId, req.Role); err != nil {
+		handleWorkspaceError(c, err, ErrMemberNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, &model.Response{Message: "Member updated successfully"})
+}
+
+func (h *WorkspaceHandler) RemoveMember(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the workspace and user IDs
+	workspaceId, err := uuid.FromString(c.Param("wsId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+	userId, err := uuid.FromString(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Remove the member from the workspace
+	if err := h.WorkspaceService.RemoveMember(ctx, workspaceId, userId); err != nil {
+		handleWorkspaceError(c, err, ErrMemberNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, &model.Response{Message: "Member removed successfully"})
+}
+
+func (h *WorkspaceHandler) CreateInvitation(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Validate the workspace ID
+	workspaceId, err := uuid.FromString(c.Param("wsId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	// Bind the JSON body to the invitation request
+	var req model.InvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errMsg := handleValidationError(err)
+		c.JSON(http.StatusBadRequest, &model.Response{Messages: errMsg})
+		return
+	}
+
+	// Sign the invitation, it is delivered to the invitee out of band
+	token, expiresAt, err := h.WorkspaceService.CreateInvitation(ctx, workspaceId, req.Email, req.Role)
+	if err != nil {
+		handleWorkspaceError(c, err, ErrWorkspaceNotFound)
+		return
+	}
+
+	c.JSON(http.StatusCreated, &model.InvitationResponse{Token: token, ExpiresAt: expiresAt})
+}
+
+func (h *WorkspaceHandler) AcceptInvitation(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Bind the JSON body to the accept request
+	var req model.AcceptInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errMsg := handleValidationError(err)
+		c.JSON(http.StatusBadRequest, &model.Response{Messages: errMsg})
+		return
+	}
+
+	// Join the workspace of the invitation
+	member, err := h.WorkspaceService.AcceptInvitation(ctx, req.Token)
+	if err != nil {
+		handleWorkspaceError(c, err, ErrWorkspaceNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+/*
+	Suporting functions
+*/
+
+// handleWorkspaceError writes the response of a failed workspace operation
+func handleWorkspaceError(c *gin.Context, err error, notFoundMsg string) {
+	switch {
+	case errors.Is(err, service.ErrWorkspaceForbidden), errors.Is(err, service.ErrInvitationMismatch):
+		c.JSON(http.StatusForbidden, &model.Response{Code: "forbidden", Message: err.Error()})
+	case errors.Is(err, service.ErrLastOwner):
+		c.JSON(http.StatusConflict, &model.Response{Message: err.Error()})
+	case errors.Is(err, service.ErrInvitationInvalid):
+		c.JSON(http.StatusBadRequest, &model.Response{Code: "invalid_invitation", Message: err.Error()})
+	case strings.EqualFold(err.Error(), "record not found"):
+		c.JSON(http.StatusNotFound, &model.Response{Message: notFoundMsg})
+	default:
+		c.JSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+	}
+}
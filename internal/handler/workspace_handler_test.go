@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"net/http"
+	"task-manager/internal/mocks"
+	"task-manager/internal/model"
+	"task-manager/internal/service"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CreateWorkspace(t *testing.T) {
+	workspaceService := new(mocks.IWorkspaceService)
+	workspaceHandler := NewWorkspaceHandler(workspaceService)
+
+	// Test case 1
+	t.Run("CreateWorkspace: input validation error", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/workspaces/", model.CreateWorkspaceRequest{})
+
+		workspaceHandler.CreateWorkspace(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Equal(t, `{"messages":{"name":"this is a required field"}}`, w.Body.String())
+	})
+
+	// Test case 2
+	t.Run("CreateWorkspace: success", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/workspaces/", model.CreateWorkspaceRequest{Name: "Team"})
+
+		workspaceService.On("CreateWorkspace", mock.Anything, mock.MatchedBy(func(ws *model.Workspace) bool {
+			return ws.Name == "Team"
+		})).Return(nil).Once()
+
+		workspaceHandler.CreateWorkspace(c)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		require.Contains(t, w.Body.String(), `"name":"Team"`)
+	})
+}
+
+func Test_GetWorkspaceByID(t *testing.T) {
+	workspaceService := new(mocks.IWorkspaceService)
+	workspaceHandler := NewWorkspaceHandler(workspaceService)
+
+	// Test case 1
+	t.Run("GetWorkspaceByID: invalid ID", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodGet, "/workspaces/abc", nil)
+		c.Params = append(c.Params, gin.Param{Key: "wsId", Value: "abc"})
+
+		workspaceHandler.GetWorkspaceByID(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	// Test case 2
+	t.Run("GetWorkspaceByID: not a member", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodGet, "/workspaces/"+uuid1.String(), nil)
+		c.Params = append(c.Params, gin.Param{Key: "wsId", Value: uuid1.String()})
+
+		workspaceService.On("GetWorkspaceByID", mock.Anything, uuid1).
+			Return(nil, errMockNotFound).Once()
+
+		workspaceHandler.GetWorkspaceByID(c)
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+		require.Equal(t, `{"message":"workspace not found"}`, w.Body.String())
+	})
+}
+
+func Test_AddMember(t *testing.T) {
+	workspaceService := new(mocks.IWorkspaceService)
+	workspaceHandler := NewWorkspaceHandler(workspaceService)
+	userId, _ := uuid.NewV7()
+
+	// Test case 1
+	t.Run("AddMember: invalid role", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/workspaces/"+uuid1.String()+"/members", model.AddMemberRequest{UserID: userId, Role: "viewer"})
+		c.Params = append(c.Params, gin.Param{Key: "wsId", Value: uuid1.String()})
+
+		workspaceHandler.AddMember(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Equal(t, `{"messages":{"role":"it must be one of the following [owner, admin, member]"}}`, w.Body.String())
+	})
+
+	// Test case 2
+	t.Run("AddMember: forbidden", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/workspaces/"+uuid1.String()+"/members", model.AddMemberRequest{UserID: userId, Role: "owner"})
+		c.Params = append(c.Params, gin.Param{Key: "wsId", Value: uuid1.String()})
+
+		workspaceService.On("AddMember", mock.Anything, uuid1, userId, "owner").
+			Return(service.ErrWorkspaceForbidden).Once()
+
+		workspaceHandler.AddMember(c)
+
+		require.Equal(t, http.StatusForbidden, w.Code)
+		require.Equal(t, `{"code":"forbidden","message":"insufficient workspace role"}`, w.Body.String())
+	})
+
+	// Test case 3
+	t.Run("AddMember: success", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/workspaces/"+uuid1.String()+"/members", model.AddMemberRequest{UserID: userId, Role: "member"})
+		c.Params = append(c.Params, gin.Param{Key: "wsId", Value: uuid1.String()})
+
+		workspaceService.On("AddMember", mock.Anything, uuid1, userId, "member").
+			Return(nil).Once()
+
+		workspaceHandler.AddMember(c)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		require.Equal(t, `{"message":"Member added successfully"}`, w.Body.String())
+	})
+}
+
+func Test_RemoveMember(t *testing.T) {
+	workspaceService := new(mocks.IWorkspaceService)
+	workspaceHandler := NewWorkspaceHandler(workspaceService)
+
+	// Test case 1
+	t.Run("RemoveMember: last owner", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodDelete, "/workspaces/"+uuid1.String()+"/members/"+uuid1.String(), nil)
+		c.Params = append(c.Params, gin.Param{Key: "wsId", Value: uuid1.String()}, gin.Param{Key: "userId", Value: uuid1.String()})
+
+		workspaceService.On("RemoveMember", mock.Anything, uuid1, uuid1).
+			Return(service.ErrLastOwner).Once()
+
+		workspaceHandler.RemoveMember(c)
+
+		require.Equal(t, http.StatusConflict, w.Code)
+		require.Equal(t, `{"message":"a workspace must keep at least one owner"}`, w.Body.String())
+	})
+}
+
+func Test_Invitations(t *testing.T) {
+	workspaceService := new(mocks.IWorkspaceService)
+	workspaceHandler := NewWorkspaceHandler(workspaceService)
+
+	// Test case 1
+	t.Run("CreateInvitation: success", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/workspaces/"+uuid1.String()+"/invitations", model.InvitationRequest{Email: "bob@example.com", Role: "member"})
+		c.Params = append(c.Params, gin.Param{Key: "wsId", Value: uuid1.String()})
+
+		expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+		workspaceService.On("CreateInvitation", mock.Anything, uuid1, "bob@example.com", "member").
+			Return("invitation-token", expiresAt, nil).Once()
+
+		workspaceHandler.CreateInvitation(c)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		require.Equal(t, `{"token":"invitation-token","expires_at":"2030-01-01T00:00:00Z"}`, w.Body.String())
+	})
+
+	// Test case 2
+	t.Run("AcceptInvitation: invalid token", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/invitations/accept", model.AcceptInvitationRequest{Token: "bad"})
+
+		workspaceService.On("AcceptInvitation", mock.Anything, "bad").
+			Return(nil, service.ErrInvitationInvalid).Once()
+
+		workspaceHandler.AcceptInvitation(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Equal(t, `{"code":"invalid_invitation","message":"invitation is invalid or expired"}`, w.Body.String())
+	})
+
+	// Test case 3
+	t.Run("AcceptInvitation: other email", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/invitations/accept", model.AcceptInvitationRequest{Token: "other"})
+
+		workspaceService.On("AcceptInvitation", mock.Anything, "other").
+			Return(nil, service.ErrInvitationMismatch).Once()
+
+		workspaceHandler.AcceptInvitation(c)
+
+		require.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	// Test case 4
+	t.Run("AcceptInvitation: success", func(t *testing.T) {
+		c, w := newJSONContext(t, http.MethodPost, "/invitations/accept", model.AcceptInvitationRequest{Token: "good"})
+
+		member := &model.WorkspaceMember{WorkspaceID: uuid1, UserID: uuid1, Role: model.WorkspaceRoleMember}
+		workspaceService.On("AcceptInvitation", mock.Anything, "good").
+			Return(member, nil).Once()
+
+		workspaceHandler.AcceptInvitation(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), `"role":"member"`)
+	})
+}
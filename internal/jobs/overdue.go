@@ -0,0 +1,63 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"task-manager/internal/events"
+	"task-manager/internal/service"
+	"time"
+)
+
+// DefaultOverdueInterval is how often the overdue job looks for tasks
+// past their due date
+const DefaultOverdueInterval = time.Minute
+
+// OverdueJob periodically marks the tasks past their due date, and
+// publishes a TaskOverdue event for each of them
+type OverdueJob struct {
+	TaskService service.ITaskService
+	Bus         *events.Bus
+	Interval    time.Duration
+
+	// now is replaced in tests
+	now func() time.Time
+}
+
+func NewOverdueJob(taskService service.ITaskService, bus *events.Bus, interval time.Duration) *OverdueJob {
+	if interval <= 0 {
+		interval = DefaultOverdueInterval
+	}
+	return &OverdueJob{TaskService: taskService, Bus: bus, Interval: interval, now: time.Now}
+}
+
+// Run checks for overdue tasks every interval, until ctx is done
+func (j *OverdueJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := j.RunOnce(ctx); err != nil {
+			log.Printf("overdue job: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce marks the tasks that became overdue since the last run and
+// publishes their events
+func (j *OverdueJob) RunOnce(ctx context.Context) error {
+	tasks, err := j.TaskService.MarkOverdueTasks(j.now())
+	if err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		j.Bus.Publish(ctx, events.TaskOverdue{Task: task})
+	}
+	return nil
+}
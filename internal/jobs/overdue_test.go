@@ -0,0 +1,57 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"task-manager/internal/events"
+	"task-manager/internal/mocks"
+	"task-manager/internal/model"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOverdueJob_RunOnce(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	taskID, _ := uuid.NewV7()
+
+	// Test case 1
+	t.Run("RunOnce: publishes the overdue tasks", func(t *testing.T) {
+		taskService := new(mocks.ITaskService)
+		bus := events.NewBus()
+		job := NewOverdueJob(taskService, bus, 0)
+		job.now = func() time.Time { return now }
+		require.Equal(t, DefaultOverdueInterval, job.Interval)
+
+		var published []uuid.UUID
+		bus.Subscribe(events.TaskOverdueEvent, func(ctx context.Context, e events.Event) {
+			published = append(published, e.(events.TaskOverdue).Task.ID)
+		})
+
+		taskService.On("MarkOverdueTasks", now).
+			Return([]model.Task{{ID: taskID, OverdueAt: &now}}, nil).Once()
+
+		require.NoError(t, job.RunOnce(context.Background()))
+		require.Equal(t, []uuid.UUID{taskID}, published)
+		taskService.AssertExpectations(t)
+	})
+
+	// Test case 2
+	t.Run("RunOnce: error", func(t *testing.T) {
+		taskService := new(mocks.ITaskService)
+		bus := events.NewBus()
+		job := NewOverdueJob(taskService, bus, time.Second)
+		job.now = func() time.Time { return now }
+
+		bus.Subscribe(events.TaskOverdueEvent, func(ctx context.Context, e events.Event) {
+			t.Fatal("unexpected event")
+		})
+
+		errMock := errors.New("internal error")
+		taskService.On("MarkOverdueTasks", now).Return(nil, errMock).Once()
+
+		require.ErrorIs(t, job.RunOnce(context.Background()), errMock)
+	})
+}
@@ -0,0 +1,69 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"task-manager/internal/service"
+	"time"
+)
+
+const (
+	// DefaultTrashRetention is how long deleted tasks stay in the trash
+	DefaultTrashRetention = 30 * 24 * time.Hour
+
+	// DefaultPurgeInterval is how often the purge job looks for tasks
+	// past their retention
+	DefaultPurgeInterval = time.Hour
+)
+
+// PurgeJob periodically deletes the tasks that stayed in the trash longer
+// than the retention period
+type PurgeJob struct {
+	TaskService service.ITaskService
+	Retention   time.Duration
+	Interval    time.Duration
+
+	// now is replaced in tests
+	now func() time.Time
+}
+
+func NewPurgeJob(taskService service.ITaskService, retention, interval time.Duration) *PurgeJob {
+	if retention <= 0 {
+		retention = DefaultTrashRetention
+	}
+	if interval <= 0 {
+		interval = DefaultPurgeInterval
+	}
+	return &PurgeJob{TaskService: taskService, Retention: retention, Interval: interval, now: time.Now}
+}
+
+// Run purges the trash every interval, until ctx is done
+func (j *PurgeJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := j.RunOnce(ctx); err != nil {
+			log.Printf("purge job: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce permanently deletes the tasks deleted before the retention
+// period
+func (j *PurgeJob) RunOnce(ctx context.Context) error {
+	purged, err := j.TaskService.PurgeDeletedTasks(j.now().Add(-j.Retention))
+	if err != nil {
+		return err
+	}
+	if purged > 0 {
+		log.Printf("purge job: deleted %d tasks from the trash", purged)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"task-manager/internal/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPurgeJob_RunOnce(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// Test case 1
+	t.Run("RunOnce: purges past the retention", func(t *testing.T) {
+		taskService := new(mocks.ITaskService)
+		job := NewPurgeJob(taskService, 0, 0)
+		job.now = func() time.Time { return now }
+		require.Equal(t, DefaultTrashRetention, job.Retention)
+		require.Equal(t, DefaultPurgeInterval, job.Interval)
+
+		taskService.On("PurgeDeletedTasks", now.Add(-DefaultTrashRetention)).
+			Return(int64(2), nil).Once()
+
+		require.NoError(t, job.RunOnce(context.Background()))
+		taskService.AssertExpectations(t)
+	})
+
+	// Test case 2
+	t.Run("RunOnce: error", func(t *testing.T) {
+		taskService := new(mocks.ITaskService)
+		job := NewPurgeJob(taskService, 24*time.Hour, time.Minute)
+		job.now = func() time.Time { return now }
+
+		taskService.On("PurgeDeletedTasks", now.Add(-24*time.Hour)).
+			Return(int64(0), errors.New("db down")).Once()
+
+		require.EqualError(t, job.RunOnce(context.Background()), "db down")
+	})
+}
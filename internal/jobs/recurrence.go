@@ -0,0 +1,59 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"task-manager/internal/service"
+	"time"
+)
+
+// DefaultRecurrenceInterval is how often the recurrence job looks for the
+// occurrences of the recurring tasks to create
+const DefaultRecurrenceInterval = time.Minute
+
+// RecurrenceJob periodically creates the tasks of the recurring tasks,
+// when the time of their next occurrence arrives or once their previous
+// task is done
+type RecurrenceJob struct {
+	RecurrenceService service.IRecurrenceService
+	Interval          time.Duration
+
+	// now is replaced in tests
+	now func() time.Time
+}
+
+func NewRecurrenceJob(recurrenceService service.IRecurrenceService, interval time.Duration) *RecurrenceJob {
+	if interval <= 0 {
+		interval = DefaultRecurrenceInterval
+	}
+	return &RecurrenceJob{RecurrenceService: recurrenceService, Interval: interval, now: time.Now}
+}
+
+// Run creates the due occurrences every interval, until ctx is done
+func (j *RecurrenceJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := j.RunOnce(ctx); err != nil {
+			log.Printf("recurrence job: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce creates the tasks of the occurrences due since the last run. The
+// templates that fail are returned in the error, and retried on the next
+// run, without holding back the others.
+func (j *RecurrenceJob) RunOnce(ctx context.Context) error {
+	tasks, err := j.RecurrenceService.MaterializeDue(j.now())
+	if len(tasks) > 0 {
+		log.Printf("recurrence job: created %d tasks", len(tasks))
+	}
+	return err
+}
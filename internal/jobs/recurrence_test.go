@@ -0,0 +1,55 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"task-manager/internal/mocks"
+	"task-manager/internal/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecurrenceJob_RunOnce(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// Test case 1
+	t.Run("RunOnce: creates the due occurrences", func(t *testing.T) {
+		recurrenceService := new(mocks.IRecurrenceService)
+		job := NewRecurrenceJob(recurrenceService, 0)
+		job.now = func() time.Time { return now }
+		require.Equal(t, DefaultRecurrenceInterval, job.Interval)
+
+		recurrenceService.On("MaterializeDue", now).
+			Return([]model.Task{{Title: "Weekly report"}}, nil).Once()
+
+		require.NoError(t, job.RunOnce(context.Background()))
+		recurrenceService.AssertExpectations(t)
+	})
+
+	// Test case 2
+	t.Run("RunOnce: error", func(t *testing.T) {
+		recurrenceService := new(mocks.IRecurrenceService)
+		job := NewRecurrenceJob(recurrenceService, time.Second)
+		job.now = func() time.Time { return now }
+
+		recurrenceService.On("MaterializeDue", now).
+			Return(nil, errors.New("db down")).Once()
+
+		require.EqualError(t, job.RunOnce(context.Background()), "db down")
+	})
+
+	// Test case 3
+	t.Run("RunOnce: some templates fail", func(t *testing.T) {
+		recurrenceService := new(mocks.IRecurrenceService)
+		job := NewRecurrenceJob(recurrenceService, time.Second)
+		job.now = func() time.Time { return now }
+
+		recurrenceService.On("MaterializeDue", now).
+			Return([]model.Task{{Title: "Weekly report"}}, errors.New("recurring task 1: record not found")).Once()
+
+		require.EqualError(t, job.RunOnce(context.Background()), "recurring task 1: record not found")
+		recurrenceService.AssertExpectations(t)
+	})
+}
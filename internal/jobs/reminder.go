@@ -0,0 +1,85 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"task-manager/internal/notify"
+	"task-manager/internal/service"
+	"time"
+)
+
+const (
+	// DefaultReminderInterval is how often the reminder job looks for the
+	// reminders to send
+	DefaultReminderInterval = 30 * time.Second
+
+	// DefaultReminderBatch is how many reminders a run sends at most
+	DefaultReminderBatch = 100
+)
+
+// ReminderJob periodically sends the due reminders through the notifier
+// of their channel. The reminders are stored, so the ones due while the
+// server was down are sent on the first run.
+type ReminderJob struct {
+	ReminderService service.IReminderService
+	Notifiers       notify.Notifiers
+	Interval        time.Duration
+	Batch           int
+
+	// now is replaced in tests
+	now func() time.Time
+}
+
+func NewReminderJob(reminderService service.IReminderService, notifiers notify.Notifiers, interval time.Duration) *ReminderJob {
+	if interval <= 0 {
+		interval = DefaultReminderInterval
+	}
+	return &ReminderJob{
+		ReminderService: reminderService,
+		Notifiers:       notifiers,
+		Interval:        interval,
+		Batch:           DefaultReminderBatch,
+		now:             time.Now,
+	}
+}
+
+// Run sends the due reminders every interval, until ctx is done
+func (j *ReminderJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := j.RunOnce(ctx); err != nil {
+			log.Printf("reminder job: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce sends the reminders due since the last run. Failed deliveries
+// are retried by later runs.
+func (j *ReminderJob) RunOnce(ctx context.Context) error {
+	messages, err := j.ReminderService.ClaimDueReminders(j.now(), j.Batch)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range messages {
+		if err := j.Notifiers.Notify(ctx, m); err != nil {
+			log.Printf("reminder job: reminder %s: %v", m.ReminderID, err)
+			if err := j.ReminderService.MarkFailed(m.ReminderID, j.now(), err); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := j.ReminderService.MarkSent(m.ReminderID, j.now()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
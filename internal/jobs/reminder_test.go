@@ -0,0 +1,97 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"task-manager/internal/mocks"
+	"task-manager/internal/notify"
+	"task-manager/internal/notify/notifytest"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReminderJob_RunOnce(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	taskID, _ := uuid.NewV7()
+	userID, _ := uuid.NewV7()
+	newMessage := func(channel string) notify.Message {
+		id, _ := uuid.NewV7()
+		return notify.Message{ReminderID: id, TaskID: taskID, UserID: userID, Channel: channel, Subject: "Reminder: Ship", Body: "Ship is due", Email: "alice@example.com"}
+	}
+
+	smtpServer, err := notifytest.NewSMTPServer()
+	require.NoError(t, err)
+	defer smtpServer.Close()
+
+	webhookStatus := http.StatusOK
+	webhookServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(webhookStatus)
+	}))
+	defer webhookServer.Close()
+
+	inbox := new(mocks.INotificationService)
+	notifiers := notify.Notifiers{
+		notify.ChannelInbox:   inbox,
+		notify.ChannelEmail:   notify.NewSMTPNotifier(notify.SMTPConfig{Addr: smtpServer.Addr}),
+		notify.ChannelWebhook: notify.NewWebhookNotifier(notify.WebhookConfig{URL: webhookServer.URL}),
+	}
+
+	// Test case 1
+	t.Run("RunOnce: delivers through every channel", func(t *testing.T) {
+		reminderService := new(mocks.IReminderService)
+		job := NewReminderJob(reminderService, notifiers, 0)
+		job.now = func() time.Time { return now }
+		require.Equal(t, DefaultReminderInterval, job.Interval)
+
+		toInbox, toEmail, toWebhook := newMessage(notify.ChannelInbox), newMessage(notify.ChannelEmail), newMessage(notify.ChannelWebhook)
+		reminderService.On("ClaimDueReminders", now, DefaultReminderBatch).
+			Return([]notify.Message{toInbox, toEmail, toWebhook}, nil).Once()
+		inbox.On("Notify", mock.Anything, toInbox).Return(nil).Once()
+		for _, m := range []notify.Message{toInbox, toEmail, toWebhook} {
+			reminderService.On("MarkSent", m.ReminderID, now).Return(nil).Once()
+		}
+
+		require.NoError(t, job.RunOnce(context.Background()))
+		reminderService.AssertExpectations(t)
+		inbox.AssertExpectations(t)
+		require.Len(t, smtpServer.Mails(), 1)
+	})
+
+	// Test case 2
+	t.Run("RunOnce: failed deliveries are retried", func(t *testing.T) {
+		reminderService := new(mocks.IReminderService)
+		job := NewReminderJob(reminderService, notify.Notifiers{notify.ChannelWebhook: notifiers[notify.ChannelWebhook]}, time.Minute)
+		job.now = func() time.Time { return now }
+		webhookStatus = http.StatusBadGateway
+		defer func() { webhookStatus = http.StatusOK }()
+
+		toWebhook, toEmail := newMessage(notify.ChannelWebhook), newMessage(notify.ChannelEmail)
+		reminderService.On("ClaimDueReminders", now, DefaultReminderBatch).
+			Return([]notify.Message{toWebhook, toEmail}, nil).Once()
+		reminderService.On("MarkFailed", toWebhook.ReminderID, now, mock.MatchedBy(func(err error) bool {
+			return errors.Is(err, notify.ErrDeliveryRejected)
+		})).Return(nil).Once()
+		reminderService.On("MarkFailed", toEmail.ReminderID, now, notify.ErrChannelDisabled).Return(nil).Once()
+
+		require.NoError(t, job.RunOnce(context.Background()))
+		reminderService.AssertExpectations(t)
+	})
+
+	// Test case 3
+	t.Run("RunOnce: error", func(t *testing.T) {
+		reminderService := new(mocks.IReminderService)
+		job := NewReminderJob(reminderService, notifiers, time.Minute)
+		job.now = func() time.Time { return now }
+
+		reminderService.On("ClaimDueReminders", now, DefaultReminderBatch).
+			Return(nil, errors.New("db down")).Once()
+
+		require.EqualError(t, job.RunOnce(context.Background()), "db down")
+	})
+}
@@ -0,0 +1,33 @@
+// Package mention finds the @username mentions of a text.
+package mention
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Max is the most mentions Parse returns, the others are ignored
+const Max = 50
+
+// Usernames are alphanumeric and 3 to 50 characters long. A mention does
+// not follow a word character, so email addresses are not mentions.
+var pattern = regexp.MustCompile(`(?:^|[^\w@])@([A-Za-z0-9]{3,50})\b`)
+
+// Parse returns the usernames mentioned in the text, lower cased, once
+// each and in order of appearance
+func Parse(text string) []string {
+	var usernames []string
+	seen := make(map[string]bool)
+	for _, m := range pattern.FindAllStringSubmatch(text, -1) {
+		username := strings.ToLower(m[1])
+		if seen[username] {
+			continue
+		}
+		seen[username] = true
+		usernames = append(usernames, username)
+		if len(usernames) == Max {
+			break
+		}
+	}
+	return usernames
+}
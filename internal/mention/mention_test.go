@@ -0,0 +1,41 @@
+package mention
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "no mention", text: "Looks good to me", want: nil},
+		{name: "mentions", text: "@alice can you review this with @Bob?", want: []string{"alice", "bob"}},
+		{name: "adjacent mentions", text: "@alice,@bob\n@carol", want: []string{"alice", "bob", "carol"}},
+		{name: "repeated mentions", text: "@alice and @ALICE and @alice", want: []string{"alice"}},
+		{name: "punctuation", text: "Thanks (@alice). Ask @bob's team.", want: []string{"alice", "bob"}},
+		{name: "email addresses", text: "Mail alice@example.com or @@bob", want: nil},
+		{name: "too short", text: "@al", want: nil},
+		{name: "too long", text: "@" + strings.Repeat("a", 51), want: nil},
+		{name: "not alphanumeric", text: "@alice_smith", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run("Parse: "+tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, Parse(tt.text))
+		})
+	}
+
+	// Test case 10
+	t.Run("Parse: at most Max mentions", func(t *testing.T) {
+		var text strings.Builder
+		for i := 0; i < Max+10; i++ {
+			text.WriteString(" @user" + strings.Repeat("x", i%40) + string(rune('a'+i%26)))
+		}
+		require.Len(t, Parse(text.String()), Max)
+	})
+}
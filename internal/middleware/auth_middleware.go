@@ -1,34 +1,131 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 	"strings"
+	"task-manager/internal/auth"
+	"task-manager/internal/model"
+	"task-manager/internal/service"
 
 	"github.com/gin-gonic/gin"
 )
 
-func AuthMiddleware(c *gin.Context) {
-	tokenString := c.GetHeader("Authorization")
-	if tokenString == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"message": "Authorization header missing"})
-		c.Abort()
-		return
+// Machine-readable reasons returned in the "code" field of a 401 response
+const (
+	ReasonMissingToken     = "missing_token"
+	ReasonMalformedToken   = "malformed_token"
+	ReasonInvalidSignature = "invalid_signature"
+	ReasonExpiredToken     = "expired_token"
+	ReasonTokenNotYetValid = "token_not_yet_valid"
+	ReasonInvalidIssuer    = "invalid_issuer"
+	ReasonInvalidAudience  = "invalid_audience"
+	ReasonRevokedToken     = "revoked_token"
+	ReasonMFAPending       = "mfa_pending"
+	ReasonInvalidToken     = "invalid_token"
+)
+
+// AuthMiddleware authenticates the bearer token of the request. JWTs are
+// checked against the signing key and the revocation list, personal access
+// tokens against their stored hash.
+func AuthMiddleware(tokenService service.ITokenService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenString := c.GetHeader("Authorization")
+		if tokenString == "" {
+			abortUnauthorized(c, ReasonMissingToken, "Authorization header missing")
+			return
+		}
+
+		// Personal access tokens are opaque, they are looked up instead
+		if fields := strings.Fields(tokenString); len(fields) == 2 && fields[0] == "Bearer" && auth.IsPersonalAccessToken(fields[1]) {
+			authenticatePersonalAccessToken(c, tokenService, fields[1])
+			return
+		}
+
+		// Validate the input token
+		if !validateInputToken(tokenString) {
+			abortUnauthorized(c, ReasonMalformedToken, "invalid authorization header format")
+			return
+		}
+
+		// Parse the token, and extract the claims
+		claims, err := auth.ParseToken(strings.Fields(tokenString)[1])
+		if err != nil {
+			abortUnauthorized(c, reasonFor(err), err.Error())
+			return
+		}
+
+		// Reject tokens revoked before their expiry, e.g. on logout
+		revoked, err := tokenService.IsAccessTokenRevoked(c.Request.Context(), claims.Id)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+			return
+		}
+		if revoked {
+			abortUnauthorized(c, ReasonRevokedToken, "token has been revoked")
+			return
+		}
+
+		// Expose the principal to the handlers and, through the request
+		// context, to the services
+		setPrincipal(c, auth.NewPrincipal(claims))
+
+		// Call the next handler
+		c.Next()
 	}
+}
 
-	// Validate the input token
-	if !validateInputToken(tokenString) {
-		c.JSON(http.StatusUnauthorized, gin.H{"message": "invalid authorization header format"})
-		c.Abort()
+func authenticatePersonalAccessToken(c *gin.Context, tokenService service.ITokenService, token string) {
+	principal, err := tokenService.AuthenticatePersonalAccessToken(c.Request.Context(), token)
+	switch {
+	case errors.Is(err, service.ErrPersonalAccessTokenInvalid):
+		abortUnauthorized(c, ReasonInvalidToken, err.Error())
+		return
+	case errors.Is(err, service.ErrPersonalAccessTokenRevoked):
+		abortUnauthorized(c, ReasonRevokedToken, err.Error())
+		return
+	case errors.Is(err, service.ErrPersonalAccessTokenExpired):
+		abortUnauthorized(c, ReasonExpiredToken, err.Error())
+		return
+	case err != nil:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
 		return
 	}
 
-	// Parse the token, and extract the claims
-	// codes..
-
-	// Call the next handler
+	setPrincipal(c, principal)
 	c.Next()
 }
 
+func setPrincipal(c *gin.Context, principal *auth.Principal) {
+	c.Set(auth.PrincipalKey, principal)
+	c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
+}
+
+func abortUnauthorized(c *gin.Context, reason, message string) {
+	c.AbortWithStatusJSON(http.StatusUnauthorized, &model.Response{Code: reason, Message: message})
+}
+
+func reasonFor(err error) string {
+	switch {
+	case errors.Is(err, auth.ErrTokenMalformed):
+		return ReasonMalformedToken
+	case errors.Is(err, auth.ErrTokenSignatureInvalid):
+		return ReasonInvalidSignature
+	case errors.Is(err, auth.ErrTokenExpired):
+		return ReasonExpiredToken
+	case errors.Is(err, auth.ErrTokenNotYetValid), errors.Is(err, auth.ErrTokenIssuedInFuture):
+		return ReasonTokenNotYetValid
+	case errors.Is(err, auth.ErrTokenInvalidIssuer):
+		return ReasonInvalidIssuer
+	case errors.Is(err, auth.ErrTokenMFAChallenge):
+		return ReasonMFAPending
+	case errors.Is(err, auth.ErrTokenInvalidAudience):
+		return ReasonInvalidAudience
+	default:
+		return ReasonInvalidToken
+	}
+}
+
 func validateInputToken(s string) bool {
 	// Check if the input string is empty
 	if s == "" {
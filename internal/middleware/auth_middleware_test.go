@@ -0,0 +1,161 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"task-manager/internal/auth"
+	"task-manager/internal/mocks"
+	"task-manager/internal/model"
+	"task-manager/internal/service"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	// There is no default signing key, so tests sign with a fixed secret
+	auth.SetKeySet(auth.NewHMACKeySet(auth.DefaultKeyID, []byte("secret")))
+}
+
+func newAuthRouter(t *testing.T, tokenService *mocks.ITokenService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/protected", AuthMiddleware(tokenService), func(c *gin.Context) {
+		principal, ok := auth.PrincipalFromContext(c.Request.Context())
+		require.True(t, ok)
+		require.Equal(t, principal, c.MustGet(auth.PrincipalKey))
+		c.JSON(http.StatusOK, gin.H{"username": principal.Username})
+	})
+	return router
+}
+
+func jtiOf(t *testing.T, token string) string {
+	claims, err := auth.ParseToken(token)
+	require.Nil(t, err)
+	return claims.Id
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	tokenService := new(mocks.ITokenService)
+	router := newAuthRouter(t, tokenService)
+
+	user := &model.User{ID: uuid.Must(uuid.NewV7()), Username: "user1"}
+	validToken, err := auth.GenerateToken(user)
+	require.Nil(t, err)
+	revokedToken, err := auth.GenerateToken(user)
+	require.Nil(t, err)
+
+	tokenService.On("IsAccessTokenRevoked", mock.Anything, jtiOf(t, validToken)).Return(false, nil)
+	tokenService.On("IsAccessTokenRevoked", mock.Anything, jtiOf(t, revokedToken)).Return(true, nil)
+
+	// Issue a token an hour and a half in the past so it is already expired
+	jwt.TimeFunc = func() time.Time { return time.Now().Add(-90 * time.Minute) }
+	expiredToken, err := auth.GenerateToken(user)
+	jwt.TimeFunc = time.Now
+	require.Nil(t, err)
+
+	patPrincipal := &auth.Principal{Subject: user.ID.String(), Username: "bot", TokenType: auth.TokenTypePersonal}
+	tokenService.On("AuthenticatePersonalAccessToken", mock.Anything, "tmpat_valid").Return(patPrincipal, nil)
+	tokenService.On("AuthenticatePersonalAccessToken", mock.Anything, "tmpat_unknown").Return(nil, service.ErrPersonalAccessTokenInvalid)
+	tokenService.On("AuthenticatePersonalAccessToken", mock.Anything, "tmpat_revoked").Return(nil, service.ErrPersonalAccessTokenRevoked)
+
+	mfaToken, _, err := auth.GenerateMFAChallengeToken(user.ID)
+	require.Nil(t, err)
+
+	forgedToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"username": "user1"}).
+		SignedString([]byte("not-the-secret"))
+	require.Nil(t, err)
+
+	tests := []struct {
+		name         string
+		header       string
+		expectedCode int
+		expectedResp string
+	}{
+		{
+			name:         "missing header",
+			header:       "",
+			expectedCode: http.StatusUnauthorized,
+			expectedResp: `{"code":"missing_token","message":"Authorization header missing"}`,
+		},
+		{
+			name:         "bad header format",
+			header:       "Token abc",
+			expectedCode: http.StatusUnauthorized,
+			expectedResp: `{"code":"malformed_token","message":"invalid authorization header format"}`,
+		},
+		{
+			name:         "malformed token",
+			header:       "Bearer asdf.qwer.zxcv",
+			expectedCode: http.StatusUnauthorized,
+			expectedResp: `{"code":"malformed_token","message":"token is malformed"}`,
+		},
+		{
+			name:         "bad signature",
+			header:       "Bearer " + forgedToken,
+			expectedCode: http.StatusUnauthorized,
+			expectedResp: `{"code":"invalid_signature","message":"token signature is invalid"}`,
+		},
+		{
+			name:         "expired token",
+			header:       "Bearer " + expiredToken,
+			expectedCode: http.StatusUnauthorized,
+			expectedResp: `{"code":"expired_token","message":"token is expired"}`,
+		},
+		{
+			name:         "revoked token",
+			header:       "Bearer " + revokedToken,
+			expectedCode: http.StatusUnauthorized,
+			expectedResp: `{"code":"revoked_token","message":"token has been revoked"}`,
+		},
+		{
+			name:         "MFA challenge token",
+			header:       "Bearer " + mfaToken,
+			expectedCode: http.StatusUnauthorized,
+			expectedResp: `{"code":"mfa_pending","message":"token is an MFA challenge, the second login step is missing"}`,
+		},
+		{
+			name:         "valid token",
+			header:       "Bearer " + validToken,
+			expectedCode: http.StatusOK,
+			expectedResp: `{"username":"user1"}`,
+		},
+		{
+			name:         "unknown personal access token",
+			header:       "Bearer tmpat_unknown",
+			expectedCode: http.StatusUnauthorized,
+			expectedResp: `{"code":"invalid_token","message":"personal access token is invalid"}`,
+		},
+		{
+			name:         "revoked personal access token",
+			header:       "Bearer tmpat_revoked",
+			expectedCode: http.StatusUnauthorized,
+			expectedResp: `{"code":"revoked_token","message":"personal access token has been revoked"}`,
+		},
+		{
+			name:         "valid personal access token",
+			header:       "Bearer tmpat_valid",
+			expectedCode: http.StatusOK,
+			expectedResp: `{"username":"bot"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/protected", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.expectedCode, w.Code)
+			require.Equal(t, tt.expectedResp, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"task-manager/internal/auth"
+	"task-manager/internal/model"
+	"task-manager/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Reasons returned in the "code" field of a 403 response
+const (
+	ReasonForbidden   = "forbidden"
+	ReasonMFARequired = "mfa_required"
+)
+
+// RequirePermission rejects the request with 403 unless the principal set
+// by AuthMiddleware holds the permission. It must run after AuthMiddleware,
+// and after RequireWorkspaceMember on the workspace routes, where the
+// permission is granted by the role in the workspace instead.
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFromContext(c.Request.Context())
+		if !ok {
+			abortUnauthorized(c, ReasonMissingToken, "Authorization header missing")
+			return
+		}
+
+		member, inWorkspace := service.TenantFromContext(c.Request.Context())
+		allowed := principal.Can(permission)
+		if inWorkspace {
+			allowed = principal.CanInWorkspace(member.Role, permission)
+		}
+
+		if !allowed {
+			if !inWorkspace && principal.RequiresMFA() {
+				c.AbortWithStatusJSON(http.StatusForbidden, &model.Response{
+					Code:    ReasonMFARequired,
+					Message: "enable two-factor authentication and log in again to use your role",
+				})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusForbidden, &model.Response{
+				Code:    ReasonForbidden,
+				Message: "missing permission: " + permission,
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
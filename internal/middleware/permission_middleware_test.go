@@ -0,0 +1,175 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"task-manager/internal/auth"
+	"task-manager/internal/model"
+	"task-manager/internal/service"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequirePermission(t *testing.T) {
+	tests := []struct {
+		name         string
+		principal    *auth.Principal
+		member       *model.WorkspaceMember
+		permission   string
+		expectedCode int
+		expectedResp string
+	}{
+		{
+			name:         "no principal",
+			permission:   auth.PermTasksRead,
+			expectedCode: http.StatusUnauthorized,
+			expectedResp: `{"code":"missing_token","message":"Authorization header missing"}`,
+		},
+		{
+			name:         "viewer can read",
+			principal:    &auth.Principal{Roles: []string{auth.RoleViewer}},
+			permission:   auth.PermTasksRead,
+			expectedCode: http.StatusOK,
+			expectedResp: `ok`,
+		},
+		{
+			name:         "viewer cannot write",
+			principal:    &auth.Principal{Roles: []string{auth.RoleViewer}},
+			permission:   auth.PermTasksWrite,
+			expectedCode: http.StatusForbidden,
+			expectedResp: `{"code":"forbidden","message":"missing permission: tasks:write"}`,
+		},
+		{
+			name:         "member cannot delete",
+			principal:    &auth.Principal{Roles: []string{auth.RoleMember}},
+			permission:   auth.PermTasksDelete,
+			expectedCode: http.StatusForbidden,
+			expectedResp: `{"code":"forbidden","message":"missing permission: tasks:delete"}`,
+		},
+		{
+			name:         "admin can delete",
+			principal:    &auth.Principal{Roles: []string{auth.RoleAdmin}, MFA: true},
+			permission:   auth.PermTasksDelete,
+			expectedCode: http.StatusOK,
+			expectedResp: `ok`,
+		},
+		{
+			name:         "admin without a second factor",
+			principal:    &auth.Principal{Roles: []string{auth.RoleAdmin}},
+			permission:   auth.PermTasksRead,
+			expectedCode: http.StatusForbidden,
+			expectedResp: `{"code":"mfa_required","message":"enable two-factor authentication and log in again to use your role"}`,
+		},
+		{
+			name:         "personal access token within scope",
+			principal:    &auth.Principal{Roles: []string{auth.RoleAdmin}, MFA: true, TokenType: auth.TokenTypePersonal, Scopes: []string{auth.PermTasksRead}},
+			permission:   auth.PermTasksRead,
+			expectedCode: http.StatusOK,
+			expectedResp: `ok`,
+		},
+		{
+			name:         "personal access token out of scope",
+			principal:    &auth.Principal{Roles: []string{auth.RoleAdmin}, MFA: true, TokenType: auth.TokenTypePersonal, Scopes: []string{auth.PermTasksRead}},
+			permission:   auth.PermTasksDelete,
+			expectedCode: http.StatusForbidden,
+			expectedResp: `{"code":"forbidden","message":"missing permission: tasks:delete"}`,
+		},
+		{
+			name:         "scope does not extend the role",
+			principal:    &auth.Principal{Roles: []string{auth.RoleViewer}, TokenType: auth.TokenTypePersonal, Scopes: []string{auth.PermTasksWrite}},
+			permission:   auth.PermTasksWrite,
+			expectedCode: http.StatusForbidden,
+			expectedResp: `{"code":"forbidden","message":"missing permission: tasks:write"}`,
+		},
+		{
+			name:         "workspace member can write as a global member",
+			principal:    &auth.Principal{Roles: []string{auth.RoleMember}},
+			member:       &model.WorkspaceMember{Role: model.WorkspaceRoleMember},
+			permission:   auth.PermTasksWrite,
+			expectedCode: http.StatusOK,
+			expectedResp: `ok`,
+		},
+		{
+			name:         "workspace owner can read as a global viewer",
+			principal:    &auth.Principal{Roles: []string{auth.RoleViewer}},
+			member:       &model.WorkspaceMember{Role: model.WorkspaceRoleOwner},
+			permission:   auth.PermTasksRead,
+			expectedCode: http.StatusOK,
+			expectedResp: `ok`,
+		},
+		{
+			name:         "workspace owner cannot write as a global viewer",
+			principal:    &auth.Principal{Roles: []string{auth.RoleViewer}},
+			member:       &model.WorkspaceMember{Role: model.WorkspaceRoleOwner},
+			permission:   auth.PermTasksWrite,
+			expectedCode: http.StatusForbidden,
+			expectedResp: `{"code":"forbidden","message":"missing permission: tasks:write"}`,
+		},
+		{
+			name:         "workspace owner cannot delete as a global viewer",
+			principal:    &auth.Principal{Roles: []string{auth.RoleViewer}},
+			member:       &model.WorkspaceMember{Role: model.WorkspaceRoleOwner},
+			permission:   auth.PermTasksDelete,
+			expectedCode: http.StatusForbidden,
+			expectedResp: `{"code":"forbidden","message":"missing permission: tasks:delete"}`,
+		},
+		{
+			name:         "workspace member cannot delete as a global admin",
+			principal:    &auth.Principal{Roles: []string{auth.RoleAdmin}, MFA: true},
+			member:       &model.WorkspaceMember{Role: model.WorkspaceRoleMember},
+			permission:   auth.PermTasksDelete,
+			expectedCode: http.StatusForbidden,
+			expectedResp: `{"code":"forbidden","message":"missing permission: tasks:delete"}`,
+		},
+		{
+			name:         "workspace admin can delete",
+			principal:    &auth.Principal{Roles: []string{auth.RoleMember}},
+			member:       &model.WorkspaceMember{Role: model.WorkspaceRoleAdmin},
+			permission:   auth.PermTasksDelete,
+			expectedCode: http.StatusOK,
+			expectedResp: `ok`,
+		},
+		{
+			name:         "workspace owner cannot manage users",
+			principal:    &auth.Principal{Roles: []string{auth.RoleMember}},
+			member:       &model.WorkspaceMember{Role: model.WorkspaceRoleOwner},
+			permission:   auth.PermUsersManage,
+			expectedCode: http.StatusForbidden,
+			expectedResp: `{"code":"forbidden","message":"missing permission: users:manage"}`,
+		},
+		{
+			name:         "personal access token out of scope in a workspace",
+			principal:    &auth.Principal{Roles: []string{auth.RoleMember}, TokenType: auth.TokenTypePersonal, Scopes: []string{auth.PermTasksRead}},
+			member:       &model.WorkspaceMember{Role: model.WorkspaceRoleOwner},
+			permission:   auth.PermTasksWrite,
+			expectedCode: http.StatusForbidden,
+			expectedResp: `{"code":"forbidden","message":"missing permission: tasks:write"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.GET("/protected", func(c *gin.Context) {
+				if tt.principal != nil {
+					c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), tt.principal))
+				}
+				if tt.member != nil {
+					c.Request = c.Request.WithContext(service.WithTenant(c.Request.Context(), tt.member))
+				}
+			}, RequirePermission(tt.permission), func(c *gin.Context) {
+				c.String(http.StatusOK, "ok")
+			})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/protected", nil)
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.expectedCode, w.Code)
+			require.Equal(t, tt.expectedResp, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"task-manager/internal/auth"
+	"task-manager/internal/model"
+	"task-manager/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+)
+
+// ErrWorkspaceNotFound is returned for unknown workspaces, and for the
+// workspaces the caller is not a member of
+const ErrWorkspaceNotFound = "workspace not found"
+
+// RequireWorkspaceMember resolves the :wsId route parameter and checks that
+// the principal is a member of the workspace. The request context is then
+// scoped to the workspace, see service.WithTenant. It must run after
+// AuthMiddleware.
+func RequireWorkspaceMember(workspaceService service.IWorkspaceService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		principal, ok := auth.PrincipalFromContext(ctx)
+		if !ok {
+			abortUnauthorized(c, ReasonMissingToken, "Authorization header missing")
+			return
+		}
+
+		workspaceID, err := uuid.FromString(c.Param("wsId"))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, &model.Response{Message: http.StatusText(http.StatusBadRequest)})
+			return
+		}
+
+		member, err := workspaceService.GetMembership(ctx, workspaceID, principal.UserID())
+		if err != nil {
+			if gorm.IsRecordNotFoundError(err) {
+				c.AbortWithStatusJSON(http.StatusNotFound, &model.Response{Message: ErrWorkspaceNotFound})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, &model.Response{Message: http.StatusText(http.StatusInternalServerError)})
+			return
+		}
+
+		c.Request = c.Request.WithContext(service.WithTenant(ctx, member))
+		c.Next()
+	}
+}
@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"task-manager/internal/auth"
+	"task-manager/internal/mocks"
+	"task-manager/internal/model"
+	"task-manager/internal/service"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequireWorkspaceMember(t *testing.T) {
+	workspaceID, _ := uuid.NewV7()
+	userID, _ := uuid.NewV7()
+	principal := &auth.Principal{Subject: userID.String(), Roles: []string{auth.RoleMember}}
+
+	tests := []struct {
+		name         string
+		principal    *auth.Principal
+		wsId         string
+		setupMock    func(*mocks.IWorkspaceService)
+		expectedCode int
+		expectedResp string
+	}{
+		{
+			name:         "no principal",
+			wsId:         workspaceID.String(),
+			setupMock:    func(*mocks.IWorkspaceService) {},
+			expectedCode: http.StatusUnauthorized,
+			expectedResp: `{"code":"missing_token","message":"Authorization header missing"}`,
+		},
+		{
+			name:         "invalid workspace ID",
+			principal:    principal,
+			wsId:         "abc",
+			setupMock:    func(*mocks.IWorkspaceService) {},
+			expectedCode: http.StatusBadRequest,
+			expectedResp: `{"message":"Bad Request"}`,
+		},
+		{
+			name:      "not a member",
+			principal: principal,
+			wsId:      workspaceID.String(),
+			setupMock: func(m *mocks.IWorkspaceService) {
+				m.On("GetMembership", mock.Anything, workspaceID, userID).Return(nil, gorm.ErrRecordNotFound).Once()
+			},
+			expectedCode: http.StatusNotFound,
+			expectedResp: `{"message":"workspace not found"}`,
+		},
+		{
+			name:      "database error",
+			principal: principal,
+			wsId:      workspaceID.String(),
+			setupMock: func(m *mocks.IWorkspaceService) {
+				m.On("GetMembership", mock.Anything, workspaceID, userID).Return(nil, errors.New("internal error")).Once()
+			},
+			expectedCode: http.StatusInternalServerError,
+			expectedResp: `{"message":"Internal Server Error"}`,
+		},
+		{
+			name:      "member",
+			principal: principal,
+			wsId:      workspaceID.String(),
+			setupMock: func(m *mocks.IWorkspaceService) {
+				member := &model.WorkspaceMember{WorkspaceID: workspaceID, UserID: userID, Role: model.WorkspaceRoleMember}
+				m.On("GetMembership", mock.Anything, workspaceID, userID).Return(member, nil).Once()
+			},
+			expectedCode: http.StatusOK,
+			expectedResp: workspaceID.String(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workspaceService := new(mocks.IWorkspaceService)
+			tt.setupMock(workspaceService)
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.GET("/workspaces/:wsId/tasks", func(c *gin.Context) {
+				if tt.principal != nil {
+					c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), tt.principal))
+				}
+			}, RequireWorkspaceMember(workspaceService), func(c *gin.Context) {
+				tenant, ok := service.TenantFromContext(c.Request.Context())
+				require.True(t, ok)
+				c.String(http.StatusOK, tenant.WorkspaceID.String())
+			})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/workspaces/"+tt.wsId+"/tasks", nil)
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.expectedCode, w.Code)
+			require.Equal(t, tt.expectedResp, w.Body.String())
+			workspaceService.AssertExpectations(t)
+		})
+	}
+}
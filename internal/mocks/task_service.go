@@ -5,6 +5,8 @@ package mocks
 import (
 	context "context"
 	model "task-manager/internal/model"
+	workflow "task-manager/internal/workflow"
+	time "time"
 
 	mock "github.com/stretchr/testify/mock"
 
@@ -16,6 +18,60 @@ type ITaskService struct {
 	mock.Mock
 }
 
+// AddDependency provides a mock function with given fields: _a0, _a1, _a2
+func (_m *ITaskService) AddDependency(_a0 context.Context, _a1 uuid.UUID, _a2 uuid.UUID) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddDependency")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AssignTask provides a mock function with given fields: _a0, _a1, _a2
+func (_m *ITaskService) AssignTask(_a0 context.Context, _a1 uuid.UUID, _a2 []uuid.UUID) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AssignTask")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, []uuid.UUID) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AttachLabels provides a mock function with given fields: _a0, _a1, _a2
+func (_m *ITaskService) AttachLabels(_a0 context.Context, _a1 uuid.UUID, _a2 []uuid.UUID) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AttachLabels")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, []uuid.UUID) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateTask provides a mock function with given fields: _a0, _a1
 func (_m *ITaskService) CreateTask(_a0 context.Context, _a1 *model.Task) error {
 	ret := _m.Called(_a0, _a1)
@@ -34,17 +90,17 @@ func (_m *ITaskService) CreateTask(_a0 context.Context, _a1 *model.Task) error {
 	return r0
 }
 
-// DeleteTask provides a mock function with given fields: _a0, _a1
-func (_m *ITaskService) DeleteTask(_a0 context.Context, _a1 uuid.UUID) error {
-	ret := _m.Called(_a0, _a1)
+// DeleteTask provides a mock function with given fields: _a0, _a1, _a2
+func (_m *ITaskService) DeleteTask(_a0 context.Context, _a1 uuid.UUID, _a2 model.DeleteTaskOptions) error {
+	ret := _m.Called(_a0, _a1, _a2)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteTask")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
-		r0 = rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, model.DeleteTaskOptions) error); ok {
+		r0 = rf(_a0, _a1, _a2)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -52,14 +108,272 @@ func (_m *ITaskService) DeleteTask(_a0 context.Context, _a1 uuid.UUID) error {
 	return r0
 }
 
-// GetAllTasks provides a mock function with given fields: _a0
-func (_m *ITaskService) GetAllTasks(_a0 context.Context) ([]model.Task, error) {
-	ret := _m.Called(_a0)
+// DetachLabel provides a mock function with given fields: _a0, _a1, _a2
+func (_m *ITaskService) DetachLabel(_a0 context.Context, _a1 uuid.UUID, _a2 uuid.UUID) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DetachLabel")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAllTasks provides a mock function with given fields: _a0, _a1
+func (_m *ITaskService) GetAllTasks(_a0 context.Context, _a1 model.TaskFilter) ([]model.Task, error) {
+	ret := _m.Called(_a0, _a1)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetAllTasks")
 	}
 
+	var r0 []model.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.TaskFilter) ([]model.Task, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.TaskFilter) []model.Task); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.TaskFilter) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSubtasks provides a mock function with given fields: _a0, _a1
+func (_m *ITaskService) GetSubtasks(_a0 context.Context, _a1 uuid.UUID) ([]model.Task, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSubtasks")
+	}
+
+	var r0 []model.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]model.Task, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []model.Task); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTaskAssignees provides a mock function with given fields: _a0, _a1
+func (_m *ITaskService) GetTaskAssignees(_a0 context.Context, _a1 uuid.UUID) ([]model.TaskAssignee, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTaskAssignees")
+	}
+
+	var r0 []model.TaskAssignee
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]model.TaskAssignee, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []model.TaskAssignee); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.TaskAssignee)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTaskAssignmentLog provides a mock function with given fields: _a0, _a1
+func (_m *ITaskService) GetTaskAssignmentLog(_a0 context.Context, _a1 uuid.UUID) ([]model.TaskAssignmentLog, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTaskAssignmentLog")
+	}
+
+	var r0 []model.TaskAssignmentLog
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]model.TaskAssignmentLog, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []model.TaskAssignmentLog); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.TaskAssignmentLog)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTaskByID provides a mock function with given fields: _a0, _a1
+func (_m *ITaskService) GetTaskByID(_a0 context.Context, _a1 uuid.UUID) (*model.Task, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTaskByID")
+	}
+
+	var r0 *model.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*model.Task, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *model.Task); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTaskGrants provides a mock function with given fields: _a0, _a1
+func (_m *ITaskService) GetTaskGrants(_a0 context.Context, _a1 uuid.UUID) ([]model.TaskGrant, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTaskGrants")
+	}
+
+	var r0 []model.TaskGrant
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]model.TaskGrant, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []model.TaskGrant); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.TaskGrant)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTaskHistory provides a mock function with given fields: _a0, _a1
+func (_m *ITaskService) GetTaskHistory(_a0 context.Context, _a1 uuid.UUID) ([]model.TaskRevision, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTaskHistory")
+	}
+
+	var r0 []model.TaskRevision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]model.TaskRevision, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []model.TaskRevision); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.TaskRevision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTaskRevision provides a mock function with given fields: _a0, _a1, _a2
+func (_m *ITaskService) GetTaskRevision(_a0 context.Context, _a1 uuid.UUID, _a2 int) (*model.TaskRevision, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTaskRevision")
+	}
+
+	var r0 *model.TaskRevision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int) (*model.TaskRevision, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int) *model.TaskRevision); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.TaskRevision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, int) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTrash provides a mock function with given fields: _a0
+func (_m *ITaskService) GetTrash(_a0 context.Context) ([]model.Task, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTrash")
+	}
+
 	var r0 []model.Task
 	var r1 error
 	if rf, ok := ret.Get(0).(func(context.Context) ([]model.Task, error)); ok {
@@ -82,12 +396,166 @@ func (_m *ITaskService) GetAllTasks(_a0 context.Context) ([]model.Task, error) {
 	return r0, r1
 }
 
-// GetTaskByID provides a mock function with given fields: _a0, _a1
-func (_m *ITaskService) GetTaskByID(_a0 context.Context, _a1 uuid.UUID) (*model.Task, error) {
+// GetWorkflow provides a mock function with given fields: _a0, _a1
+func (_m *ITaskService) GetWorkflow(_a0 context.Context, _a1 *uuid.UUID) (*workflow.StateMachine, error) {
 	ret := _m.Called(_a0, _a1)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetTaskByID")
+		panic("no return value specified for GetWorkflow")
+	}
+
+	var r0 *workflow.StateMachine
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *uuid.UUID) (*workflow.StateMachine, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *uuid.UUID) *workflow.StateMachine); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*workflow.StateMachine)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *uuid.UUID) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GrantTask provides a mock function with given fields: _a0, _a1, _a2
+func (_m *ITaskService) GrantTask(_a0 context.Context, _a1 uuid.UUID, _a2 uuid.UUID) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GrantTask")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MarkOverdueTasks provides a mock function with given fields: _a0
+func (_m *ITaskService) MarkOverdueTasks(_a0 time.Time) ([]model.Task, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkOverdueTasks")
+	}
+
+	var r0 []model.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time) ([]model.Task, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time) []model.Task); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PatchTask provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *ITaskService) PatchTask(_a0 context.Context, _a1 uuid.UUID, _a2 model.TaskPatch, _a3 model.UpdateTaskOptions) (*model.Task, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PatchTask")
+	}
+
+	var r0 *model.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, model.TaskPatch, model.UpdateTaskOptions) (*model.Task, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, model.TaskPatch, model.UpdateTaskOptions) *model.Task); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, model.TaskPatch, model.UpdateTaskOptions) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PurgeDeletedTasks provides a mock function with given fields: _a0
+func (_m *ITaskService) PurgeDeletedTasks(_a0 time.Time) (int64, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeDeletedTasks")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time) (int64, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time) int64); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveDependency provides a mock function with given fields: _a0, _a1, _a2
+func (_m *ITaskService) RemoveDependency(_a0 context.Context, _a1 uuid.UUID, _a2 uuid.UUID) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveDependency")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RestoreTask provides a mock function with given fields: _a0, _a1
+func (_m *ITaskService) RestoreTask(_a0 context.Context, _a1 uuid.UUID) (*model.Task, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreTask")
 	}
 
 	var r0 *model.Task
@@ -112,16 +580,46 @@ func (_m *ITaskService) GetTaskByID(_a0 context.Context, _a1 uuid.UUID) (*model.
 	return r0, r1
 }
 
-// UpdateTask provides a mock function with given fields: _a0, _a1, _a2
-func (_m *ITaskService) UpdateTask(_a0 context.Context, _a1 uuid.UUID, _a2 *model.Task) error {
+// RevertTask provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *ITaskService) RevertTask(_a0 context.Context, _a1 uuid.UUID, _a2 int, _a3 model.UpdateTaskOptions) (*model.Task, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevertTask")
+	}
+
+	var r0 *model.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int, model.UpdateTaskOptions) (*model.Task, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int, model.UpdateTaskOptions) *model.Task); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, int, model.UpdateTaskOptions) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeTaskGrant provides a mock function with given fields: _a0, _a1, _a2
+func (_m *ITaskService) RevokeTaskGrant(_a0 context.Context, _a1 uuid.UUID, _a2 uuid.UUID) error {
 	ret := _m.Called(_a0, _a1, _a2)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateTask")
+		panic("no return value specified for RevokeTaskGrant")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *model.Task) error); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
 		r0 = rf(_a0, _a1, _a2)
 	} else {
 		r0 = ret.Error(0)
@@ -130,6 +628,84 @@ func (_m *ITaskService) UpdateTask(_a0 context.Context, _a1 uuid.UUID, _a2 *mode
 	return r0
 }
 
+// TransitionTask provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *ITaskService) TransitionTask(_a0 context.Context, _a1 uuid.UUID, _a2 string, _a3 model.UpdateTaskOptions) (*model.Task, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TransitionTask")
+	}
+
+	var r0 *model.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, model.UpdateTaskOptions) (*model.Task, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, model.UpdateTaskOptions) *model.Task); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, string, model.UpdateTaskOptions) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UnassignTask provides a mock function with given fields: _a0, _a1, _a2
+func (_m *ITaskService) UnassignTask(_a0 context.Context, _a1 uuid.UUID, _a2 []uuid.UUID) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnassignTask")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, []uuid.UUID) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateTask provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *ITaskService) UpdateTask(_a0 context.Context, _a1 uuid.UUID, _a2 *model.Task, _a3 model.UpdateTaskOptions) (*model.Task, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTask")
+	}
+
+	var r0 *model.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *model.Task, model.UpdateTaskOptions) (*model.Task, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *model.Task, model.UpdateTaskOptions) *model.Task); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, *model.Task, model.UpdateTaskOptions) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewITaskService creates a new instance of ITaskService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewITaskService(t interface {
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Comment is a message of the discussion of a task. Replies answer a top
+// level comment, threads are one level deep.
+type Comment struct {
+	ID       uuid.UUID  `json:"id" gorm:"primaryKey"`
+	TaskID   uuid.UUID  `json:"task_id" gorm:"type:uuid;not null;index"`
+	ParentID *uuid.UUID `json:"parent_id" gorm:"type:uuid;index"`
+	AuthorID uuid.UUID  `json:"author_id" gorm:"type:uuid;not null"`
+	Body     string     `json:"body" gorm:"type:text;not null"`
+
+	// EditedAt is the time of the latest edit, the previous bodies are
+	// kept as revisions
+	EditedAt *time.Time `json:"edited_at"`
+
+	// Replies are only loaded on the top level comments of a listing
+	Replies []Comment `json:"replies,omitempty" gorm:"-"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CommentRevision is a previous body of an edited comment
+type CommentRevision struct {
+	ID        uuid.UUID `json:"id" gorm:"primaryKey"`
+	CommentID uuid.UUID `json:"comment_id" gorm:"type:uuid;not null;index"`
+	Body      string    `json:"body" gorm:"type:text;not null"`
+
+	// EditorID edited the comment away from Body at CreatedAt
+	EditorID  uuid.UUID `json:"editor_id" gorm:"type:uuid"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CommentMention is a user mentioned by a comment, who was notified of it
+type CommentMention struct {
+	CommentID uuid.UUID `json:"comment_id" gorm:"type:uuid;primary_key"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;primary_key"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CommentRequest struct {
+	Body     string     `json:"body" binding:"required,max=10000"`
+	ParentID *uuid.UUID `json:"parent_id"`
+}
+
+type UpdateCommentRequest struct {
+	Body string `json:"body" binding:"required,max=10000"`
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Label classifies tasks. Labels belong to a workspace, or to their owner
+// for personal tasks, like the tasks they are attached to.
+type Label struct {
+	ID          uuid.UUID  `json:"id" gorm:"primaryKey"`
+	WorkspaceID *uuid.UUID `json:"workspace_id" gorm:"type:uuid;index"`
+	OwnerID     uuid.UUID  `json:"owner_id" gorm:"type:uuid;index"`
+	Name        string     `json:"name" gorm:"type:varchar(50);not null"`
+	Color       string     `json:"color" gorm:"type:varchar(7);not null"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// TaskLabel attaches a label to a task
+type TaskLabel struct {
+	TaskID    uuid.UUID `json:"task_id" gorm:"type:uuid;primary_key"`
+	LabelID   uuid.UUID `json:"label_id" gorm:"type:uuid;primary_key"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type LabelRequest struct {
+	Name  string `json:"name" binding:"required,max=50"`
+	Color string `json:"color" binding:"required,hexcolor"`
+}
+
+type AttachLabelsRequest struct {
+	LabelIDs []uuid.UUID `json:"label_ids" binding:"required,min=1,max=20"`
+}
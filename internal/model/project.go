@@ -0,0 +1,84 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Categories group the statuses of a workflow. Tasks in a done status are
+// complete, the others count as open.
+const (
+	CategoryTodo   = "todo"
+	CategoryActive = "active"
+	CategoryDone   = "done"
+)
+
+// Project groups tasks following the same status workflow. Projects belong
+// to a workspace, or to their owner for personal tasks.
+type Project struct {
+	ID          uuid.UUID  `json:"id" gorm:"primaryKey"`
+	WorkspaceID *uuid.UUID `json:"workspace_id" gorm:"type:uuid;index"`
+	OwnerID     uuid.UUID  `json:"owner_id" gorm:"type:uuid;index"`
+	Name        string     `json:"name" gorm:"type:varchar(100);not null"`
+	Workflow    Workflow   `json:"workflow" gorm:"type:text;not null"`
+
+	// TimeSpent is the time logged on the tasks of the project, in
+	// seconds
+	TimeSpent int64 `json:"time_spent" gorm:"-"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Workflow is the set of statuses of the tasks of a project and the
+// transitions allowed between them, stored as JSON. Without transitions,
+// tasks move freely between the statuses.
+type Workflow struct {
+	Statuses    []WorkflowStatus     `json:"statuses" binding:"required,min=1,max=20,dive"`
+	Transitions []WorkflowTransition `json:"transitions" binding:"omitempty,max=200,dive"`
+
+	// ReopenTo is the status done tasks are reopened to, defaults to the
+	// first todo status
+	ReopenTo string `json:"reopen_to,omitempty" binding:"omitempty,max=50"`
+}
+
+type WorkflowStatus struct {
+	Key      string `json:"key" binding:"required,max=50"`
+	Name     string `json:"name" binding:"required,max=100"`
+	Category string `json:"category" binding:"required,oneof=todo active done"`
+}
+
+type WorkflowTransition struct {
+	From string `json:"from" binding:"required,max=50"`
+	To   string `json:"to" binding:"required,max=50"`
+}
+
+func (w Workflow) Value() (driver.Value, error) {
+	b, err := json.Marshal(w)
+	return string(b), err
+}
+
+func (w *Workflow) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case string:
+		return json.Unmarshal([]byte(src), w)
+	case []byte:
+		return json.Unmarshal(src, w)
+	case nil:
+		*w = Workflow{}
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into Workflow", src)
+	}
+}
+
+type CreateProjectRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+
+	// Workflow defaults to the workflow of the deployment
+	Workflow *Workflow `json:"workflow"`
+}
@@ -0,0 +1,79 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Recurrence is the template of a recurring task. The scheduler creates a
+// task from the template for each occurrence of its RRULE, when the time
+// of the occurrence arrives or as soon as the previous task is done.
+type Recurrence struct {
+	ID          uuid.UUID  `json:"id" gorm:"primaryKey"`
+	WorkspaceID *uuid.UUID `json:"workspace_id" gorm:"type:uuid;index"`
+	OwnerID     uuid.UUID  `json:"owner_id" gorm:"type:uuid;index"`
+	ProjectID   *uuid.UUID `json:"project_id" gorm:"type:uuid;index"`
+	CreatedBy   uuid.UUID  `json:"created_by" gorm:"type:uuid"`
+
+	// Fields of the tasks created from the template
+	Title       string `json:"title" gorm:"not null"`
+	Description string `json:"description" gorm:"not null"`
+	Priority    string `json:"priority" gorm:"type:varchar(10);not null;default:'medium'"`
+
+	// RRule is expanded from StartAt in Timezone, skipping the occurrences
+	// on the exception dates
+	RRule       string    `json:"rrule" gorm:"column:rrule;type:varchar(500);not null"`
+	Timezone    string    `json:"timezone" gorm:"type:varchar(64);not null;default:'UTC'"`
+	StartAt     time.Time `json:"start_at" gorm:"not null"`
+	ExceptDates DateList  `json:"except_dates" gorm:"type:text"`
+
+	// NextAt is the next occurrence without a task, nil once the rule has
+	// no more occurrences
+	NextAt *time.Time `json:"next_at" gorm:"index"`
+
+	// LastAt and LastTaskID are the latest occurrence with a task, and
+	// its task
+	LastAt      *time.Time `json:"last_at"`
+	LastTaskID  *uuid.UUID `json:"last_task_id" gorm:"type:uuid"`
+	Occurrences int        `json:"occurrences" gorm:"not null;default:0"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type RecurrenceRequest struct {
+	Title       string     `json:"title" binding:"required"`
+	Description string     `json:"description" binding:"required"`
+	Priority    string     `json:"priority" binding:"omitempty,oneof=low medium high urgent"`
+	ProjectID   *uuid.UUID `json:"project_id"`
+	RRule       string     `json:"rrule" binding:"required,max=500"`
+	Timezone    string     `json:"timezone" binding:"omitempty,timezone"`
+	StartAt     time.Time  `json:"start_at" binding:"required"`
+	ExceptDates []string   `json:"except_dates" binding:"omitempty,max=366,dive,datetime=2006-01-02"`
+}
+
+type SkipOccurrenceRequest struct {
+	Date string `json:"date" binding:"required,datetime=2006-01-02"`
+}
+
+// DateList is a list of dates formatted as 2006-01-02, stored as JSON
+type DateList []string
+
+func (d DateList) Value() (driver.Value, error) {
+	if d == nil {
+		d = DateList{}
+	}
+	b, err := json.Marshal(d)
+	return string(b), err
+}
+
+func (d *DateList) Scan(src interface{}) error {
+	if src == nil {
+		*d = nil
+		return nil
+	}
+	return scanJSON(src, d)
+}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Reminder notifies its user about a task, at a given time or some
+// minutes before the due date of the task
+type Reminder struct {
+	ID      uuid.UUID `json:"id" gorm:"primaryKey"`
+	TaskID  uuid.UUID `json:"task_id" gorm:"type:uuid;not null;index"`
+	UserID  uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
+	Channel string    `json:"channel" gorm:"type:varchar(10);not null"`
+
+	// Either RemindAt or BeforeMinutes is set. Reminders relative to the
+	// due date follow its changes until they are sent.
+	RemindAt      *time.Time `json:"remind_at"`
+	BeforeMinutes *int       `json:"before_minutes"`
+
+	SentAt *time.Time `json:"sent_at"`
+
+	// Failed deliveries are retried at RetryAt, the reminder gives up at
+	// FailedAt. RetryAt also keeps the reminder from being sent twice
+	// while it is being delivered.
+	Attempts  int        `json:"attempts" gorm:"not null;default:0"`
+	RetryAt   *time.Time `json:"-" gorm:"index"`
+	LastError string     `json:"last_error,omitempty"`
+	FailedAt  *time.Time `json:"failed_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ReminderRequest struct {
+	Channel       string     `json:"channel" binding:"omitempty,oneof=inbox email webhook"`
+	RemindAt      *time.Time `json:"remind_at" binding:"required_without=BeforeMinutes,excluded_with=BeforeMinutes"`
+	BeforeMinutes *int       `json:"before_minutes" binding:"required_without=RemindAt,omitempty,gte=0,lte=525600"`
+}
+
+// Notification is a message of the in-app inbox of a user, from a reminder
+// or a comment mentioning the user
+type Notification struct {
+	ID         uuid.UUID  `json:"id" gorm:"primaryKey"`
+	UserID     uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	TaskID     uuid.UUID  `json:"task_id" gorm:"type:uuid"`
+	ReminderID *uuid.UUID `json:"reminder_id" gorm:"type:uuid"`
+	CommentID  *uuid.UUID `json:"comment_id" gorm:"type:uuid"`
+	Subject    string     `json:"subject" gorm:"not null"`
+	Body       string     `json:"body" gorm:"not null"`
+	ReadAt     *time.Time `json:"read_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type NotificationFilter struct {
+	Unread bool `form:"unread"`
+}
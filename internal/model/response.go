@@ -2,6 +2,9 @@ package model
 
 type Response struct {
 
+	// code
+	Code string `json:"code,omitempty"`
+
 	// message
 	Message string `json:"message,omitempty"`
 
@@ -0,0 +1,66 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// TaskRevision records a change of a task. Revision is the version of the
+// task after the change, the first revision records its creation.
+type TaskRevision struct {
+	ID       uuid.UUID `json:"id" gorm:"primaryKey"`
+	TaskID   uuid.UUID `json:"task_id" gorm:"type:uuid;not null;unique_index:idx_task_revisions_task_revision"`
+	Revision int       `json:"revision" gorm:"not null;unique_index:idx_task_revisions_task_revision"`
+	ActorID  uuid.UUID `json:"actor_id" gorm:"type:uuid"`
+
+	// Changes holds the old and new values of the fields that changed
+	Changes TaskChanges `json:"changes" gorm:"type:text;not null"`
+
+	// Snapshot holds the fields of the task after the change, it is only
+	// loaded for a single revision
+	Snapshot *TaskPatch `json:"snapshot,omitempty" gorm:"type:text"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TaskChanges maps the JSON name of the changed fields to their values,
+// stored as JSON
+type TaskChanges map[string]FieldChange
+
+type FieldChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+func (c TaskChanges) Value() (driver.Value, error) {
+	b, err := json.Marshal(c)
+	return string(b), err
+}
+
+func (c *TaskChanges) Scan(src interface{}) error {
+	return scanJSON(src, c)
+}
+
+func (p TaskPatch) Value() (driver.Value, error) {
+	b, err := json.Marshal(p)
+	return string(b), err
+}
+
+func (p *TaskPatch) Scan(src interface{}) error {
+	return scanJSON(src, p)
+}
+
+func scanJSON(src, dest interface{}) error {
+	switch src := src.(type) {
+	case string:
+		return json.Unmarshal([]byte(src), dest)
+	case []byte:
+		return json.Unmarshal(src, dest)
+	default:
+		return fmt.Errorf("cannot scan %T into %T", src, dest)
+	}
+}
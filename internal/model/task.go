@@ -1,16 +1,228 @@
 package model
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/gofrs/uuid"
 )
 
+// Statuses of a task
+const (
+	TaskStatusPending    = "pending"
+	TaskStatusInProgress = "in-progress"
+	TaskStatusCompleted  = "completed"
+)
+
+// Actions moving a task from a status to another
+const (
+	TaskActionStart    = "start"
+	TaskActionComplete = "complete"
+	TaskActionReopen   = "reopen"
+)
+
+// What happens to the subtasks of a deleted task
+const (
+	SubtasksCascade = "cascade"
+	SubtasksOrphan  = "orphan"
+	SubtasksReject  = "reject"
+)
+
+// Priorities of a task, from the lowest to the highest
+const (
+	PriorityLow    = "low"
+	PriorityMedium = "medium"
+	PriorityHigh   = "high"
+	PriorityUrgent = "urgent"
+)
+
 type Task struct {
-	ID          uuid.UUID `json:"id" gorm:"primaryKey"`
-	Title       string    `json:"title" binding:"required"`
-	Description string    `json:"description" binding:"required"`
-	Status      string    `json:"status" binding:"required,oneof=pending in-progress completed"`
+	ID          uuid.UUID  `json:"id" gorm:"primaryKey"`
+	Title       string     `json:"title" binding:"required"`
+	Description string     `json:"description" binding:"required"`
+	Status      string     `json:"status" binding:"max=50"`
+	Priority    string     `json:"priority" gorm:"type:varchar(10);not null;default:'medium'" binding:"omitempty,oneof=low medium high urgent"`
+	DueAt       *time.Time `json:"due_at" gorm:"index"`
+	ParentID    *uuid.UUID `json:"parent_id" gorm:"type:uuid;index"`
+	ProjectID   *uuid.UUID `json:"project_id" gorm:"type:uuid;index"`
+
+	// RecurrenceID is the recurring task the task was created from
+	RecurrenceID *uuid.UUID `json:"recurrence_id" gorm:"type:uuid;index"`
+
+	// StatusCategory is the category of the status in the workflow of
+	// the task, it is kept along with the status
+	StatusCategory string `json:"status_category" gorm:"type:varchar(10);not null;default:'todo'"`
+
+	WorkspaceID *uuid.UUID `json:"workspace_id" gorm:"type:uuid;index"`
+	OwnerID     uuid.UUID  `json:"owner_id" gorm:"type:uuid;index"`
+	CreatedBy   uuid.UUID  `json:"created_by" gorm:"type:uuid"`
+
+	// OverdueAt is set by the overdue job when it first notices the task
+	// is past its due date, so the task is only reported once
+	OverdueAt *time.Time `json:"overdue_at" gorm:"index"`
+
+	// Labels are attached through their own endpoints, never saved along
+	// with the task
+	Labels []Label `json:"labels" gorm:"many2many:task_labels;save_associations:false"`
+
+	// Progress rolls up the subtasks, it is only set on parent tasks
+	Progress *TaskProgress `json:"progress,omitempty" gorm:"-"`
+
+	// BlockedBy and Blocks are the tasks this task depends on, and the
+	// tasks depending on it
+	BlockedBy []uuid.UUID `json:"blocked_by" gorm:"-"`
+	Blocks    []uuid.UUID `json:"blocks" gorm:"-"`
+
+	// TimeSpent is the time logged on the task, in seconds
+	TimeSpent int64 `json:"time_spent" gorm:"-"`
+
+	// Version is incremented by every change of the task, it is sent as
+	// the ETag of the task and checked against If-Match
+	Version int `json:"version" gorm:"not null;default:1"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// DeletedAt is set when the task is moved to the trash, gorm leaves
+	// such tasks out of its queries. It is never taken from a request,
+	// tasks only reach the trash through DeleteTask.
+	DeletedAt *time.Time `json:"deleted_at,omitempty" gorm:"index" binding:"-"`
+}
+
+// TaskProgress is the share of the subtasks of a task that are completed
+type TaskProgress struct {
+	Subtasks  int `json:"subtasks"`
+	Completed int `json:"completed"`
+	Percent   int `json:"percent"`
+}
+
+// IsOverdue tells whether the task is past its due date and not done
+func (t *Task) IsOverdue(now time.Time) bool {
+	return t.DueAt != nil && t.DueAt.Before(now) && t.StatusCategory != CategoryDone
+}
+
+// MarshalJSON adds the derived overdue flag to the task
+func (t Task) MarshalJSON() ([]byte, error) {
+	type task Task
+	return json.Marshal(struct {
+		task
+		Overdue bool `json:"overdue"`
+	}{task(t), t.IsOverdue(time.Now())})
+}
+
+// TaskFilter narrows and orders the tasks listed by GetAllTasks. Zero
+// values do not filter.
+type TaskFilter struct {
+	Status    string    `form:"status" binding:"omitempty,max=50"`
+	Category  string    `form:"category" binding:"omitempty,oneof=todo active done"`
+	Priority  string    `form:"priority" binding:"omitempty,oneof=low medium high urgent"`
+	DueBefore time.Time `form:"due_before" time_format:"2006-01-02T15:04:05Z07:00"`
+	DueAfter  time.Time `form:"due_after" time_format:"2006-01-02T15:04:05Z07:00"`
+	Overdue   *bool     `form:"overdue"`
+	ProjectID string    `form:"project_id" binding:"omitempty,uuid"`
+
+	// Labels only lists the tasks carrying any, or all with LabelMatch
+	// "all", of the label names
+	Labels     []string `form:"label"`
+	LabelMatch string   `form:"label_match" binding:"omitempty,oneof=any all"`
+
+	// AssigneeID only lists the tasks assigned to the user, it is set by
+	// the "assigned to me" listing
+	AssigneeID uuid.UUID `form:"-"`
+
+	Sort string `form:"sort" binding:"omitempty,oneof=created_at -created_at due_at -due_at priority -priority"`
+}
+
+// TaskPatch holds the fields of a task a PATCH request may change. The
+// patch is applied to the current fields and the result is validated like
+// a new task, null clears the due date and the parent.
+type TaskPatch struct {
+	Title       string     `json:"title" binding:"required"`
+	Description string     `json:"description" binding:"required"`
+	Status      string     `json:"status" binding:"required,max=50"`
+	Priority    string     `json:"priority" binding:"required,oneof=low medium high urgent"`
+	DueAt       *time.Time `json:"due_at"`
+	ParentID    *uuid.UUID `json:"parent_id"`
+}
+
+// NewTaskPatch returns the patchable fields of the task
+func NewTaskPatch(task *Task) TaskPatch {
+	return TaskPatch{
+		Title:       task.Title,
+		Description: task.Description,
+		Status:      task.Status,
+		Priority:    task.Priority,
+		DueAt:       task.DueAt,
+		ParentID:    task.ParentID,
+	}
+}
+
+type UpdateTaskOptions struct {
+	// Force starts or completes the task even though blockers are open
+	Force bool `form:"force"`
+
+	// Version is the version the change was based on, taken from the
+	// If-Match header. Zero matches any version.
+	Version int `form:"-"`
+}
+
+type DeleteTaskOptions struct {
+	Subtasks string `form:"subtasks" binding:"omitempty,oneof=cascade orphan reject"`
+
+	// Version is the version the deletion was based on, zero matches any
+	// version
+	Version int `form:"-"`
+}
+
+// TaskDependency tells that a task cannot start before its blocker is
+// completed
+type TaskDependency struct {
+	TaskID      uuid.UUID `json:"task_id" gorm:"type:uuid;primary_key"`
+	BlockedByID uuid.UUID `json:"blocked_by_id" gorm:"type:uuid;primary_key;index"`
+	CreatedBy   uuid.UUID `json:"created_by" gorm:"type:uuid"`
 	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type DependencyRequest struct {
+	BlockedByID uuid.UUID `json:"blocked_by_id" binding:"required"`
+}
+
+// TaskGrant gives a user other than the owner access to a task
+type TaskGrant struct {
+	TaskID    uuid.UUID `json:"task_id" gorm:"type:uuid;primary_key"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;primary_key"`
+	GrantedBy uuid.UUID `json:"granted_by" gorm:"type:uuid"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type GrantRequest struct {
+	UserID uuid.UUID `json:"user_id" binding:"required"`
+}
+
+// Actions of a task assignment log
+const (
+	AssignmentAssigned   = "assigned"
+	AssignmentUnassigned = "unassigned"
+)
+
+// TaskAssignee is a user responsible for a task
+type TaskAssignee struct {
+	TaskID     uuid.UUID `json:"task_id" gorm:"type:uuid;primary_key"`
+	UserID     uuid.UUID `json:"user_id" gorm:"type:uuid;primary_key"`
+	AssignedBy uuid.UUID `json:"assigned_by" gorm:"type:uuid"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TaskAssignmentLog records who assigned or unassigned a user, and when
+type TaskAssignmentLog struct {
+	ID        uuid.UUID `json:"id" gorm:"primaryKey"`
+	TaskID    uuid.UUID `json:"task_id" gorm:"type:uuid;index;not null"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;not null"`
+	Action    string    `json:"action" gorm:"type:varchar(20);not null"`
+	ActorID   uuid.UUID `json:"actor_id" gorm:"type:uuid"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type AssigneesRequest struct {
+	UserIDs []uuid.UUID `json:"user_ids" binding:"required,min=1,max=20"`
 }
@@ -0,0 +1,86 @@
+package model
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// RefreshToken is a server side record of an issued refresh token. Tokens
+// issued by rotation share the FamilyID of the token issued at login.
+type RefreshToken struct {
+	ID         uuid.UUID  `json:"id" gorm:"primaryKey"`
+	UserID     uuid.UUID  `json:"user_id" gorm:"type:uuid;index;not null"`
+	FamilyID   uuid.UUID  `json:"family_id" gorm:"type:uuid;index;not null"`
+	TokenHash  string     `json:"-" gorm:"type:varchar(64);unique_index;not null"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	ReplacedBy *uuid.UUID `json:"replaced_by" gorm:"type:uuid"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// RevokedToken is an entry of the access token revocation list
+type RevokedToken struct {
+	JTI       string    `json:"jti" gorm:"column:jti;primary_key"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"index"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PersonalAccessToken is a long-lived credential of a user, meant for
+// scripts and bots. Only the hash of the token is stored.
+type PersonalAccessToken struct {
+	ID         uuid.UUID  `json:"id" gorm:"primaryKey"`
+	UserID     uuid.UUID  `json:"user_id" gorm:"type:uuid;index;not null"`
+	Name       string     `json:"name" gorm:"type:varchar(100);not null"`
+	Scopes     Scopes     `json:"scopes" gorm:"type:text;not null"`
+	TokenHash  string     `json:"-" gorm:"type:varchar(64);unique_index;not null"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// Scopes is a list of permissions, stored as a space separated string
+type Scopes []string
+
+func (s Scopes) Value() (driver.Value, error) {
+	return strings.Join(s, " "), nil
+}
+
+func (s *Scopes) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case string:
+		*s = strings.Fields(src)
+	case []byte:
+		*s = strings.Fields(string(src))
+	case nil:
+		*s = nil
+	default:
+		return fmt.Errorf("cannot scan %T into Scopes", src)
+	}
+	return nil
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type CreatePersonalAccessTokenRequest struct {
+	Name          string   `json:"name" binding:"required,max=100"`
+	Scopes        []string `json:"scopes" binding:"required,min=1,dive,oneof=tasks:read tasks:write tasks:delete"`
+	ExpiresInDays int      `json:"expires_in_days" binding:"omitempty,min=1,max=365"`
+}
+
+// PersonalAccessTokenResponse carries the token itself, which is only
+// returned once, on creation
+type PersonalAccessTokenResponse struct {
+	PersonalAccessToken
+	Token string `json:"token"`
+}
@@ -0,0 +1,105 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+type User struct {
+	ID           uuid.UUID `json:"id" gorm:"primaryKey"`
+	Username     string    `json:"username" gorm:"type:varchar(50);unique_index;not null"`
+	Email        string    `json:"email" gorm:"type:varchar(255);unique_index;not null"`
+	PasswordHash string    `json:"-" gorm:"not null"`
+	Role         string    `json:"role" gorm:"type:varchar(20);not null;default:'member'"`
+
+	// EmailVerified is set when an identity provider vouched for the email
+	// of the user. Registration does not verify it.
+	EmailVerified bool `json:"-" gorm:"column:email_verified;not null;default:false"`
+
+	// The TOTP secret is set on enrollment, and only used for logins once
+	// the first code confirmed it
+	TOTPSecret      string `json:"-" gorm:"column:totp_secret;type:varchar(64)"`
+	TOTPEnabled     bool   `json:"totp_enabled" gorm:"column:totp_enabled;not null;default:false"`
+	TOTPLastCounter int64  `json:"-" gorm:"column:totp_last_counter;not null;default:0"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// UserIdentity links a user to the account of an external identity
+// provider. Users who only log in through a provider have no password.
+type UserIdentity struct {
+	Issuer    string    `json:"issuer" gorm:"type:varchar(255);primary_key"`
+	Subject   string    `json:"subject" gorm:"type:varchar(255);primary_key"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;index;not null"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ExternalProfile is the user described by an identity provider
+type ExternalProfile struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Username      string
+}
+
+// RecoveryCode is a one time code that replaces the TOTP code at login.
+// Only its hash is stored.
+type RecoveryCode struct {
+	ID        uuid.UUID  `json:"id" gorm:"primaryKey"`
+	UserID    uuid.UUID  `json:"user_id" gorm:"type:uuid;index;not null"`
+	CodeHash  string     `json:"-" gorm:"type:varchar(64);not null"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type RegisterRequest struct {
+	Username string `json:"username" binding:"required,alphanum,min=3,max=50"`
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required,min=8,max=72"`
+}
+
+type UpdateRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=admin member viewer"`
+}
+
+type LoginRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+// MFAChallengeResponse is returned by the password step of a login when
+// the user has two-factor authentication enabled
+type MFAChallengeResponse struct {
+	MFARequired bool   `json:"mfa_required"`
+	MFAToken    string `json:"mfa_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+type MFALoginRequest struct {
+	MFAToken     string `json:"mfa_token" binding:"required"`
+	Code         string `json:"code" binding:"required_without=RecoveryCode"`
+	RecoveryCode string `json:"recovery_code" binding:"required_without=Code"`
+}
+
+type TOTPCodeRequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+type TOTPEnrollmentResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
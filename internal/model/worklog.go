@@ -0,0 +1,78 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Sources of a work log
+const (
+	WorkLogTimer  = "timer"
+	WorkLogManual = "manual"
+)
+
+// WorkLog is time a user spent on a task, recorded by a timer or entered
+// by hand. The log of a running timer has no end yet.
+type WorkLog struct {
+	ID        uuid.UUID  `json:"id" gorm:"primaryKey"`
+	TaskID    uuid.UUID  `json:"task_id" gorm:"type:uuid;not null;index"`
+	UserID    uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	StartedAt time.Time  `json:"started_at" gorm:"not null;index"`
+	EndedAt   *time.Time `json:"ended_at"`
+
+	// Seconds is the duration of the log, it is set once the log ends
+	Seconds int64  `json:"seconds" gorm:"not null;default:0"`
+	Note    string `json:"note" gorm:"type:varchar(500)"`
+	Source  string `json:"source" gorm:"type:varchar(10);not null"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type TimerRequest struct {
+	Note string `json:"note" binding:"max=500"`
+}
+
+type WorkLogRequest struct {
+	StartedAt time.Time `json:"started_at" binding:"required"`
+	EndedAt   time.Time `json:"ended_at" binding:"required,gtfield=StartedAt"`
+	Note      string    `json:"note" binding:"max=500"`
+}
+
+// TimesheetFilter narrows the work logs of a timesheet. The dates are
+// inclusive and in UTC, zero values do not filter.
+type TimesheetFilter struct {
+	UserID    string    `form:"user_id" binding:"omitempty,uuid"`
+	ProjectID string    `form:"project_id" binding:"omitempty,uuid"`
+	From      time.Time `form:"from" time_format:"2006-01-02"`
+	To        time.Time `form:"to" time_format:"2006-01-02"`
+	Format    string    `form:"format" binding:"omitempty,oneof=json csv"`
+}
+
+// Timesheet lists the ended work logs of the visible tasks, with the total
+// time of each user
+type Timesheet struct {
+	Entries []TimesheetEntry `json:"entries"`
+	Totals  []TimesheetTotal `json:"totals"`
+	Seconds int64            `json:"seconds"`
+}
+
+type TimesheetEntry struct {
+	WorkLogID uuid.UUID  `json:"work_log_id"`
+	UserID    uuid.UUID  `json:"user_id"`
+	Username  string     `json:"username"`
+	TaskID    uuid.UUID  `json:"task_id"`
+	TaskTitle string     `json:"task_title"`
+	ProjectID *uuid.UUID `json:"project_id"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   time.Time  `json:"ended_at"`
+	Seconds   int64      `json:"seconds"`
+	Note      string     `json:"note"`
+}
+
+type TimesheetTotal struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Username string    `json:"username"`
+	Seconds  int64     `json:"seconds"`
+}
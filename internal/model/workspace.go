@@ -0,0 +1,57 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Roles of a user within a workspace
+const (
+	WorkspaceRoleOwner  = "owner"
+	WorkspaceRoleAdmin  = "admin"
+	WorkspaceRoleMember = "member"
+)
+
+type Workspace struct {
+	ID        uuid.UUID `json:"id" gorm:"primaryKey"`
+	Name      string    `json:"name" gorm:"type:varchar(100);not null"`
+	CreatedBy uuid.UUID `json:"created_by" gorm:"type:uuid"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type WorkspaceMember struct {
+	WorkspaceID uuid.UUID `json:"workspace_id" gorm:"type:uuid;primary_key"`
+	UserID      uuid.UUID `json:"user_id" gorm:"type:uuid;primary_key"`
+	Role        string    `json:"role" gorm:"type:varchar(20);not null"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type CreateWorkspaceRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+type AddMemberRequest struct {
+	UserID uuid.UUID `json:"user_id" binding:"required"`
+	Role   string    `json:"role" binding:"required,oneof=owner admin member"`
+}
+
+type UpdateMemberRequest struct {
+	Role string `json:"role" binding:"required,oneof=owner admin member"`
+}
+
+type InvitationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required,oneof=admin member"`
+}
+
+type InvitationResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type AcceptInvitationRequest struct {
+	Token string `json:"token" binding:"required"`
+}
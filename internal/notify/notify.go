@@ -0,0 +1,67 @@
+// Package notify delivers the notifications of the reminders through the
+// channels configured on the deployment
+package notify
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Channels a notification is delivered through
+const (
+	ChannelInbox   = "inbox"
+	ChannelEmail   = "email"
+	ChannelWebhook = "webhook"
+)
+
+var (
+	ErrNoRecipient      = errors.New("notify: the notification has no recipient")
+	ErrChannelDisabled  = errors.New("notify: the channel is not configured")
+	ErrDeliveryRejected = errors.New("notify: the delivery was rejected")
+)
+
+// Message is a notification to a user about a task
+type Message struct {
+	ReminderID uuid.UUID  `json:"reminder_id"`
+	TaskID     uuid.UUID  `json:"task_id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	Channel    string     `json:"channel"`
+	Subject    string     `json:"subject"`
+	Body       string     `json:"body"`
+	DueAt      *time.Time `json:"due_at"`
+
+	// Email is the address of the user, it is only used by the email
+	// channel
+	Email string `json:"-"`
+}
+
+// Notifier delivers messages through a channel
+type Notifier interface {
+	Notify(context.Context, Message) error
+}
+
+// Notifiers routes the messages to the notifier of their channel
+type Notifiers map[string]Notifier
+
+// Channels lists the configured channels
+func (n Notifiers) Channels() []string {
+	channels := make([]string, 0, len(n))
+	for _, channel := range []string{ChannelInbox, ChannelEmail, ChannelWebhook} {
+		if _, ok := n[channel]; ok {
+			channels = append(channels, channel)
+		}
+	}
+	return channels
+}
+
+// Notify delivers the message through the notifier of its channel
+func (n Notifiers) Notify(ctx context.Context, m Message) error {
+	notifier, ok := n[m.Channel]
+	if !ok {
+		return ErrChannelDisabled
+	}
+	return notifier.Notify(ctx, m)
+}
@@ -0,0 +1,112 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"task-manager/internal/notify/notifytest"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+var message = Message{
+	ReminderID: uuid.Must(uuid.NewV7()),
+	TaskID:     uuid.Must(uuid.NewV7()),
+	UserID:     uuid.Must(uuid.NewV7()),
+	Channel:    ChannelEmail,
+	Subject:    "Reminder: Ship the release\r\nBcc: eve@example.com",
+	Body:       "Ship the release is due on 2026-03-02 09:00 UTC",
+	Email:      "alice@example.com",
+}
+
+func TestSMTPNotifier_Notify(t *testing.T) {
+	server, err := notifytest.NewSMTPServer()
+	require.NoError(t, err)
+	defer server.Close()
+	notifier := NewSMTPNotifier(SMTPConfig{Addr: server.Addr, From: "tasks@example.com"})
+
+	// Test case 1
+	t.Run("Notify: sends the mail", func(t *testing.T) {
+		require.NoError(t, notifier.Notify(context.Background(), message))
+
+		mails := server.Mails()
+		require.Len(t, mails, 1)
+		require.Equal(t, "tasks@example.com", mails[0].From)
+		require.Equal(t, []string{"alice@example.com"}, mails[0].To)
+		require.Contains(t, mails[0].Data, "Subject: Reminder: Ship the release  Bcc: eve@example.com\r\n")
+		require.NotContains(t, mails[0].Data, "\r\nBcc:")
+		require.Contains(t, mails[0].Data, "\r\n\r\nShip the release is due on 2026-03-02 09:00 UTC\r\n")
+	})
+
+	// Test case 2
+	t.Run("Notify: no recipient", func(t *testing.T) {
+		m := message
+		m.Email = ""
+		require.ErrorIs(t, notifier.Notify(context.Background(), m), ErrNoRecipient)
+	})
+
+	// Test case 3
+	t.Run("Notify: recipient rejected", func(t *testing.T) {
+		server.Reject = true
+		defer func() { server.Reject = false }()
+
+		require.ErrorIs(t, notifier.Notify(context.Background(), message), ErrDeliveryRejected)
+	})
+}
+
+func TestWebhookNotifier_Notify(t *testing.T) {
+	var received Message
+	var signature string
+	status := http.StatusNoContent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &received)
+		signature = r.Header.Get(SignatureHeader)
+		require.Equal(t, "sha256="+Sign("secret", body), signature)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+	notifier := NewWebhookNotifier(WebhookConfig{URL: server.URL, Secret: "secret"})
+
+	// Test case 1
+	t.Run("Notify: posts the signed message", func(t *testing.T) {
+		due := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+		m := message
+		m.Channel = ChannelWebhook
+		m.DueAt = &due
+
+		require.NoError(t, notifier.Notify(context.Background(), m))
+		require.Equal(t, m.TaskID, received.TaskID)
+		require.Equal(t, "", received.Email)
+		require.True(t, due.Equal(*received.DueAt))
+		require.NotEmpty(t, signature)
+	})
+
+	// Test case 2
+	t.Run("Notify: rejected", func(t *testing.T) {
+		status = http.StatusServiceUnavailable
+
+		err := notifier.Notify(context.Background(), message)
+		require.ErrorIs(t, err, ErrDeliveryRejected)
+		require.EqualError(t, err, "notify: the delivery was rejected: webhook answered 503 Service Unavailable")
+	})
+}
+
+func TestNotifiers(t *testing.T) {
+	notifiers := Notifiers{ChannelWebhook: NewWebhookNotifier(WebhookConfig{}), ChannelInbox: nil}
+
+	// Test case 1
+	t.Run("Channels: in order", func(t *testing.T) {
+		require.Equal(t, []string{ChannelInbox, ChannelWebhook}, notifiers.Channels())
+	})
+
+	// Test case 2
+	t.Run("Notify: channel disabled", func(t *testing.T) {
+		require.ErrorIs(t, notifiers.Notify(context.Background(), message), ErrChannelDisabled)
+	})
+}
@@ -0,0 +1,132 @@
+// Package notifytest provides a mail server for tests. It accepts every
+// message without authentication and keeps them in memory.
+package notifytest
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"sync"
+)
+
+// Mail is a message received by the server
+type Mail struct {
+	From string
+	To   []string
+	Data string
+}
+
+// SMTPServer is a minimal SMTP server listening on a local port
+type SMTPServer struct {
+	Addr string
+
+	// Reject makes the server refuse the recipients
+	Reject bool
+
+	listener net.Listener
+	mu       sync.Mutex
+	mails    []Mail
+}
+
+// NewSMTPServer starts a server on a random local port
+func NewSMTPServer() (*SMTPServer, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &SMTPServer{Addr: l.Addr().String(), listener: l}
+	go s.serve()
+	return s, nil
+}
+
+// Mails returns the messages received so far
+func (s *SMTPServer) Mails() []Mail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Mail(nil), s.mails...)
+}
+
+func (s *SMTPServer) Close() error {
+	return s.listener.Close()
+}
+
+/*
+	Supporting functions
+*/
+
+func (s *SMTPServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+// handle speaks the commands net/smtp sends, one session per connection
+func (s *SMTPServer) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 localhost ESMTP notifytest")
+	var mail Mail
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(line)
+
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			mail = Mail{From: address(line[len("MAIL FROM:"):])}
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			if s.Reject {
+				reply("550 no such user")
+				continue
+			}
+			mail.To = append(mail.To, address(line[len("RCPT TO:"):]))
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 end data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(l, "."))
+			}
+			mail.Data = data.String()
+			s.mu.Lock()
+			s.mails = append(s.mails, mail)
+			s.mu.Unlock()
+			reply("250 OK")
+		case cmd == "RSET", cmd == "NOOP":
+			reply("250 OK")
+		case cmd == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 command not implemented")
+		}
+	}
+}
+
+func address(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, ' '); i >= 0 {
+		s = s[:i]
+	}
+	return strings.Trim(s, "<>")
+}
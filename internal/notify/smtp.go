@@ -0,0 +1,122 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+)
+
+// SMTPConfig is the mail server the email notifications are sent through
+type SMTPConfig struct {
+	Addr     string
+	From     string
+	Username string
+	Password string
+}
+
+// SMTPConfigFromEnv reads the configuration from SMTP_ADDR, SMTP_FROM,
+// SMTP_USERNAME and SMTP_PASSWORD. It reports false when SMTP_ADDR is
+// unset, i.e. when the email channel is disabled.
+func SMTPConfigFromEnv() (SMTPConfig, bool) {
+	config := SMTPConfig{
+		Addr:     os.Getenv("SMTP_ADDR"),
+		From:     os.Getenv("SMTP_FROM"),
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+	}
+	return config, config.Addr != ""
+}
+
+// SMTPNotifier sends the messages by email to the address of their user
+type SMTPNotifier struct {
+	Config SMTPConfig
+}
+
+func NewSMTPNotifier(config SMTPConfig) *SMTPNotifier {
+	if config.From == "" {
+		config.From = "task-manager@localhost"
+	}
+	return &SMTPNotifier{Config: config}
+}
+
+// Notify sends the message, upgrading the connection with STARTTLS when
+// the server offers it. Authentication is only attempted with a username,
+// and net/smtp refuses to send the password without TLS unless the server
+// is local.
+func (n *SMTPNotifier) Notify(ctx context.Context, m Message) error {
+	if m.Email == "" {
+		return ErrNoRecipient
+	}
+
+	conn, err := (&net.Dialer{Timeout: 10 * time.Second}).DialContext(ctx, "tcp", n.Config.Addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	host, _, _ := net.SplitHostPort(n.Config.Addr)
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if n.Config.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", n.Config.Username, n.Config.Password, host)); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(n.Config.From); err != nil {
+		return err
+	}
+	if err := c.Rcpt(m.Email); err != nil {
+		return fmt.Errorf("%w: %v", ErrDeliveryRejected, err)
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(n.message(m)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+/*
+	Supporting functions
+*/
+
+// message formats the mail, the headers cannot be broken by the task
+// title
+func (n *SMTPNotifier) message(m Message) []byte {
+	header := strings.NewReplacer("\r", " ", "\n", " ")
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", header.Replace(n.Config.From))
+	fmt.Fprintf(&b, "To: %s\r\n", header.Replace(m.Email))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", header.Replace(m.Subject)))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(m.Body, "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return []byte(b.String())
+}
@@ -0,0 +1,84 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+// SignatureHeader carries the HMAC-SHA256 of the body of the webhook
+// requests, keyed with the webhook secret
+const SignatureHeader = "X-Signature-256"
+
+// WebhookConfig is the endpoint the webhook notifications are posted to
+type WebhookConfig struct {
+	URL    string
+	Secret string
+}
+
+// WebhookConfigFromEnv reads the configuration from WEBHOOK_URL and
+// WEBHOOK_SECRET. It reports false when WEBHOOK_URL is unset, i.e. when
+// the webhook channel is disabled.
+func WebhookConfigFromEnv() (WebhookConfig, bool) {
+	config := WebhookConfig{
+		URL:    os.Getenv("WEBHOOK_URL"),
+		Secret: os.Getenv("WEBHOOK_SECRET"),
+	}
+	return config, config.URL != ""
+}
+
+// WebhookNotifier posts the messages as JSON to the webhook of the
+// deployment
+type WebhookNotifier struct {
+	Config WebhookConfig
+	Client *http.Client
+}
+
+func NewWebhookNotifier(config WebhookConfig) *WebhookNotifier {
+	return &WebhookNotifier{Config: config, Client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Notify posts the message, any answer but a 2xx is a failed delivery
+func (n *WebhookNotifier) Notify(ctx context.Context, m Message) error {
+	body, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.Config.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.Config.Secret != "" {
+		req.Header.Set(SignatureHeader, "sha256="+Sign(n.Config.Secret, body))
+	}
+
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%w: webhook answered %s", ErrDeliveryRejected, resp.Status)
+	}
+	return nil
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of the body, for the receivers
+// to check the signature header
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,85 @@
+package oidc
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// Leeway is the clock skew tolerated between this service and the
+// provider when checking the time based claims
+var Leeway = time.Minute
+
+// IDTokenClaims are the claims of an ID token this service maps to a
+// local user
+type IDTokenClaims struct {
+	Issuer          string   `json:"iss"`
+	Subject         string   `json:"sub"`
+	Audience        Audience `json:"aud"`
+	ExpiresAt       int64    `json:"exp"`
+	IssuedAt        int64    `json:"iat"`
+	NotBefore       int64    `json:"nbf,omitempty"`
+	Nonce           string   `json:"nonce,omitempty"`
+	AuthorizedParty string   `json:"azp,omitempty"`
+
+	Email             string `json:"email,omitempty"`
+	EmailVerified     bool   `json:"email_verified,omitempty"`
+	PreferredUsername string `json:"preferred_username,omitempty"`
+	Name              string `json:"name,omitempty"`
+}
+
+// Valid verifies the time based claims. The issuer, the audience and the
+// nonce are checked by Provider.VerifyIDToken.
+func (c *IDTokenClaims) Valid() error {
+	now := jwt.TimeFunc()
+	vErr := &jwt.ValidationError{}
+
+	if c.ExpiresAt == 0 || now.Add(-Leeway).Unix() > c.ExpiresAt {
+		vErr.Inner = errors.New("token is expired")
+		vErr.Errors |= jwt.ValidationErrorExpired
+	}
+	if now.Add(Leeway).Unix() < c.IssuedAt {
+		vErr.Inner = errors.New("token used before issued")
+		vErr.Errors |= jwt.ValidationErrorIssuedAt
+	}
+	if c.NotBefore != 0 && now.Add(Leeway).Unix() < c.NotBefore {
+		vErr.Inner = errors.New("token is not valid yet")
+		vErr.Errors |= jwt.ValidationErrorNotValidYet
+	}
+
+	if vErr.Errors == 0 {
+		return nil
+	}
+	return vErr
+}
+
+// Audience is the "aud" claim, which is either a string or an array of
+// strings
+type Audience []string
+
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = Audience{single}
+		return nil
+	}
+
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+// Contains reports whether the audience includes the client
+func (a Audience) Contains(clientID string) bool {
+	for _, aud := range a {
+		if aud == clientID {
+			return true
+		}
+	}
+	return false
+}
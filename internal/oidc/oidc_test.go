@@ -0,0 +1,135 @@
+package oidc_test
+
+import (
+	"context"
+	"encoding/json"
+	"task-manager/internal/oidc"
+	"task-manager/internal/oidc/oidctest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const redirectURL = "http://localhost:8080/auth/oidc/callback"
+
+func newProvider(t *testing.T) (*oidctest.Server, *oidc.Provider) {
+	idp, err := oidctest.NewServer("task-manager", "client-secret")
+	require.Nil(t, err)
+	t.Cleanup(idp.Close)
+	return idp, oidc.NewProvider(idp.Config(redirectURL))
+}
+
+func TestProvider_Flow(t *testing.T) {
+	ctx := context.Background()
+	idp, provider := newProvider(t)
+	idp.SetUser(map[string]interface{}{"sub": "idp-42", "email": "alice@example.com", "email_verified": true})
+
+	verifier, err := oidc.GenerateCodeVerifier()
+	require.Nil(t, err)
+
+	authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", oidc.CodeChallengeS256(verifier))
+	require.Nil(t, err)
+	code, state, err := idp.Login(authURL)
+	require.Nil(t, err)
+	require.Equal(t, "state-1", state)
+
+	// Test case 1: the code is bound to the verifier
+	_, err = provider.Exchange(ctx, code, "not-the-verifier")
+	require.ErrorIs(t, err, oidc.ErrExchangeFailed)
+
+	// Test case 2: a failed exchange burnt the code
+	_, err = provider.Exchange(ctx, code, verifier)
+	require.ErrorIs(t, err, oidc.ErrExchangeFailed)
+
+	// Test case 3: success
+	code, _, err = idp.Login(authURL)
+	require.Nil(t, err)
+	idToken, err := provider.Exchange(ctx, code, verifier)
+	require.Nil(t, err)
+
+	claims, err := provider.VerifyIDToken(ctx, idToken, "nonce-1")
+	require.Nil(t, err)
+	require.Equal(t, "idp-42", claims.Subject)
+	require.Equal(t, "alice@example.com", claims.Email)
+	require.True(t, claims.EmailVerified)
+
+	// Test case 4: the ID token belongs to another login
+	_, err = provider.VerifyIDToken(ctx, idToken, "nonce-2")
+	require.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+}
+
+func TestProvider_VerifyIDToken(t *testing.T) {
+	ctx := context.Background()
+	idp, provider := newProvider(t)
+
+	tests := []struct {
+		name   string
+		claims map[string]interface{}
+		valid  bool
+	}{
+		{
+			name:   "valid",
+			claims: map[string]interface{}{"sub": "idp-42"},
+			valid:  true,
+		},
+		{
+			name:   "audience list with authorized party",
+			claims: map[string]interface{}{"sub": "idp-42", "aud": []string{"other", "task-manager"}, "azp": "task-manager"},
+			valid:  true,
+		},
+		{
+			name:   "other audience",
+			claims: map[string]interface{}{"sub": "idp-42", "aud": "other"},
+		},
+		{
+			name:   "other issuer",
+			claims: map[string]interface{}{"sub": "idp-42", "iss": "https://evil.example.com"},
+		},
+		{
+			name:   "expired",
+			claims: map[string]interface{}{"sub": "idp-42", "exp": time.Now().Add(-time.Hour).Unix()},
+		},
+		{
+			name:   "no subject",
+			claims: map[string]interface{}{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idToken, err := idp.IDToken("nonce-1", tt.claims)
+			require.Nil(t, err)
+
+			_, err = provider.VerifyIDToken(ctx, idToken, "nonce-1")
+			if tt.valid {
+				require.Nil(t, err)
+			} else {
+				require.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+			}
+		})
+	}
+}
+
+func TestProvider_Discovery(t *testing.T) {
+	idp, _ := newProvider(t)
+
+	config := idp.Config(redirectURL)
+	config.Issuer = idp.URL + "/other"
+	_, err := oidc.NewProvider(config).Metadata(context.Background())
+	require.ErrorIs(t, err, oidc.ErrDiscoveryFailed)
+}
+
+func TestAudience_UnmarshalJSON(t *testing.T) {
+	var claims oidc.IDTokenClaims
+	require.Nil(t, json.Unmarshal([]byte(`{"aud":"a"}`), &claims))
+	require.Equal(t, oidc.Audience{"a"}, claims.Audience)
+
+	require.Nil(t, json.Unmarshal([]byte(`{"aud":["a","b"]}`), &claims))
+	require.Equal(t, oidc.Audience{"a", "b"}, claims.Audience)
+}
+
+func TestCodeChallengeS256(t *testing.T) {
+	// Example of RFC 7636, appendix B
+	require.Equal(t, "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", oidc.CodeChallengeS256("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"))
+}
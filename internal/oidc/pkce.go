@@ -0,0 +1,19 @@
+package oidc
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"task-manager/internal/auth"
+)
+
+// GenerateCodeVerifier returns a PKCE code verifier of 43 characters
+func GenerateCodeVerifier() (string, error) {
+	return auth.GenerateOpaqueToken(32)
+}
+
+// CodeChallengeS256 derives the code challenge sent to the provider from
+// the verifier, which is only revealed when the code is exchanged
+func CodeChallengeS256(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
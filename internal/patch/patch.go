@@ -0,0 +1,302 @@
+// Package patch applies JSON Merge Patch (RFC 7396) and JSON Patch
+// (RFC 6902) documents to JSON documents.
+package patch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Media types of the patch documents
+const (
+	MergePatchType = "application/merge-patch+json"
+	JSONPatchType  = "application/json-patch+json"
+)
+
+var (
+	ErrInvalidPatch = errors.New("invalid patch")
+	ErrPathNotFound = errors.New("path not found")
+	ErrTestFailed   = errors.New("test operation failed")
+)
+
+// Operation is an operation of a JSON Patch document. Value is nil when
+// the operation has no value, and "null" when the value is null.
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// MergePatch merges the patch into the document following RFC 7396, null
+// members of the patch remove the members of the document
+func MergePatch(doc, patch []byte) ([]byte, error) {
+	var target, p interface{}
+	if err := json.Unmarshal(doc, &target); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+	return json.Marshal(merge(target, p))
+}
+
+// Apply applies the operations of the patch to the document in order
+// following RFC 6902. The document is left untouched when an operation
+// fails.
+func Apply(doc, patch []byte) ([]byte, error) {
+	var ops []Operation
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+	var root interface{}
+	if err := json.Unmarshal(doc, &root); err != nil {
+		return nil, err
+	}
+
+	for i, op := range ops {
+		var err error
+		if root, err = apply(root, op); err != nil {
+			return nil, fmt.Errorf("operation %d: %w", i, err)
+		}
+	}
+	return json.Marshal(root)
+}
+
+/*
+	Supporting functions
+*/
+
+func merge(target, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]interface{})
+	if !ok {
+		t = make(map[string]interface{})
+	}
+	for key, value := range p {
+		if value == nil {
+			delete(t, key)
+			continue
+		}
+		t[key] = merge(t[key], value)
+	}
+	return t
+}
+
+func apply(root interface{}, op Operation) (interface{}, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return nil, fmt.Errorf("%w: %s needs a value", ErrInvalidPatch, op.Op)
+		}
+		var value interface{}
+		if err := json.Unmarshal(op.Value, &value); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+		}
+		switch op.Op {
+		case "add":
+			return add(root, path, value)
+		case "replace":
+			if _, err := get(root, path); err != nil {
+				return nil, err
+			}
+			if root, _, err = remove(root, path); err != nil {
+				return nil, err
+			}
+			return add(root, path, value)
+		default:
+			current, err := get(root, path)
+			if err != nil {
+				return nil, err
+			}
+			if !reflect.DeepEqual(current, value) {
+				return nil, fmt.Errorf("%w: %s", ErrTestFailed, op.Path)
+			}
+			return root, nil
+		}
+	case "remove":
+		root, _, err = remove(root, path)
+		return root, err
+	case "move", "copy":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		var value interface{}
+		if op.Op == "move" {
+			if strings.HasPrefix(op.Path, op.From+"/") {
+				return nil, fmt.Errorf("%w: cannot move %s into itself", ErrInvalidPatch, op.From)
+			}
+			root, value, err = remove(root, from)
+		} else {
+			value, err = get(root, from)
+			if err == nil {
+				value, err = clone(value)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		return add(root, path, value)
+	default:
+		return nil, fmt.Errorf("%w: unknown operation %q", ErrInvalidPatch, op.Op)
+	}
+}
+
+// parsePointer splits the JSON pointer (RFC 6901) into its unescaped
+// tokens, the empty pointer refers to the whole document
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if pointer[0] != '/' {
+		return nil, fmt.Errorf("%w: invalid path %q", ErrInvalidPatch, pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	unescape := strings.NewReplacer("~1", "/", "~0", "~")
+	for i, token := range tokens {
+		tokens[i] = unescape.Replace(token)
+	}
+	return tokens, nil
+}
+
+func get(node interface{}, path []string) (interface{}, error) {
+	for _, token := range path {
+		var err error
+		if node, err = child(node, token); err != nil {
+			return nil, err
+		}
+	}
+	return node, nil
+}
+
+func add(root interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return edit(root, path, func(container interface{}, token string) (interface{}, error) {
+		switch c := container.(type) {
+		case map[string]interface{}:
+			c[token] = value
+			return c, nil
+		case []interface{}:
+			if token == "-" {
+				return append(c, value), nil
+			}
+			i, err := index(token, len(c)+1)
+			if err != nil {
+				return nil, err
+			}
+			c = append(c, nil)
+			copy(c[i+1:], c[i:])
+			c[i] = value
+			return c, nil
+		default:
+			return nil, ErrPathNotFound
+		}
+	})
+}
+
+// remove removes the value at the path, and returns it along with the
+// updated document
+func remove(root interface{}, path []string) (interface{}, interface{}, error) {
+	if len(path) == 0 {
+		return nil, nil, fmt.Errorf("%w: cannot remove the whole document", ErrInvalidPatch)
+	}
+
+	var removed interface{}
+	root, err := edit(root, path, func(container interface{}, token string) (interface{}, error) {
+		var err error
+		if removed, err = child(container, token); err != nil {
+			return nil, err
+		}
+		switch c := container.(type) {
+		case map[string]interface{}:
+			delete(c, token)
+			return c, nil
+		case []interface{}:
+			i, _ := index(token, len(c))
+			return append(c[:i], c[i+1:]...), nil
+		default:
+			return nil, ErrPathNotFound
+		}
+	})
+	return root, removed, err
+}
+
+// edit replaces the container of the last token of the path with the
+// result of fn, and returns the updated document
+func edit(node interface{}, path []string, fn func(interface{}, string) (interface{}, error)) (interface{}, error) {
+	if len(path) == 1 {
+		return fn(node, path[0])
+	}
+
+	next, err := child(node, path[0])
+	if err != nil {
+		return nil, err
+	}
+	if next, err = edit(next, path[1:], fn); err != nil {
+		return nil, err
+	}
+	switch c := node.(type) {
+	case map[string]interface{}:
+		c[path[0]] = next
+	case []interface{}:
+		i, _ := index(path[0], len(c))
+		c[i] = next
+	}
+	return node, nil
+}
+
+func child(node interface{}, token string) (interface{}, error) {
+	switch c := node.(type) {
+	case map[string]interface{}:
+		value, ok := c[token]
+		if !ok {
+			return nil, ErrPathNotFound
+		}
+		return value, nil
+	case []interface{}:
+		i, err := index(token, len(c))
+		if err != nil {
+			return nil, err
+		}
+		return c[i], nil
+	default:
+		return nil, ErrPathNotFound
+	}
+}
+
+// index parses the array index, which must be below size
+func index(token string, size int) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, ErrPathNotFound
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || i >= size {
+		return 0, ErrPathNotFound
+	}
+	return i, nil
+}
+
+func clone(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var copied interface{}
+	err = json.Unmarshal(data, &copied)
+	return copied, err
+}
@@ -0,0 +1,75 @@
+package patch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+	}{
+		{name: "replace member", doc: `{"a":"b"}`, patch: `{"a":"c"}`, want: `{"a":"c"}`},
+		{name: "add member", doc: `{"a":"b"}`, patch: `{"b":"c"}`, want: `{"a":"b","b":"c"}`},
+		{name: "null removes member", doc: `{"a":"b","b":"c"}`, patch: `{"a":null}`, want: `{"b":"c"}`},
+		{name: "arrays are replaced", doc: `{"a":["b"]}`, patch: `{"a":["c","d"]}`, want: `{"a":["c","d"]}`},
+		{name: "nested objects are merged", doc: `{"a":{"b":"c","d":"e"}}`, patch: `{"a":{"d":null,"f":"g"}}`, want: `{"a":{"b":"c","f":"g"}}`},
+		{name: "non object patch replaces", doc: `{"a":"b"}`, patch: `["c"]`, want: `["c"]`},
+	}
+
+	for _, tt := range tests {
+		t.Run("MergePatch: "+tt.name, func(t *testing.T) {
+			got, err := MergePatch([]byte(tt.doc), []byte(tt.patch))
+			require.NoError(t, err)
+			require.JSONEq(t, tt.want, string(got))
+		})
+	}
+
+	// Test case 7
+	t.Run("MergePatch: invalid patch", func(t *testing.T) {
+		_, err := MergePatch([]byte(`{}`), []byte(`{`))
+		require.ErrorIs(t, err, ErrInvalidPatch)
+	})
+}
+
+func TestApply(t *testing.T) {
+	doc := `{"title":"Task","tags":["a","b"],"meta":{"a/b":1,"m~n":2}}`
+
+	tests := []struct {
+		name  string
+		patch string
+		want  string
+		err   error
+	}{
+		{name: "add member", patch: `[{"op":"add","path":"/status","value":"pending"}]`, want: `{"title":"Task","status":"pending","tags":["a","b"],"meta":{"a/b":1,"m~n":2}}`},
+		{name: "add to array", patch: `[{"op":"add","path":"/tags/1","value":"x"},{"op":"add","path":"/tags/-","value":"y"}]`, want: `{"title":"Task","tags":["a","x","b","y"],"meta":{"a/b":1,"m~n":2}}`},
+		{name: "remove escaped members", patch: `[{"op":"remove","path":"/meta/a~1b"},{"op":"remove","path":"/meta/m~0n"}]`, want: `{"title":"Task","tags":["a","b"],"meta":{}}`},
+		{name: "replace", patch: `[{"op":"replace","path":"/title","value":null}]`, want: `{"title":null,"tags":["a","b"],"meta":{"a/b":1,"m~n":2}}`},
+		{name: "move", patch: `[{"op":"move","from":"/tags/0","path":"/first"}]`, want: `{"title":"Task","first":"a","tags":["b"],"meta":{"a/b":1,"m~n":2}}`},
+		{name: "copy", patch: `[{"op":"copy","from":"/tags","path":"/labels"}]`, want: `{"title":"Task","tags":["a","b"],"labels":["a","b"],"meta":{"a/b":1,"m~n":2}}`},
+		{name: "test passes", patch: `[{"op":"test","path":"/tags","value":["a","b"]},{"op":"replace","path":"/title","value":"Done"}]`, want: `{"title":"Done","tags":["a","b"],"meta":{"a/b":1,"m~n":2}}`},
+		{name: "test fails", patch: `[{"op":"replace","path":"/title","value":"Done"},{"op":"test","path":"/title","value":"Task"}]`, err: ErrTestFailed},
+		{name: "replace missing member", patch: `[{"op":"replace","path":"/status","value":"pending"}]`, err: ErrPathNotFound},
+		{name: "array index out of range", patch: `[{"op":"add","path":"/tags/3","value":"x"}]`, err: ErrPathNotFound},
+		{name: "unknown operation", patch: `[{"op":"merge","path":"/title","value":"x"}]`, err: ErrInvalidPatch},
+		{name: "missing value", patch: `[{"op":"add","path":"/title"}]`, err: ErrInvalidPatch},
+		{name: "move into itself", patch: `[{"op":"move","from":"/meta","path":"/meta/inner"}]`, err: ErrInvalidPatch},
+		{name: "not an array", patch: `{"op":"add"}`, err: ErrInvalidPatch},
+	}
+
+	for _, tt := range tests {
+		t.Run("Apply: "+tt.name, func(t *testing.T) {
+			got, err := Apply([]byte(doc), []byte(tt.patch))
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
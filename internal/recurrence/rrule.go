@@ -0,0 +1,458 @@
+// Package recurrence expands the RFC 5545 recurrence rules of the
+// recurring tasks.
+//
+// The supported subset covers FREQ (DAILY, WEEKLY, MONTHLY, YEARLY),
+// INTERVAL, COUNT, UNTIL, BYDAY, BYMONTHDAY and BYMONTH. Occurrences keep
+// the wall clock time of the start of the schedule in its location, so
+// a task due at 09:00 stays due at 09:00 across daylight saving changes.
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	// Schedules are expanded in the IANA timezones even when the host
+	// has no timezone database
+	_ "time/tzdata"
+)
+
+// Frequencies of a rule
+const (
+	Daily   = "DAILY"
+	Weekly  = "WEEKLY"
+	Monthly = "MONTHLY"
+	Yearly  = "YEARLY"
+)
+
+// DateLayout is the layout of the exception dates of a schedule
+const DateLayout = "2006-01-02"
+
+// maxPeriods bounds the expansion of the rules matching rarely, or never
+// like the 30th of February
+const maxPeriods = 10000
+
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// Weekday is a day of the week of BYDAY. N is the position of the day in
+// the month or year, counted from the end when negative, or every such
+// day when 0.
+type Weekday struct {
+	N   int
+	Day time.Weekday
+}
+
+// Rule is a parsed RRULE
+type Rule struct {
+	Freq       string
+	Interval   int
+	Count      int
+	Until      time.Time
+	ByDay      []Weekday
+	ByMonthDay []int
+	ByMonth    []time.Month
+}
+
+// Parse parses the value of an RRULE property, with or without its
+// "RRULE:" prefix
+func Parse(s string) (*Rule, error) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(strings.ToUpper(s), "RRULE:")
+	if s == "" {
+		return nil, invalid("the rule is empty")
+	}
+
+	rule := &Rule{Interval: 1}
+	seen := map[string]bool{}
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, invalid("%q is not a KEY=VALUE pair", part)
+		}
+		if seen[key] {
+			return nil, invalid("%s is repeated", key)
+		}
+		seen[key] = true
+
+		var err error
+		switch key {
+		case "FREQ":
+			switch value {
+			case Daily, Weekly, Monthly, Yearly:
+				rule.Freq = value
+			default:
+				err = invalid("FREQ=%s is not supported", value)
+			}
+		case "INTERVAL":
+			rule.Interval, err = parseInt(key, value, 1, 1000)
+		case "COUNT":
+			rule.Count, err = parseInt(key, value, 1, maxPeriods)
+		case "UNTIL":
+			rule.Until, err = parseUntil(value)
+		case "BYDAY":
+			rule.ByDay, err = parseByDay(value)
+		case "BYMONTHDAY":
+			for _, v := range strings.Split(value, ",") {
+				var day int
+				if day, err = parseInt(key, v, -31, 31); err != nil {
+					break
+				}
+				if day == 0 {
+					err = invalid("BYMONTHDAY cannot be 0")
+					break
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, day)
+			}
+		case "BYMONTH":
+			for _, v := range strings.Split(value, ",") {
+				var month int
+				if month, err = parseInt(key, v, 1, 12); err != nil {
+					break
+				}
+				rule.ByMonth = append(rule.ByMonth, time.Month(month))
+			}
+		case "WKST":
+			// Weeks start on Monday, the default of RFC 5545
+			if value != "MO" {
+				err = invalid("only WKST=MO is supported")
+			}
+		default:
+			err = invalid("%s is not supported", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := rule.validate(); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (r *Rule) validate() error {
+	if r.Freq == "" {
+		return invalid("FREQ is required")
+	}
+	if r.Count > 0 && !r.Until.IsZero() {
+		return invalid("COUNT and UNTIL cannot be combined")
+	}
+	if r.Freq == Weekly && len(r.ByMonthDay) > 0 {
+		return invalid("BYMONTHDAY cannot be used with FREQ=WEEKLY")
+	}
+	for _, wd := range r.ByDay {
+		if wd.N == 0 {
+			continue
+		}
+		switch {
+		case r.Freq != Monthly && r.Freq != Yearly:
+			return invalid("numbered BYDAY needs FREQ=MONTHLY or FREQ=YEARLY")
+		case wd.N < -5 || wd.N > 5:
+			return invalid("BYDAY positions range from -5 to 5")
+		}
+	}
+	// Days of the year are not supported, yearly weekdays are counted
+	// within their months
+	if r.Freq == Yearly && len(r.ByDay) > 0 && len(r.ByMonth) == 0 {
+		return invalid("BYDAY with FREQ=YEARLY needs BYMONTH")
+	}
+	return nil
+}
+
+// Schedule expands a rule from its start, skipping the exception dates
+type Schedule struct {
+	Rule *Rule
+
+	// Start is the first possible occurrence, in the location of the
+	// schedule
+	Start time.Time
+
+	except map[string]bool
+}
+
+// NewSchedule returns the schedule of the rule from start, in loc.
+// Occurrences on the exception dates, formatted with DateLayout in loc,
+// are skipped.
+func NewSchedule(rule *Rule, start time.Time, loc *time.Location, except []string) *Schedule {
+	s := &Schedule{Rule: rule, Start: start.In(loc), except: map[string]bool{}}
+	for _, date := range except {
+		s.except[date] = true
+	}
+	return s
+}
+
+// Next returns the first occurrence strictly after the time, or false when
+// the schedule has no more occurrences. Skipped occurrences count toward
+// COUNT, like the EXDATE of RFC 5545.
+func (s *Schedule) Next(after time.Time) (time.Time, bool) {
+	count := 0
+	for period := 0; period < maxPeriods; period++ {
+		for _, t := range s.occurrences(period) {
+			if t.Before(s.Start) {
+				continue
+			}
+			if !s.Rule.Until.IsZero() && t.After(s.Rule.Until) {
+				return time.Time{}, false
+			}
+			count++
+			if s.Rule.Count > 0 && count > s.Rule.Count {
+				return time.Time{}, false
+			}
+			if t.After(after) && !s.except[t.Format(DateLayout)] {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// Latest returns the last occurrence at or before the time, or false when
+// the schedule has none by then
+func (s *Schedule) Latest(at time.Time) (time.Time, bool) {
+	var latest time.Time
+	found := false
+	count := 0
+	for period := 0; period < maxPeriods; period++ {
+		for _, t := range s.occurrences(period) {
+			if t.Before(s.Start) {
+				continue
+			}
+			if t.After(at) || !s.Rule.Until.IsZero() && t.After(s.Rule.Until) {
+				return latest, found
+			}
+			count++
+			if s.Rule.Count > 0 && count > s.Rule.Count {
+				return latest, found
+			}
+			if !s.except[t.Format(DateLayout)] {
+				latest, found = t, true
+			}
+		}
+	}
+	return latest, found
+}
+
+// First returns the first occurrence of the schedule
+func (s *Schedule) First() (time.Time, bool) {
+	return s.Next(s.Start.Add(-time.Nanosecond))
+}
+
+/*
+	Supporting functions
+*/
+
+// occurrences returns the sorted occurrences of the rule in the period,
+// counted in intervals from the period of the start
+func (s *Schedule) occurrences(period int) []time.Time {
+	r, start := s.Rule, s.Start
+	n := period * r.Interval
+
+	var days []time.Time
+	switch r.Freq {
+	case Daily:
+		days = []time.Time{start.AddDate(0, 0, n)}
+	case Weekly:
+		if len(r.ByDay) == 0 {
+			days = []time.Time{start.AddDate(0, 0, 7*n)}
+			break
+		}
+		monday := start.AddDate(0, 0, 7*n-weekdayIndex(start.Weekday()))
+		for _, wd := range r.ByDay {
+			days = append(days, monday.AddDate(0, 0, weekdayIndex(wd.Day)))
+		}
+	case Monthly:
+		days = s.monthDays(start.Year(), start.Month()+time.Month(n))
+	case Yearly:
+		months := r.ByMonth
+		if len(months) == 0 {
+			months = []time.Month{start.Month()}
+		}
+		for _, month := range months {
+			days = append(days, s.monthDays(start.Year()+n, month)...)
+		}
+	}
+
+	filtered := days[:0]
+	for _, day := range days {
+		if s.matches(day) {
+			filtered = append(filtered, day)
+		}
+	}
+	sort.Slice(filtered, func(i, j int) bool { return filtered[i].Before(filtered[j]) })
+	return filtered
+}
+
+// matches applies the BY rules limiting the days of the frequencies
+// expanding them
+func (s *Schedule) matches(day time.Time) bool {
+	r := s.Rule
+	if len(r.ByMonth) > 0 && r.Freq != Yearly && !containsMonth(r.ByMonth, day.Month()) {
+		return false
+	}
+	if r.Freq != Daily {
+		return true
+	}
+	if len(r.ByMonthDay) > 0 && !containsMonthDay(r.ByMonthDay, day) {
+		return false
+	}
+	if len(r.ByDay) > 0 && !containsWeekday(r.ByDay, day.Weekday()) {
+		return false
+	}
+	return true
+}
+
+// monthDays returns the days of the month matching BYMONTHDAY and BYDAY,
+// or the day of the month of the start. Months too short for the day are
+// skipped.
+func (s *Schedule) monthDays(year int, month time.Month) []time.Time {
+	r, start := s.Rule, s.Start
+	first := time.Date(year, month, 1, 0, 0, 0, 0, start.Location())
+	year, month = first.Year(), first.Month()
+	last := first.AddDate(0, 1, -1).Day()
+
+	set := map[int]bool{}
+	if len(r.ByMonthDay) == 0 && len(r.ByDay) == 0 {
+		if start.Day() <= last {
+			set[start.Day()] = true
+		}
+	}
+	for _, md := range r.ByMonthDay {
+		day := md
+		if md < 0 {
+			day = last + md + 1
+		}
+		if day >= 1 && day <= last {
+			set[day] = true
+		}
+	}
+	if len(r.ByDay) > 0 {
+		byDay := map[int]bool{}
+		for _, wd := range r.ByDay {
+			var matching []int
+			for day := 1; day <= last; day++ {
+				if first.AddDate(0, 0, day-1).Weekday() == wd.Day {
+					matching = append(matching, day)
+				}
+			}
+			switch {
+			case wd.N == 0:
+				for _, day := range matching {
+					byDay[day] = true
+				}
+			case wd.N > 0 && wd.N <= len(matching):
+				byDay[matching[wd.N-1]] = true
+			case wd.N < 0 && -wd.N <= len(matching):
+				byDay[matching[len(matching)+wd.N]] = true
+			}
+		}
+		// BYDAY limits the days of BYMONTHDAY when both are given
+		if len(r.ByMonthDay) > 0 {
+			for day := range set {
+				if !byDay[day] {
+					delete(set, day)
+				}
+			}
+		} else {
+			set = byDay
+		}
+	}
+
+	days := make([]time.Time, 0, len(set))
+	for day := range set {
+		days = append(days, time.Date(year, month, day, start.Hour(), start.Minute(), start.Second(), 0, start.Location()))
+	}
+	return days
+}
+
+// weekdayIndex counts the days from Monday
+func weekdayIndex(day time.Weekday) int {
+	return (int(day) + 6) % 7
+}
+
+func containsMonth(months []time.Month, month time.Month) bool {
+	for _, m := range months {
+		if m == month {
+			return true
+		}
+	}
+	return false
+}
+
+func containsMonthDay(monthDays []int, day time.Time) bool {
+	last := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, day.Location()).Day()
+	for _, md := range monthDays {
+		if md == day.Day() || md < 0 && last+md+1 == day.Day() {
+			return true
+		}
+	}
+	return false
+}
+
+func containsWeekday(days []Weekday, day time.Weekday) bool {
+	for _, wd := range days {
+		if wd.Day == day {
+			return true
+		}
+	}
+	return false
+}
+
+func parseInt(key, value string, min, max int) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < min || n > max {
+		return 0, invalid("%s must be a number from %d to %d", key, min, max)
+	}
+	return n, nil
+}
+
+// parseUntil accepts the UTC date times and the dates of RFC 5545, a date
+// includes the whole day
+func parseUntil(value string) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("20060102", value); err == nil {
+		return t.AddDate(0, 0, 1).Add(-time.Second), nil
+	}
+	return time.Time{}, invalid("UNTIL must be a date or a UTC date time")
+}
+
+func parseByDay(value string) ([]Weekday, error) {
+	var days []Weekday
+	for _, v := range strings.Split(value, ",") {
+		if len(v) < 2 {
+			return nil, invalid("%q is not a weekday", v)
+		}
+		day, ok := weekdays[v[len(v)-2:]]
+		if !ok {
+			return nil, invalid("%q is not a weekday", v)
+		}
+		wd := Weekday{Day: day}
+		if prefix := v[:len(v)-2]; prefix != "" {
+			n, err := strconv.Atoi(prefix)
+			if err != nil || n == 0 {
+				return nil, invalid("%q is not a weekday", v)
+			}
+			wd.N = n
+		}
+		days = append(days, wd)
+	}
+	return days, nil
+}
+
+func invalid(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrInvalidRule, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,221 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	// Test case 1
+	t.Run("Parse: full rule", func(t *testing.T) {
+		rule, err := Parse("RRULE:FREQ=MONTHLY;INTERVAL=2;BYDAY=MO,-1FR;BYMONTH=1,7;UNTIL=20261231")
+		require.NoError(t, err)
+		require.Equal(t, Monthly, rule.Freq)
+		require.Equal(t, 2, rule.Interval)
+		require.Equal(t, []Weekday{{Day: time.Monday}, {N: -1, Day: time.Friday}}, rule.ByDay)
+		require.Equal(t, []time.Month{time.January, time.July}, rule.ByMonth)
+		require.Equal(t, time.Date(2026, 12, 31, 23, 59, 59, 0, time.UTC), rule.Until)
+	})
+
+	tests := []struct {
+		name string
+		rule string
+	}{
+		{name: "empty", rule: ""},
+		{name: "missing freq", rule: "INTERVAL=2"},
+		{name: "unsupported freq", rule: "FREQ=HOURLY"},
+		{name: "unsupported part", rule: "FREQ=DAILY;BYHOUR=9"},
+		{name: "repeated part", rule: "FREQ=DAILY;FREQ=WEEKLY"},
+		{name: "not a pair", rule: "FREQ=DAILY;COUNT"},
+		{name: "zero interval", rule: "FREQ=DAILY;INTERVAL=0"},
+		{name: "count and until", rule: "FREQ=DAILY;COUNT=2;UNTIL=20260101"},
+		{name: "invalid weekday", rule: "FREQ=WEEKLY;BYDAY=XX"},
+		{name: "numbered weekly day", rule: "FREQ=WEEKLY;BYDAY=1MO"},
+		{name: "zero month day", rule: "FREQ=MONTHLY;BYMONTHDAY=0"},
+		{name: "weekly month day", rule: "FREQ=WEEKLY;BYMONTHDAY=1"},
+		{name: "yearly weekday without month", rule: "FREQ=YEARLY;BYDAY=MO"},
+	}
+
+	for _, tt := range tests {
+		t.Run("Parse: "+tt.name, func(t *testing.T) {
+			_, err := Parse(tt.rule)
+			require.ErrorIs(t, err, ErrInvalidRule)
+		})
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		rule   string
+		start  time.Time
+		except []string
+		want   []time.Time
+	}{
+		{
+			name:  "daily with interval",
+			rule:  "FREQ=DAILY;INTERVAL=2;COUNT=3",
+			start: time.Date(2026, 1, 30, 9, 0, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2026, 1, 30, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 2, 3, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "weekly on weekdays",
+			rule:  "FREQ=WEEKLY;BYDAY=MO,WE,FR;COUNT=4",
+			start: time.Date(2026, 3, 4, 8, 30, 0, 0, time.UTC), // Wednesday
+			want: []time.Time{
+				time.Date(2026, 3, 4, 8, 30, 0, 0, time.UTC),
+				time.Date(2026, 3, 6, 8, 30, 0, 0, time.UTC),
+				time.Date(2026, 3, 9, 8, 30, 0, 0, time.UTC),
+				time.Date(2026, 3, 11, 8, 30, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "monthly skips short months",
+			rule:  "FREQ=MONTHLY;COUNT=3",
+			start: time.Date(2026, 1, 31, 12, 0, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2026, 1, 31, 12, 0, 0, 0, time.UTC),
+				time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC),
+				time.Date(2026, 5, 31, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "last friday of the month",
+			rule:  "FREQ=MONTHLY;BYDAY=-1FR;COUNT=3",
+			start: time.Date(2026, 1, 1, 17, 0, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2026, 1, 30, 17, 0, 0, 0, time.UTC),
+				time.Date(2026, 2, 27, 17, 0, 0, 0, time.UTC),
+				time.Date(2026, 3, 27, 17, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "last day of the month",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=-1;UNTIL=20260401",
+			start: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "yearly on leap days",
+			rule:  "FREQ=YEARLY;COUNT=2",
+			start: time.Date(2024, 2, 29, 10, 0, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2024, 2, 29, 10, 0, 0, 0, time.UTC),
+				time.Date(2028, 2, 29, 10, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "yearly first monday of months",
+			rule:  "FREQ=YEARLY;BYMONTH=3,9;BYDAY=1MO;COUNT=3",
+			start: time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 9, 7, 9, 0, 0, 0, time.UTC),
+				time.Date(2027, 3, 1, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:   "exception dates count toward count",
+			rule:   "FREQ=DAILY;COUNT=3",
+			start:  time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC),
+			except: []string{"2026-05-02"},
+			want: []time.Time{
+				time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 5, 3, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "keeps the wall clock across daylight saving",
+			rule:  "FREQ=DAILY;COUNT=3",
+			start: time.Date(2026, 3, 28, 9, 0, 0, 0, berlin),
+			want: []time.Time{
+				time.Date(2026, 3, 28, 8, 0, 0, 0, time.UTC),
+				time.Date(2026, 3, 29, 7, 0, 0, 0, time.UTC),
+				time.Date(2026, 3, 30, 7, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run("Next: "+tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			require.NoError(t, err)
+			s := NewSchedule(rule, tt.start, tt.start.Location(), tt.except)
+
+			var got []time.Time
+			next, ok := s.First()
+			for ok {
+				got = append(got, next.UTC())
+				next, ok = s.Next(next)
+			}
+			require.Equal(t, len(tt.want), len(got), got)
+			for i := range tt.want {
+				require.True(t, tt.want[i].Equal(got[i]), "occurrence %d: want %s, got %s", i, tt.want[i], got[i])
+			}
+		})
+	}
+
+	// Test case 10
+	t.Run("Next: exception dates in the timezone", func(t *testing.T) {
+		rule, err := Parse("FREQ=DAILY")
+		require.NoError(t, err)
+		// 00:30 in Berlin is still the previous day in UTC
+		s := NewSchedule(rule, time.Date(2026, 6, 1, 22, 30, 0, 0, time.UTC), berlin, []string{"2026-06-02"})
+
+		next, ok := s.First()
+		require.True(t, ok)
+		require.True(t, time.Date(2026, 6, 2, 22, 30, 0, 0, time.UTC).Equal(next))
+	})
+
+	// Test case 11
+	t.Run("Next: rule never matching", func(t *testing.T) {
+		rule, err := Parse("FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30")
+		require.NoError(t, err)
+		_, ok := NewSchedule(rule, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), time.UTC, nil).First()
+		require.False(t, ok)
+	})
+}
+
+func TestSchedule_Latest(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		rule   string
+		except []string
+		at     time.Time
+		want   time.Time
+		ok     bool
+	}{
+		{name: "before the start", rule: "FREQ=DAILY", at: start.Add(-time.Minute), ok: false},
+		{name: "on an occurrence", rule: "FREQ=DAILY", at: start.AddDate(0, 0, 3), want: start.AddDate(0, 0, 3), ok: true},
+		{name: "between occurrences", rule: "FREQ=DAILY", at: start.AddDate(0, 0, 3).Add(time.Hour), want: start.AddDate(0, 0, 3), ok: true},
+		{name: "skips the exception dates", rule: "FREQ=DAILY", except: []string{"2026-03-05"}, at: start.AddDate(0, 0, 3).Add(time.Hour), want: start.AddDate(0, 0, 2), ok: true},
+		{name: "stops at COUNT", rule: "FREQ=DAILY;COUNT=2", at: start.AddDate(0, 1, 0), want: start.AddDate(0, 0, 1), ok: true},
+		{name: "stops at UNTIL", rule: "FREQ=WEEKLY;UNTIL=20260320", at: start.AddDate(0, 1, 0), want: start.AddDate(0, 0, 14), ok: true},
+	}
+
+	for _, tt := range tests {
+		t.Run("Latest: "+tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			require.NoError(t, err)
+
+			got, ok := NewSchedule(rule, start, time.UTC, tt.except).Latest(tt.at)
+			require.Equal(t, tt.ok, ok)
+			require.True(t, tt.want.Equal(got), "want %s, got %s", tt.want, got)
+		})
+	}
+}
@@ -1,28 +1,300 @@
 package router
 
 import (
+	"task-manager/internal/auth"
 	"task-manager/internal/handler"
 	"task-manager/internal/middleware"
+	"task-manager/internal/oidc"
 	"task-manager/internal/service"
 
 	"github.com/gin-gonic/gin"
 )
 
-func SetupRouter(router *gin.Engine, TaskService service.ITaskService) {
+// Services holds the services the routes are backed by
+type Services struct {
+	TaskService         service.ITaskService
+	UserService         service.IUserService
+	TokenService        service.ITokenService
+	WorkspaceService    service.IWorkspaceService
+	MFAService          service.IMFAService
+	LabelService        service.ILabelService
+	ProjectService      service.IProjectService
+	RecurrenceService   service.IRecurrenceService
+	ReminderService     service.IReminderService
+	NotificationService service.INotificationService
+	TimeService         service.ITimeService
+	CommentService      service.ICommentService
+
+	// OIDCProvider is nil when login through an identity provider is
+	// not configured
+	OIDCProvider *oidc.Provider
+}
+
+func SetupRouter(router *gin.Engine, services Services) {
 	healthzHandler := handler.NewHealthzHandler()
-	taskHandler := handler.NewTaskHandler(TaskService)
+	jwksHandler := handler.NewJWKSHandler()
+	authHandler := handler.NewAuthHandler(services.UserService, services.TokenService)
+	userHandler := handler.NewUserHandler(services.UserService)
+	tokenHandler := handler.NewTokenHandler(services.TokenService)
+	mfaHandler := handler.NewMFAHandler(services.MFAService, services.TokenService)
+	taskHandler := handler.NewTaskHandler(services.TaskService)
+	labelHandler := handler.NewLabelHandler(services.LabelService)
+	projectHandler := handler.NewProjectHandler(services.ProjectService)
+	recurrenceHandler := handler.NewRecurrenceHandler(services.RecurrenceService)
+	reminderHandler := handler.NewReminderHandler(services.ReminderService)
+	notificationHandler := handler.NewNotificationHandler(services.NotificationService)
+	timeHandler := handler.NewTimeHandler(services.TimeService)
+	commentHandler := handler.NewCommentHandler(services.CommentService)
+	workspaceHandler := handler.NewWorkspaceHandler(services.WorkspaceService)
+	authMiddleware := middleware.AuthMiddleware(services.TokenService)
 
 	// Healthz endpoint
 	activity := router.Group("/activity")
 	activity.GET("/healthz", healthzHandler.GetHealthz) // Get Health status
 
-	// Task endpoints
+	// Well-known endpoints
+	router.GET("/.well-known/jwks.json", jwksHandler.GetJWKS) // Get Signing Keys
+
+	// Auth endpoints
+	authGroup := router.Group("/auth")
+	authGroup.POST("/register", authHandler.Register)             // Register User
+	authGroup.POST("/login", authHandler.Login)                   // Login User
+	authGroup.POST("/login/mfa", mfaHandler.LoginMFA)             // Login Second Step
+	authGroup.POST("/refresh", authHandler.Refresh)               // Refresh Tokens
+	authGroup.POST("/logout", authMiddleware, authHandler.Logout) // Logout User
+
+	// OpenID Connect endpoints
+	if services.OIDCProvider != nil {
+		oidcHandler := handler.NewOIDCHandler(services.OIDCProvider, services.UserService, services.TokenService)
+		authGroup.GET("/oidc/login", oidcHandler.Login)       // Login with the Identity Provider
+		authGroup.GET("/oidc/callback", oidcHandler.Callback) // Identity Provider Callback
+	}
+
+	// Two-factor authentication endpoints
+	mfa := authGroup.Group("/mfa")
+	mfa.Use(authMiddleware)
+	mfa.POST("/totp", mfaHandler.EnrollTOTP)                        // Enroll TOTP
+	mfa.POST("/totp/confirm", mfaHandler.ConfirmTOTP)               // Confirm TOTP
+	mfa.POST("/totp/disable", mfaHandler.DisableTOTP)               // Disable TOTP
+	mfa.POST("/recovery-codes", mfaHandler.RegenerateRecoveryCodes) // Regenerate Recovery Codes
+
+	// Personal access token endpoints
+	tokens := authGroup.Group("/tokens")
+	tokens.Use(authMiddleware)
+	tokens.GET("/", tokenHandler.GetPersonalAccessTokens)              // Get My Access Tokens
+	tokens.POST("/", tokenHandler.CreatePersonalAccessToken)           // Create Access Token
+	tokens.DELETE("/:tokenId", tokenHandler.RevokePersonalAccessToken) // Revoke Access Token
+
+	// User endpoints
+	users := router.Group("/users")
+	users.Use(authMiddleware, middleware.RequirePermission(auth.PermUsersManage))
+	users.PUT("/:userId/role", userHandler.UpdateUserRole) // Update User Role
+
+	// Task endpoints, personal tasks
 	tasks := router.Group("/tasks")
-	tasks.GET("/", taskHandler.GetTasks) // Get All Tasks
+	tasks.Use(authMiddleware) // Auth Middleware added
+	setupTaskRoutes(tasks, taskHandler)
+	setupReminderRoutes(tasks, reminderHandler)
+	setupTimeRoutes(tasks, timeHandler)
+	setupCommentRoutes(tasks, commentHandler)
+
+	// Time tracking endpoints, the timer of the caller and the timesheet
+	// of the personal tasks
+	router.GET("/timer", authMiddleware, timeHandler.GetRunningTimer)                                                    // Get My Running Timer
+	router.POST("/timer/stop", authMiddleware, timeHandler.StopRunningTimer)                                             // Stop My Running Timer
+	router.GET("/timesheet", authMiddleware, middleware.RequirePermission(auth.PermTasksRead), timeHandler.GetTimesheet) // Get Timesheet
+
+	// Label endpoints, personal labels
+	labels := router.Group("/labels")
+	labels.Use(authMiddleware)
+	setupLabelRoutes(labels, labelHandler)
+
+	// Project endpoints, personal projects
+	projects := router.Group("/projects")
+	projects.Use(authMiddleware)
+	setupProjectRoutes(projects, projectHandler)
+
+	// Recurring task endpoints, personal recurring tasks
+	recurrences := router.Group("/recurrences")
+	recurrences.Use(authMiddleware)
+	setupRecurrenceRoutes(recurrences, recurrenceHandler)
+
+	// Notification endpoints, the inbox of the caller
+	notifications := router.Group("/notifications")
+	notifications.Use(authMiddleware)
+	notifications.GET("/", notificationHandler.GetNotifications)                          // Get My Notifications
+	notifications.POST("/:notificationId/read", notificationHandler.MarkNotificationRead) // Mark Notification Read
+
+	// Workspace endpoints, viewers cannot create workspaces as they would
+	// own them
+	canCreateWorkspace := middleware.RequirePermission(auth.PermTasksWrite)
+	workspaces := router.Group("/workspaces")
+	workspaces.Use(authMiddleware)
+	workspaces.GET("/", workspaceHandler.GetWorkspaces)                        // Get My Workspaces
+	workspaces.POST("/", canCreateWorkspace, workspaceHandler.CreateWorkspace) // Create Workspace
+	workspaces.GET("/:wsId", workspaceHandler.GetWorkspaceByID)                // Get Workspace by ID
+	workspaces.GET("/:wsId/members", workspaceHandler.GetMembers)              // Get Workspace Members
+	workspaces.POST("/:wsId/members", workspaceHandler.AddMember)              // Add Workspace Member
+	workspaces.PUT("/:wsId/members/:userId", workspaceHandler.UpdateMember)    // Update Member Role
+	workspaces.DELETE("/:wsId/members/:userId", workspaceHandler.RemoveMember) // Remove Workspace Member
+	workspaces.POST("/:wsId/invitations", workspaceHandler.CreateInvitation)   // Invite to Workspace
+
+	// Invitation endpoints
+	router.POST("/invitations/accept", authMiddleware, workspaceHandler.AcceptInvitation) // Accept Invitation
+
+	// Task endpoints, scoped to the workspace
+	workspaceTasks := workspaces.Group("/:wsId/tasks")
+	workspaceTasks.Use(middleware.RequireWorkspaceMember(services.WorkspaceService))
+	setupTaskRoutes(workspaceTasks, taskHandler)
+	setupReminderRoutes(workspaceTasks, reminderHandler)
+	setupTimeRoutes(workspaceTasks, timeHandler)
+	setupCommentRoutes(workspaceTasks, commentHandler)
+
+	// Timesheet endpoint, scoped to the workspace
+	workspaceTimesheet := workspaces.Group("/:wsId/timesheet")
+	workspaceTimesheet.Use(middleware.RequireWorkspaceMember(services.WorkspaceService))
+	workspaceTimesheet.GET("/", middleware.RequirePermission(auth.PermTasksRead), timeHandler.GetTimesheet) // Get Workspace Timesheet
+
+	// Label endpoints, scoped to the workspace
+	workspaceLabels := workspaces.Group("/:wsId/labels")
+	workspaceLabels.Use(middleware.RequireWorkspaceMember(services.WorkspaceService))
+	setupLabelRoutes(workspaceLabels, labelHandler)
+
+	// Project endpoints, scoped to the workspace
+	workspaceProjects := workspaces.Group("/:wsId/projects")
+	workspaceProjects.Use(middleware.RequireWorkspaceMember(services.WorkspaceService))
+	setupProjectRoutes(workspaceProjects, projectHandler)
+
+	// Recurring task endpoints, scoped to the workspace
+	workspaceRecurrences := workspaces.Group("/:wsId/recurrences")
+	workspaceRecurrences.Use(middleware.RequireWorkspaceMember(services.WorkspaceService))
+	setupRecurrenceRoutes(workspaceRecurrences, recurrenceHandler)
+}
+
+// setupTaskRoutes registers the task endpoints on the group. The same
+// routes serve the personal tasks and the tasks of a workspace, the tenant
+// is resolved by the middlewares of the group.
+func setupTaskRoutes(tasks *gin.RouterGroup, taskHandler *handler.TaskHandler) {
+	// Permission checks
+	canRead := middleware.RequirePermission(auth.PermTasksRead)
+	canWrite := middleware.RequirePermission(auth.PermTasksWrite)
+	canDelete := middleware.RequirePermission(auth.PermTasksDelete)
+
+	tasks.GET("/", canRead, taskHandler.GetTasks)                      // Get All Tasks
+	tasks.GET("/assigned", canRead, taskHandler.GetAssignedTasks)      // Get Tasks Assigned to Me
+	tasks.GET("/trash", canRead, taskHandler.GetTrash)                 // Get Deleted Tasks
+	tasks.POST("/", canWrite, taskHandler.CreateTask)                  // Create Task
+	tasks.GET("/:taskId", canRead, taskHandler.GetTaskByID)            // Get Task by ID
+	tasks.PUT("/:taskId", canWrite, taskHandler.UpdateTaskByID)        // Update Task by ID
+	tasks.PATCH("/:taskId", canWrite, taskHandler.PatchTaskByID)       // Patch Task by ID
+	tasks.DELETE("/:taskId", canDelete, taskHandler.DeleteTaskByID)    // Delete Task by ID
+	tasks.GET("/:taskId/subtasks", canRead, taskHandler.GetSubtasks)   // Get Subtasks
+	tasks.POST("/:taskId/restore", canDelete, taskHandler.RestoreTask) // Restore Deleted Task
+
+	tasks.GET("/:taskId/history", canRead, taskHandler.GetTaskHistory)             // Get Task History
+	tasks.GET("/:taskId/revisions/:rev", canRead, taskHandler.GetTaskRevision)     // Get Task Revision
+	tasks.POST("/:taskId/revisions/:rev/revert", canWrite, taskHandler.RevertTask) // Revert Task to Revision
+
+	tasks.POST("/:taskId/start", canWrite, taskHandler.StartTask)       // Start Task
+	tasks.POST("/:taskId/complete", canWrite, taskHandler.CompleteTask) // Complete Task
+	tasks.POST("/:taskId/reopen", canWrite, taskHandler.ReopenTask)     // Reopen Task
+
+	tasks.GET("/:taskId/grants", canRead, taskHandler.GetTaskGrants)               // Get Task Grants
+	tasks.POST("/:taskId/grants", canWrite, taskHandler.GrantTask)                 // Grant Task Access
+	tasks.DELETE("/:taskId/grants/:userId", canWrite, taskHandler.RevokeTaskGrant) // Revoke Task Access
+
+	tasks.POST("/:taskId/dependencies", canWrite, taskHandler.AddDependency)                 // Add Task Dependency
+	tasks.DELETE("/:taskId/dependencies/:blockerId", canWrite, taskHandler.RemoveDependency) // Remove Task Dependency
+
+	tasks.GET("/:taskId/assignees", canRead, taskHandler.GetTaskAssignees)         // Get Task Assignees
+	tasks.GET("/:taskId/assignees/log", canRead, taskHandler.GetTaskAssignmentLog) // Get Task Assignment Log
+	tasks.POST("/:taskId/assignees", canWrite, taskHandler.AssignTask)             // Assign Task
+	tasks.DELETE("/:taskId/assignees", canWrite, taskHandler.UnassignTask)         // Unassign Task
+
+	tasks.POST("/:taskId/labels", canWrite, taskHandler.AttachLabels)           // Attach Labels
+	tasks.DELETE("/:taskId/labels/:labelId", canWrite, taskHandler.DetachLabel) // Detach Label
+}
+
+// setupReminderRoutes registers the reminder endpoints of the tasks on the
+// group of the task endpoints
+func setupReminderRoutes(tasks *gin.RouterGroup, reminderHandler *handler.ReminderHandler) {
+	// Permission checks, reminders only need to see the task
+	canRead := middleware.RequirePermission(auth.PermTasksRead)
+
+	tasks.GET("/:taskId/reminders", canRead, reminderHandler.GetReminders)                  // Get My Task Reminders
+	tasks.POST("/:taskId/reminders", canRead, reminderHandler.CreateReminder)               // Create Task Reminder
+	tasks.DELETE("/:taskId/reminders/:reminderId", canRead, reminderHandler.DeleteReminder) // Delete Task Reminder
+}
+
+// setupTimeRoutes registers the timer and work log endpoints of the tasks
+// on the group of the task endpoints
+func setupTimeRoutes(tasks *gin.RouterGroup, timeHandler *handler.TimeHandler) {
+	// Permission checks, time is logged by the users working on the task
+	canRead := middleware.RequirePermission(auth.PermTasksRead)
+	canWrite := middleware.RequirePermission(auth.PermTasksWrite)
+
+	tasks.POST("/:taskId/timer/start", canWrite, timeHandler.StartTimer)              // Start Timer
+	tasks.POST("/:taskId/timer/stop", canWrite, timeHandler.StopTimer)                // Stop Timer
+	tasks.GET("/:taskId/worklogs", canRead, timeHandler.GetWorkLogs)                  // Get Work Logs
+	tasks.POST("/:taskId/worklogs", canWrite, timeHandler.CreateWorkLog)              // Create Work Log
+	tasks.DELETE("/:taskId/worklogs/:workLogId", canWrite, timeHandler.DeleteWorkLog) // Delete Work Log
+}
+
+// setupCommentRoutes registers the comment endpoints of the tasks on the
+// group of the task endpoints
+func setupCommentRoutes(tasks *gin.RouterGroup, commentHandler *handler.CommentHandler) {
+	// Permission checks, the service also checks the author of the comment
+	canRead := middleware.RequirePermission(auth.PermTasksRead)
+	canWrite := middleware.RequirePermission(auth.PermTasksWrite)
+
+	tasks.GET("/:taskId/comments", canRead, commentHandler.GetComments)                          // Get Task Comments
+	tasks.POST("/:taskId/comments", canWrite, commentHandler.CreateComment)                      // Create Comment
+	tasks.PUT("/:taskId/comments/:commentId", canWrite, commentHandler.UpdateComment)            // Update Comment
+	tasks.DELETE("/:taskId/comments/:commentId", canWrite, commentHandler.DeleteComment)         // Delete Comment
+	tasks.GET("/:taskId/comments/:commentId/history", canRead, commentHandler.GetCommentHistory) // Get Comment History
+}
+
+// setupLabelRoutes registers the label endpoints on the group, for the
+// personal labels or the labels of a workspace like the task endpoints
+func setupLabelRoutes(labels *gin.RouterGroup, labelHandler *handler.LabelHandler) {
+	// Permission checks, labels are managed along with the tasks
+	canRead := middleware.RequirePermission(auth.PermTasksRead)
+	canWrite := middleware.RequirePermission(auth.PermTasksWrite)
+
+	labels.GET("/", canRead, labelHandler.GetLabels)               // Get All Labels
+	labels.POST("/", canWrite, labelHandler.CreateLabel)           // Create Label
+	labels.PUT("/:labelId", canWrite, labelHandler.UpdateLabel)    // Update Label by ID
+	labels.DELETE("/:labelId", canWrite, labelHandler.DeleteLabel) // Delete Label by ID
+}
+
+// setupProjectRoutes registers the project endpoints on the group, for the
+// personal projects or the projects of a workspace like the task endpoints
+func setupProjectRoutes(projects *gin.RouterGroup, projectHandler *handler.ProjectHandler) {
+	// Permission checks, projects are managed along with the tasks
+	canRead := middleware.RequirePermission(auth.PermTasksRead)
+	canWrite := middleware.RequirePermission(auth.PermTasksWrite)
+	canDelete := middleware.RequirePermission(auth.PermTasksDelete)
+
+	projects.GET("/", canRead, projectHandler.GetProjects)                        // Get All Projects
+	projects.POST("/", canWrite, projectHandler.CreateProject)                    // Create Project
+	projects.GET("/:projectId", canRead, projectHandler.GetProjectByID)           // Get Project by ID
+	projects.PUT("/:projectId/workflow", canWrite, projectHandler.UpdateWorkflow) // Update Project Workflow
+	projects.DELETE("/:projectId", canDelete, projectHandler.DeleteProject)       // Delete Project by ID
+}
+
+// setupRecurrenceRoutes registers the recurring task endpoints on the
+// group, for the personal recurring tasks or those of a workspace like the
+// task endpoints
+func setupRecurrenceRoutes(recurrences *gin.RouterGroup, recurrenceHandler *handler.RecurrenceHandler) {
+	// Permission checks, recurring tasks are managed along with the tasks
+	canRead := middleware.RequirePermission(auth.PermTasksRead)
+	canWrite := middleware.RequirePermission(auth.PermTasksWrite)
+	canDelete := middleware.RequirePermission(auth.PermTasksDelete)
 
-	tasks.Use(middleware.AuthMiddleware)                 // Auth Middleware added
-	tasks.POST("/", taskHandler.CreateTask)              // Create Task
-	tasks.GET("/:taskId", taskHandler.GetTaskByID)       // Get Task by ID
-	tasks.PUT("/:taskId", taskHandler.UpdateTaskByID)    // Update Task by ID
-	tasks.DELETE("/:taskId", taskHandler.DeleteTaskByID) // Delete Task by ID
+	recurrences.GET("/", canRead, recurrenceHandler.GetRecurrences)                     // Get All Recurring Tasks
+	recurrences.POST("/", canWrite, recurrenceHandler.CreateRecurrence)                 // Create Recurring Task
+	recurrences.GET("/:recurrenceId", canRead, recurrenceHandler.GetRecurrenceByID)     // Get Recurring Task by ID
+	recurrences.PUT("/:recurrenceId", canWrite, recurrenceHandler.UpdateRecurrence)     // Update Recurring Task by ID
+	recurrences.POST("/:recurrenceId/skip", canWrite, recurrenceHandler.SkipOccurrence) // Skip Occurrence
+	recurrences.DELETE("/:recurrenceId", canDelete, recurrenceHandler.DeleteRecurrence) // Delete Recurring Task by ID
 }
@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"task-manager/internal/auth"
+	"task-manager/internal/mention"
+	"task-manager/internal/model"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+)
+
+var (
+	ErrCommentNotFound  = errors.New("comment not found")
+	ErrNestedReply      = errors.New("replies can only answer a top level comment")
+	ErrNotCommentAuthor = errors.New("only the author can change the comment")
+)
+
+type (
+	ICommentService interface {
+		GetComments(context.Context, uuid.UUID) ([]model.Comment, error)
+		CreateComment(context.Context, uuid.UUID, *model.Comment) error
+		UpdateComment(context.Context, uuid.UUID, uuid.UUID, string) (*model.Comment, error)
+		DeleteComment(context.Context, uuid.UUID, uuid.UUID) error
+		GetCommentHistory(context.Context, uuid.UUID, uuid.UUID) ([]model.CommentRevision, error)
+	}
+
+	CommentService struct {
+		DB *gorm.DB
+
+		tasks *TaskService
+	}
+)
+
+func NewCommentService(db *gorm.DB) ICommentService {
+	return &CommentService{DB: db, tasks: &TaskService{DB: db}}
+}
+
+// GetComments lists the top level comments of the visible task with their
+// replies, the oldest first
+func (s *CommentService) GetComments(ctx context.Context, taskID uuid.UUID) ([]model.Comment, error) {
+	if _, _, err := s.tasks.visibleTask(ctx, taskID); err != nil {
+		return nil, err
+	}
+
+	var all []model.Comment
+	if err := s.DB.Where("task_id = ?", taskID).Order("created_at, id").Find(&all).Error; err != nil {
+		return nil, err
+	}
+
+	comments := []model.Comment{}
+	threads := make(map[uuid.UUID]int)
+	for _, c := range all {
+		if c.ParentID == nil {
+			threads[c.ID] = len(comments)
+			comments = append(comments, c)
+		}
+	}
+	for _, c := range all {
+		if c.ParentID == nil {
+			continue
+		}
+		if i, ok := threads[*c.ParentID]; ok {
+			comments[i].Replies = append(comments[i].Replies, c)
+		}
+	}
+	return comments, nil
+}
+
+// CreateComment adds the comment of the caller to the visible task, as a
+// reply when it has a parent. The users it mentions who can see the task
+// are notified in their inbox.
+func (s *CommentService) CreateComment(ctx context.Context, taskID uuid.UUID, comment *model.Comment) error {
+	principal, task, err := s.tasks.visibleTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	comment.ID, _ = uuid.NewV7()
+	comment.TaskID = taskID
+	comment.AuthorID = principal.UserID()
+	comment.EditedAt = nil
+	comment.Replies = nil
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		if comment.ParentID != nil {
+			parent, err := getComment(tx, taskID, *comment.ParentID)
+			if err != nil {
+				return err
+			}
+			if parent.ParentID != nil {
+				return ErrNestedReply
+			}
+		}
+		if err := tx.Create(comment).Error; err != nil {
+			return err
+		}
+		return notifyMentions(tx, task, comment)
+	})
+}
+
+// UpdateComment replaces the body of a comment of the caller, keeping the
+// previous body as a revision. Only the users newly mentioned are notified.
+func (s *CommentService) UpdateComment(ctx context.Context, taskID, id uuid.UUID, body string) (*model.Comment, error) {
+	principal, task, err := s.tasks.visibleTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	var comment *model.Comment
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if comment, err = getComment(tx.Set("gorm:query_option", "FOR UPDATE"), taskID, id); err != nil {
+			return err
+		}
+		if comment.AuthorID != principal.UserID() {
+			return ErrNotCommentAuthor
+		}
+		if comment.Body == body {
+			return nil
+		}
+
+		revision := model.CommentRevision{CommentID: comment.ID, Body: comment.Body, EditorID: principal.UserID()}
+		revision.ID, _ = uuid.NewV7()
+		if err := tx.Create(&revision).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		comment.Body = body
+		comment.EditedAt = &now
+		comment.UpdatedAt = now
+		err = tx.Model(comment).UpdateColumns(map[string]interface{}{
+			"body":       body,
+			"edited_at":  now,
+			"updated_at": now,
+		}).Error
+		if err != nil {
+			return err
+		}
+		return notifyMentions(tx, task, comment)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// DeleteComment deletes a comment along with its replies, its revisions
+// and the notifications of its mentions. Besides the author, the admins
+// of the workspace of the task, or the admins for the personal tasks, can
+// delete it.
+func (s *CommentService) DeleteComment(ctx context.Context, taskID, id uuid.UUID) error {
+	principal, _, err := s.tasks.visibleTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		comment, err := getComment(tx, taskID, id)
+		if err != nil {
+			return err
+		}
+		if comment.AuthorID != principal.UserID() && !canModerate(ctx, principal) {
+			return ErrNotCommentAuthor
+		}
+
+		var ids []uuid.UUID
+		if err := tx.Model(&model.Comment{}).Where("id = ? OR parent_id = ?", id, id).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&model.CommentRevision{}, "comment_id IN (?)", ids).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&model.CommentMention{}, "comment_id IN (?)", ids).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&model.Notification{}, "comment_id IN (?)", ids).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.Comment{}, "id IN (?)", ids).Error
+	})
+}
+
+// GetCommentHistory lists the previous bodies of a comment of the visible
+// task, the latest first
+func (s *CommentService) GetCommentHistory(ctx context.Context, taskID, id uuid.UUID) ([]model.CommentRevision, error) {
+	if _, _, err := s.tasks.visibleTask(ctx, taskID); err != nil {
+		return nil, err
+	}
+	if _, err := getComment(s.DB, taskID, id); err != nil {
+		return nil, err
+	}
+
+	revisions := []model.CommentRevision{}
+	err := s.DB.Where("comment_id = ?", id).Order("created_at DESC, id DESC").Find(&revisions).Error
+	return revisions, err
+}
+
+/*
+	Supporting functions
+*/
+
+func getComment(db *gorm.DB, taskID, id uuid.UUID) (*model.Comment, error) {
+	var comment model.Comment
+	if err := db.First(&comment, "id = ? AND task_id = ?", id, taskID).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// canModerate reports whether the principal administers the tasks of ctx,
+// as an admin of its workspace or an admin for the personal tasks
+func canModerate(ctx context.Context, principal *auth.Principal) bool {
+	if tenant, ok := TenantFromContext(ctx); ok {
+		return tenant.Role == model.WorkspaceRoleOwner || tenant.Role == model.WorkspaceRoleAdmin
+	}
+	return principal.HasRole(auth.RoleAdmin)
+}
+
+// notifyMentions notifies the users the comment mentions who can see the
+// task, once per comment, leaving out its author. The users who cannot
+// see the task are ignored, so mentions do not leak it.
+func notifyMentions(tx *gorm.DB, task *model.Task, comment *model.Comment) error {
+	usernames := mention.Parse(comment.Body)
+	if len(usernames) == 0 {
+		return nil
+	}
+
+	db := tx.Where("LOWER(users.username) IN (?) AND users.id <> ?", usernames, comment.AuthorID).
+		Where("users.id NOT IN (SELECT user_id FROM comment_mentions WHERE comment_id = ?)", comment.ID)
+	if task.WorkspaceID != nil {
+		db = db.Where("users.id IN (SELECT user_id FROM workspace_members WHERE workspace_id = ?)", *task.WorkspaceID)
+	} else {
+		db = db.Where("users.id = ? OR users.id IN (SELECT user_id FROM task_grants WHERE task_id = ?)", task.OwnerID, task.ID)
+	}
+	var mentioned []model.User
+	if err := db.Select("users.id").Find(&mentioned).Error; err != nil {
+		return err
+	}
+	if len(mentioned) == 0 {
+		return nil
+	}
+
+	var author model.User
+	if err := tx.Select("username").First(&author, "id = ?", comment.AuthorID).Error; err != nil {
+		return err
+	}
+	for _, user := range mentioned {
+		if err := tx.Create(&model.CommentMention{CommentID: comment.ID, UserID: user.ID}).Error; err != nil {
+			return err
+		}
+		notification := model.Notification{
+			UserID:    user.ID,
+			TaskID:    task.ID,
+			CommentID: &comment.ID,
+			Subject:   author.Username + " mentioned you on " + task.Title,
+			Body:      comment.Body,
+		}
+		notification.ID, _ = uuid.NewV7()
+		if err := tx.Create(&notification).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
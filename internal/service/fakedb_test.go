@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jinzhu/gorm"
+)
+
+// fakeDB is a database/sql driver recording the statements gorm sends, for
+// the services whose behaviour lies in the statements they run. Queries
+// answer with the rows of the first stub they contain, or no rows, and
+// inserts succeed.
+type fakeDB struct {
+	mu         sync.Mutex
+	statements []string
+	stubs      []fakeStub
+}
+
+type fakeStub struct {
+	match   string
+	columns []string
+	rows    [][]driver.Value
+	err     error
+
+	// affected is the number of rows the statements report, one by default
+	affected *int64
+
+	// once stubs only answer the first statement they match
+	once bool
+}
+
+// newFakeDB returns a gorm handle on a new fake database, speaking the
+// postgres dialect
+func newFakeDB(t *testing.T) (*gorm.DB, *fakeDB) {
+	t.Helper()
+	f := &fakeDB{}
+	db, err := gorm.Open("postgres", sql.OpenDB(f))
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.LogMode(false)
+	t.Cleanup(func() { db.Close() })
+	return db, f
+}
+
+// stub answers the queries containing match with the rows
+func (f *fakeDB) stub(match string, columns []string, rows ...[]driver.Value) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stubs = append(f.stubs, fakeStub{match: match, columns: columns, rows: rows})
+}
+
+// stubOnce answers the next query containing match with the rows
+func (f *fakeDB) stubOnce(match string, columns []string, rows ...[]driver.Value) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stubs = append(f.stubs, fakeStub{match: match, columns: columns, rows: rows, once: true})
+}
+
+// fail makes the statements containing match fail with err
+func (f *fakeDB) fail(match string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stubs = append(f.stubs, fakeStub{match: match, err: err})
+}
+
+// affect makes the statements containing match report n affected rows
+func (f *fakeDB) affect(match string, n int64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stubs = append(f.stubs, fakeStub{match: match, affected: &n})
+}
+
+// executed returns the statements run so far, with BEGIN, COMMIT and
+// ROLLBACK for the transactions
+func (f *fakeDB) executed() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.statements...)
+}
+
+// indexOf returns the index of the first statement containing s, or -1
+func (f *fakeDB) indexOf(s string) int {
+	for i, stmt := range f.executed() {
+		if strings.Contains(stmt, s) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (f *fakeDB) record(query string) *fakeStub {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.statements = append(f.statements, query)
+	for i, stub := range f.stubs {
+		if strings.Contains(query, stub.match) {
+			if stub.once {
+				f.stubs = append(f.stubs[:i:i], f.stubs[i+1:]...)
+			}
+			return &stub
+		}
+	}
+	return nil
+}
+
+func (f *fakeDB) Connect(context.Context) (driver.Conn, error) { return &fakeConn{f}, nil }
+func (f *fakeDB) Driver() driver.Driver                        { return nil }
+
+type fakeConn struct{ db *fakeDB }
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("fakedb: prepared statements are not supported")
+}
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.db.record("BEGIN")
+	return &fakeTx{c.db}, nil
+}
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	stub := c.db.record(query)
+	if stub != nil && stub.err != nil {
+		return nil, stub.err
+	}
+	if stub != nil && stub.affected != nil {
+		return driver.RowsAffected(*stub.affected), nil
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	stub := c.db.record(query)
+	if stub == nil && strings.HasPrefix(query, "INSERT") && len(args) > 0 {
+		// gorm reads the primary key back, the first column it inserts
+		return &fakeRows{columns: []string{"id"}, rows: [][]driver.Value{{args[0].Value}}}, nil
+	}
+	if stub == nil {
+		return &fakeRows{}, nil
+	}
+	if stub.err != nil {
+		return nil, stub.err
+	}
+	return &fakeRows{columns: stub.columns, rows: stub.rows}, nil
+}
+
+type fakeTx struct{ db *fakeDB }
+
+func (tx *fakeTx) Commit() error {
+	tx.db.record("COMMIT")
+	return nil
+}
+
+func (tx *fakeTx) Rollback() error {
+	tx.db.record("ROLLBACK")
+	return nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
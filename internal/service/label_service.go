@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"task-manager/internal/auth"
+	"task-manager/internal/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+)
+
+var (
+	ErrLabelExists = errors.New("a label with this name already exists")
+)
+
+type (
+	ILabelService interface {
+		GetLabels(context.Context) ([]model.Label, error)
+		CreateLabel(context.Context, *model.Label) error
+		UpdateLabel(context.Context, uuid.UUID, *model.Label) error
+		DeleteLabel(context.Context, uuid.UUID) error
+	}
+
+	LabelService struct {
+		DB *gorm.DB
+	}
+)
+
+func NewLabelService(db *gorm.DB) ILabelService {
+	return &LabelService{DB: db}
+}
+
+func (s *LabelService) GetLabels(ctx context.Context) ([]model.Label, error) {
+	db, err := s.visible(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var labels []model.Label
+	err = db.Order("name").Find(&labels).Error
+	return labels, err
+}
+
+// CreateLabel stores the label in the workspace of ctx, or among the
+// personal labels of the caller when ctx has no tenant
+func (s *LabelService) CreateLabel(ctx context.Context, label *model.Label) error {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	label.ID, _ = uuid.NewV7()
+	label.OwnerID = principal.UserID()
+	label.WorkspaceID = nil
+	if tenant, ok := TenantFromContext(ctx); ok {
+		label.WorkspaceID = &tenant.WorkspaceID
+	}
+
+	if err := s.ensureUniqueName(ctx, uuid.Nil, label.Name); err != nil {
+		return err
+	}
+	return s.DB.Create(label).Error
+}
+
+func (s *LabelService) UpdateLabel(ctx context.Context, id uuid.UUID, label *model.Label) error {
+	db, err := s.visible(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.ensureUniqueName(ctx, id, label.Name); err != nil {
+		return err
+	}
+
+	res := db.Model(&model.Label{}).
+		Where("labels.id = ?", id).
+		Updates(map[string]interface{}{"name": label.Name, "color": label.Color})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteLabel deletes the label and detaches it from its tasks
+func (s *LabelService) DeleteLabel(ctx context.Context, id uuid.UUID) error {
+	db, err := s.visible(ctx)
+	if err != nil {
+		return err
+	}
+
+	var label model.Label
+	if err := db.First(&label, "labels.id = ?", id).Error; err != nil {
+		return err
+	}
+
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&model.TaskLabel{}, "label_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&label).Error
+	})
+}
+
+/*
+	Supporting functions
+*/
+
+// visible scopes the labels table to the tenant of ctx, like the tasks.
+// Outside of a workspace only the labels of the caller are visible.
+func (s *LabelService) visible(ctx context.Context) (*gorm.DB, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+	if tenant, ok := TenantFromContext(ctx); ok {
+		return s.DB.Where("labels.workspace_id = ?", tenant.WorkspaceID), nil
+	}
+	return s.DB.Where("labels.workspace_id IS NULL AND labels.owner_id = ?", principal.UserID()), nil
+}
+
+// ensureUniqueName checks that no other visible label has the name,
+// regardless of the case
+func (s *LabelService) ensureUniqueName(ctx context.Context, id uuid.UUID, name string) error {
+	db, err := s.visible(ctx)
+	if err != nil {
+		return err
+	}
+
+	var count int
+	err = db.Model(&model.Label{}).
+		Where("LOWER(labels.name) = ? AND labels.id <> ?", strings.ToLower(name), id).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrLabelExists
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"task-manager/internal/auth"
+	"task-manager/internal/model"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+)
+
+// RecoveryCodeCount is the number of recovery codes handed out at once
+const RecoveryCodeCount = 10
+
+var (
+	ErrTOTPAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrTOTPNotEnrolled    = errors.New("two-factor authentication is not enrolled")
+	ErrInvalidMFACode     = errors.New("invalid authentication code")
+)
+
+type (
+	IMFAService interface {
+		EnrollTOTP(context.Context) (string, string, error)
+		ConfirmTOTP(context.Context, string) ([]string, error)
+		DisableTOTP(context.Context, string) error
+		RegenerateRecoveryCodes(context.Context, string) ([]string, error)
+		VerifySecondFactor(context.Context, uuid.UUID, string, string) (*model.User, error)
+	}
+
+	MFAService struct {
+		DB *gorm.DB
+	}
+)
+
+func NewMFAService(db *gorm.DB) IMFAService {
+	return &MFAService{DB: db}
+}
+
+// EnrollTOTP generates a new secret for the caller and returns it with its
+// otpauth URI. The secret is only used for logins once ConfirmTOTP checked
+// a first code.
+func (s *MFAService) EnrollTOTP(ctx context.Context) (string, string, error) {
+	user, err := s.caller(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	if user.TOTPEnabled {
+		return "", "", ErrTOTPAlreadyEnabled
+	}
+
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		return "", "", err
+	}
+	err = s.DB.Model(user).Updates(map[string]any{"totp_secret": secret, "totp_last_counter": 0}).Error
+	if err != nil {
+		return "", "", err
+	}
+	return secret, auth.TOTPURI(secret, user.Username), nil
+}
+
+// ConfirmTOTP enables two-factor authentication once the code proves the
+// authenticator app holds the secret, and returns the recovery codes
+func (s *MFAService) ConfirmTOTP(ctx context.Context, code string) ([]string, error) {
+	user, err := s.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled {
+		return nil, ErrTOTPAlreadyEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, ErrTOTPNotEnrolled
+	}
+
+	var codes []string
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := useTOTPCode(tx, user, code); err != nil {
+			return err
+		}
+		if err := tx.Model(user).Update("totp_enabled", true).Error; err != nil {
+			return err
+		}
+		codes, err = replaceRecoveryCodes(tx, user.ID)
+		return err
+	})
+	return codes, err
+}
+
+// DisableTOTP turns two-factor authentication off, which takes a current
+// code
+func (s *MFAService) DisableTOTP(ctx context.Context, code string) error {
+	user, err := s.caller(ctx)
+	if err != nil {
+		return err
+	}
+	if !user.TOTPEnabled {
+		return ErrTOTPNotEnrolled
+	}
+
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := useTOTPCode(tx, user, code); err != nil {
+			return err
+		}
+		err := tx.Model(user).Updates(map[string]any{"totp_enabled": false, "totp_secret": "", "totp_last_counter": 0}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Delete(&model.RecoveryCode{}, "user_id = ?", user.ID).Error
+	})
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes of the caller
+func (s *MFAService) RegenerateRecoveryCodes(ctx context.Context, code string) ([]string, error) {
+	user, err := s.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !user.TOTPEnabled {
+		return nil, ErrTOTPNotEnrolled
+	}
+
+	var codes []string
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := useTOTPCode(tx, user, code); err != nil {
+			return err
+		}
+		codes, err = replaceRecoveryCodes(tx, user.ID)
+		return err
+	})
+	return codes, err
+}
+
+// VerifySecondFactor checks the TOTP code, or else the recovery code, of
+// the user at the second step of a login. Both can only be used once.
+func (s *MFAService) VerifySecondFactor(ctx context.Context, userID uuid.UUID, code, recoveryCode string) (*model.User, error) {
+	var user model.User
+	if err := s.DB.First(&user, "id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+	if !user.TOTPEnabled {
+		return nil, ErrTOTPNotEnrolled
+	}
+
+	if code != "" {
+		if err := useTOTPCode(s.DB, &user, code); err != nil {
+			return nil, err
+		}
+		return &user, nil
+	}
+
+	res := s.DB.Model(&model.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", user.ID, hashRecoveryCode(recoveryCode)).
+		Update("used_at", time.Now())
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, ErrInvalidMFACode
+	}
+	return &user, nil
+}
+
+/*
+	Supporting functions
+*/
+
+func (s *MFAService) caller(ctx context.Context) (*model.User, error) {
+	principal, err := sessionPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var user model.User
+	err = s.DB.First(&user, "id = ?", principal.UserID()).Error
+	return &user, err
+}
+
+// useTOTPCode checks the code and records its period, so that the same
+// code cannot be used twice, even by concurrent requests
+func useTOTPCode(db *gorm.DB, user *model.User, code string) error {
+	counter, ok := auth.ValidateTOTP(user.TOTPSecret, code, time.Now())
+	if !ok || counter <= user.TOTPLastCounter {
+		return ErrInvalidMFACode
+	}
+
+	res := db.Model(&model.User{}).
+		Where("id = ? AND totp_last_counter < ?", user.ID, counter).
+		Update("totp_last_counter", counter)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrInvalidMFACode
+	}
+	user.TOTPLastCounter = counter
+	return nil
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, userID uuid.UUID) ([]string, error) {
+	if err := tx.Delete(&model.RecoveryCode{}, "user_id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, RecoveryCodeCount)
+	for i := 0; i < RecoveryCodeCount; i++ {
+		code, err := auth.GenerateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+
+		record := model.RecoveryCode{UserID: userID, CodeHash: hashRecoveryCode(code)}
+		record.ID, _ = uuid.NewV7()
+		if err := tx.Create(&record).Error; err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+// hashRecoveryCode ignores the case and the dashes users may drop when
+// typing the code
+func hashRecoveryCode(code string) string {
+	return auth.HashToken(strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", "")))
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"task-manager/internal/auth"
+	"task-manager/internal/model"
+	"task-manager/internal/notify"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+)
+
+var (
+	ErrNotificationNotFound = errors.New("notification not found")
+)
+
+type (
+	INotificationService interface {
+		GetNotifications(context.Context, model.NotificationFilter) ([]model.Notification, error)
+		MarkRead(context.Context, uuid.UUID) (*model.Notification, error)
+
+		// Notify delivers the messages of the inbox channel
+		Notify(context.Context, notify.Message) error
+	}
+
+	NotificationService struct {
+		DB *gorm.DB
+	}
+)
+
+func NewNotificationService(db *gorm.DB) INotificationService {
+	return &NotificationService{DB: db}
+}
+
+// GetNotifications lists the inbox of the caller, the latest first
+func (s *NotificationService) GetNotifications(ctx context.Context, filter model.NotificationFilter) ([]model.Notification, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	db := s.DB.Where("user_id = ?", principal.UserID())
+	if filter.Unread {
+		db = db.Where("read_at IS NULL")
+	}
+
+	var notifications []model.Notification
+	err := db.Order("created_at DESC").Limit(100).Find(&notifications).Error
+	return notifications, err
+}
+
+// MarkRead marks a notification of the caller as read, once
+func (s *NotificationService) MarkRead(ctx context.Context, id uuid.UUID) (*model.Notification, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	var notification model.Notification
+	if err := s.DB.First(&notification, "id = ? AND user_id = ?", id, principal.UserID()).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, ErrNotificationNotFound
+		}
+		return nil, err
+	}
+	if notification.ReadAt != nil {
+		return &notification, nil
+	}
+
+	now := time.Now()
+	notification.ReadAt = &now
+	err := s.DB.Model(&notification).UpdateColumn("read_at", now).Error
+	return &notification, err
+}
+
+// Notify stores the message in the inbox of its user
+func (s *NotificationService) Notify(ctx context.Context, m notify.Message) error {
+	notification := model.Notification{
+		UserID:     m.UserID,
+		TaskID:     m.TaskID,
+		ReminderID: &m.ReminderID,
+		Subject:    m.Subject,
+		Body:       m.Body,
+	}
+	notification.ID, _ = uuid.NewV7()
+	return s.DB.Create(&notification).Error
+}
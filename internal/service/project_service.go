@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"task-manager/internal/auth"
+	"task-manager/internal/model"
+	"task-manager/internal/workflow"
+
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+)
+
+var (
+	ErrProjectNotFound       = errors.New("project not found")
+	ErrProjectHasTasks       = errors.New("project still has tasks")
+	ErrProjectHasRecurrences = errors.New("project still has recurring tasks")
+	ErrStatusInUse           = errors.New("statuses still used by tasks cannot be removed")
+)
+
+type (
+	IProjectService interface {
+		GetProjects(context.Context) ([]model.Project, error)
+		CreateProject(context.Context, *model.Project) error
+		GetProjectByID(context.Context, uuid.UUID) (*model.Project, error)
+		UpdateWorkflow(context.Context, uuid.UUID, model.Workflow) (*model.Project, error)
+		DeleteProject(context.Context, uuid.UUID) error
+	}
+
+	ProjectService struct {
+		DB *gorm.DB
+
+		// Workflow is given to the projects created without one
+		Workflow *workflow.StateMachine
+	}
+)
+
+func NewProjectService(db *gorm.DB, sm *workflow.StateMachine) IProjectService {
+	if sm == nil {
+		sm = workflow.Default()
+	}
+	return &ProjectService{DB: db, Workflow: sm}
+}
+
+func (s *ProjectService) GetProjects(ctx context.Context) ([]model.Project, error) {
+	db, err := visibleProjects(ctx, s.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []model.Project
+	if err := db.Order("name").Find(&projects).Error; err != nil {
+		return nil, err
+	}
+	return projects, s.rollUpTime(projects)
+}
+
+// CreateProject stores the project in the workspace of ctx, or among the
+// personal projects of the caller when ctx has no tenant. Projects without
+// statuses follow the workflow of the deployment.
+func (s *ProjectService) CreateProject(ctx context.Context, project *model.Project) error {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	if len(project.Workflow.Statuses) == 0 {
+		project.Workflow = s.Workflow.Workflow()
+	}
+	sm, err := workflow.New(project.Workflow)
+	if err != nil {
+		return err
+	}
+
+	project.ID, _ = uuid.NewV7()
+	project.OwnerID = principal.UserID()
+	project.Workflow = sm.Workflow()
+	project.WorkspaceID = nil
+	if tenant, ok := TenantFromContext(ctx); ok {
+		project.WorkspaceID = &tenant.WorkspaceID
+	}
+	return s.DB.Create(project).Error
+}
+
+func (s *ProjectService) GetProjectByID(ctx context.Context, id uuid.UUID) (*model.Project, error) {
+	db, err := visibleProjects(ctx, s.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	var project model.Project
+	if err := db.First(&project, "projects.id = ?", id).Error; err != nil {
+		return &project, err
+	}
+	projects := []model.Project{project}
+	if err := s.rollUpTime(projects); err != nil {
+		return nil, err
+	}
+	return &projects[0], nil
+}
+
+// UpdateWorkflow replaces the workflow of the visible project. Statuses
+// still used by its tasks must be kept, their category may change.
+func (s *ProjectService) UpdateWorkflow(ctx context.Context, id uuid.UUID, w model.Workflow) (*model.Project, error) {
+	project, err := s.GetProjectByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	sm, err := workflow.New(w)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		var statuses []string
+		// Tasks in the trash keep their status for their restore
+		if err := tx.Unscoped().Model(&model.Task{}).Where("project_id = ?", id).Pluck("DISTINCT status", &statuses).Error; err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			if !sm.Known(status) {
+				return ErrStatusInUse
+			}
+			if err := tx.Unscoped().Model(&model.Task{}).
+				Where("project_id = ? AND status = ?", id, status).
+				UpdateColumn("status_category", sm.Category(status)).Error; err != nil {
+				return err
+			}
+		}
+
+		project.Workflow = sm.Workflow()
+		return tx.Model(project).Update("workflow", project.Workflow).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// DeleteProject deletes the visible project once it has no tasks left,
+// including in the trash, and no recurring tasks creating new ones
+func (s *ProjectService) DeleteProject(ctx context.Context, id uuid.UUID) error {
+	project, err := s.GetProjectByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	var count int
+	if err := s.DB.Unscoped().Model(&model.Task{}).Where("project_id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrProjectHasTasks
+	}
+	if err := s.DB.Model(&model.Recurrence{}).Where("project_id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrProjectHasRecurrences
+	}
+	return s.DB.Delete(project).Error
+}
+
+/*
+	Supporting functions
+*/
+
+// rollUpTime sets the time logged on the tasks of the projects
+func (s *ProjectService) rollUpTime(projects []model.Project) error {
+	if len(projects) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, len(projects))
+	for i := range projects {
+		ids[i] = projects[i].ID
+	}
+
+	spent, err := timeSpent(s.DB, "tasks.project_id", ids)
+	if err != nil {
+		return err
+	}
+	for i := range projects {
+		projects[i].TimeSpent = spent[projects[i].ID]
+	}
+	return nil
+}
+
+// visibleProjects scopes the projects table to the tenant of ctx, like the
+// tasks. Outside of a workspace only the projects of the caller are
+// visible.
+func visibleProjects(ctx context.Context, db *gorm.DB) (*gorm.DB, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+	if tenant, ok := TenantFromContext(ctx); ok {
+		return db.Where("projects.workspace_id = ?", tenant.WorkspaceID), nil
+	}
+	return db.Where("projects.workspace_id IS NULL AND projects.owner_id = ?", principal.UserID()), nil
+}
@@ -0,0 +1,351 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"task-manager/internal/auth"
+	"task-manager/internal/model"
+	"task-manager/internal/recurrence"
+	"task-manager/internal/workflow"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+)
+
+var (
+	ErrRecurrenceNotFound = errors.New("recurring task not found")
+	ErrScheduleEnded      = errors.New("the rule has no occurrence from now on")
+)
+
+type (
+	IRecurrenceService interface {
+		GetRecurrences(context.Context) ([]model.Recurrence, error)
+		CreateRecurrence(context.Context, *model.Recurrence) error
+		GetRecurrenceByID(context.Context, uuid.UUID) (*model.Recurrence, error)
+		UpdateRecurrence(context.Context, uuid.UUID, *model.Recurrence) error
+		SkipOccurrence(context.Context, uuid.UUID, string) (*model.Recurrence, error)
+		DeleteRecurrence(context.Context, uuid.UUID) error
+		MaterializeDue(time.Time) ([]model.Task, error)
+	}
+
+	RecurrenceService struct {
+		DB *gorm.DB
+
+		// Workflow gives their initial status to the tasks created outside
+		// of a project
+		Workflow *workflow.StateMachine
+	}
+)
+
+func NewRecurrenceService(db *gorm.DB, sm *workflow.StateMachine) IRecurrenceService {
+	if sm == nil {
+		sm = workflow.Default()
+	}
+	return &RecurrenceService{DB: db, Workflow: sm}
+}
+
+func (s *RecurrenceService) GetRecurrences(ctx context.Context) ([]model.Recurrence, error) {
+	db, err := s.visible(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var recurrences []model.Recurrence
+	err = db.Order("created_at").Find(&recurrences).Error
+	return recurrences, err
+}
+
+// CreateRecurrence stores the template in the workspace of ctx, or among
+// the personal templates of the caller when ctx has no tenant. Its first
+// task is created by the scheduler.
+func (s *RecurrenceService) CreateRecurrence(ctx context.Context, r *model.Recurrence) error {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+	if r.ProjectID != nil {
+		if _, err := s.tasks(s.DB).visibleProject(ctx, *r.ProjectID); err != nil {
+			return err
+		}
+	}
+
+	r.ID, _ = uuid.NewV7()
+	r.OwnerID = principal.UserID()
+	r.CreatedBy = principal.UserID()
+	r.WorkspaceID = nil
+	if tenant, ok := TenantFromContext(ctx); ok {
+		r.WorkspaceID = &tenant.WorkspaceID
+	}
+	r.LastAt, r.LastTaskID, r.Occurrences = nil, nil, 0
+
+	next, err := nextOccurrence(r, time.Now())
+	if err != nil {
+		return err
+	}
+	if next == nil {
+		return ErrScheduleEnded
+	}
+	r.NextAt = next
+	return s.DB.Create(r).Error
+}
+
+func (s *RecurrenceService) GetRecurrenceByID(ctx context.Context, id uuid.UUID) (*model.Recurrence, error) {
+	db, err := s.visible(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var r model.Recurrence
+	if err := db.First(&r, "recurrences.id = ?", id).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, ErrRecurrenceNotFound
+		}
+		return nil, err
+	}
+	return &r, nil
+}
+
+// UpdateRecurrence replaces the template and its rule. The tasks already
+// created are left as they are, the next occurrence follows the new rule.
+func (s *RecurrenceService) UpdateRecurrence(ctx context.Context, id uuid.UUID, r *model.Recurrence) error {
+	current, err := s.GetRecurrenceByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if r.ProjectID != nil {
+		if _, err := s.tasks(s.DB).visibleProject(ctx, *r.ProjectID); err != nil {
+			return err
+		}
+	}
+
+	current.Title = r.Title
+	current.Description = r.Description
+	current.Priority = r.Priority
+	current.ProjectID = r.ProjectID
+	current.RRule = r.RRule
+	current.Timezone = r.Timezone
+	current.StartAt = r.StartAt
+	current.ExceptDates = r.ExceptDates
+	if current.NextAt, err = nextOccurrence(current, time.Now()); err != nil {
+		return err
+	}
+
+	if err := s.DB.Save(current).Error; err != nil {
+		return err
+	}
+	*r = *current
+	return nil
+}
+
+// SkipOccurrence adds the date, formatted as 2006-01-02 in the timezone of
+// the template, to its exception dates. The task of an occurrence already
+// created is not deleted.
+func (s *RecurrenceService) SkipOccurrence(ctx context.Context, id uuid.UUID, date string) (*model.Recurrence, error) {
+	r, err := s.GetRecurrenceByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, d := range r.ExceptDates {
+		if d == date {
+			return r, nil
+		}
+	}
+	r.ExceptDates = append(r.ExceptDates, date)
+	if r.NextAt, err = nextOccurrence(r, time.Now()); err != nil {
+		return nil, err
+	}
+
+	err = s.DB.Model(r).UpdateColumns(map[string]interface{}{
+		"except_dates": r.ExceptDates,
+		"next_at":      r.NextAt,
+	}).Error
+	return r, err
+}
+
+// DeleteRecurrence stops the recurring task, the tasks already created
+// are kept
+func (s *RecurrenceService) DeleteRecurrence(ctx context.Context, id uuid.UUID) error {
+	r, err := s.GetRecurrenceByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.DB.Delete(r).Error
+}
+
+// MaterializeDue creates the task of the next occurrence of the templates
+// whose occurrence time arrived, or whose latest task is done or deleted.
+// Occurrences missed while the server was down are skipped, only the
+// latest gets a task. Each template is created in its own transaction: the
+// failures are returned together, along with the tasks of the others.
+func (s *RecurrenceService) MaterializeDue(now time.Time) ([]model.Task, error) {
+	var ids []uuid.UUID
+	if err := dueRecurrences(s.DB, now).Model(&model.Recurrence{}).Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+
+	var tasks []model.Task
+	var errs []error
+	for _, id := range ids {
+		err := s.DB.Transaction(func(tx *gorm.DB) error {
+			// Another run may have taken the template since it was listed
+			var r model.Recurrence
+			err := dueRecurrences(tx.Set("gorm:query_option", "FOR UPDATE SKIP LOCKED"), now).First(&r, "id = ?", id).Error
+			if gorm.IsRecordNotFoundError(err) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			task, err := s.materialize(tx, &r, now)
+			if err != nil {
+				return err
+			}
+			tasks = append(tasks, *task)
+			return nil
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("recurring task %s: %w", id, err))
+		}
+	}
+	return tasks, errors.Join(errs...)
+}
+
+/*
+	Supporting functions
+*/
+
+// visible scopes the recurrences table to the tenant of ctx, like the
+// tasks. Outside of a workspace only the templates of the caller are
+// visible.
+func (s *RecurrenceService) visible(ctx context.Context) (*gorm.DB, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+	if tenant, ok := TenantFromContext(ctx); ok {
+		return s.DB.Where("recurrences.workspace_id = ?", tenant.WorkspaceID), nil
+	}
+	return s.DB.Where("recurrences.workspace_id IS NULL AND recurrences.owner_id = ?", principal.UserID()), nil
+}
+
+// dueRecurrences scopes db to the templates with an occurrence to create
+// at now
+func dueRecurrences(db *gorm.DB, now time.Time) *gorm.DB {
+	return db.Where("next_at IS NOT NULL AND (next_at <= ? OR NOT EXISTS "+
+		"(SELECT 1 FROM tasks WHERE tasks.id = recurrences.last_task_id AND tasks.deleted_at IS NULL AND tasks.status_category <> ?))",
+		now, model.CategoryDone)
+}
+
+// tasks returns the task service writing in db, for the workflows and
+// the revisions of the created tasks
+func (s *RecurrenceService) tasks(db *gorm.DB) *TaskService {
+	return &TaskService{DB: db, Workflow: s.Workflow}
+}
+
+// materialize creates the task of the next occurrence of the template and
+// moves the template to the following occurrence
+func (s *RecurrenceService) materialize(tx *gorm.DB, r *model.Recurrence, now time.Time) (*model.Task, error) {
+	sm, err := s.tasks(tx).projectWorkflow(r.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Catch up on the occurrences missed while the scheduler was not
+	// running, only the latest one gets a task
+	latest, err := latestOccurrence(r, now)
+	if err != nil {
+		return nil, err
+	}
+	if latest != nil && latest.After(*r.NextAt) {
+		r.NextAt = latest
+	}
+
+	task := model.Task{
+		Title:        r.Title,
+		Description:  r.Description,
+		Status:       sm.Initial(),
+		Priority:     r.Priority,
+		DueAt:        r.NextAt,
+		ProjectID:    r.ProjectID,
+		RecurrenceID: &r.ID,
+		WorkspaceID:  r.WorkspaceID,
+		OwnerID:      r.OwnerID,
+		CreatedBy:    r.CreatedBy,
+		Version:      1,
+	}
+	task.ID, _ = uuid.NewV7()
+	task.StatusCategory = sm.Category(task.Status)
+	if err := tx.Create(&task).Error; err != nil {
+		return nil, err
+	}
+
+	// The creator of the template is the author of its tasks
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: r.CreatedBy.String()})
+	if err := s.tasks(tx).recordRevision(ctx, tx, nil, task.ID); err != nil {
+		return nil, err
+	}
+
+	r.LastAt = r.NextAt
+	r.LastTaskID = &task.ID
+	r.Occurrences++
+	if r.NextAt, err = nextOccurrence(r, now); err != nil {
+		return nil, err
+	}
+	err = tx.Model(r).UpdateColumns(map[string]interface{}{
+		"next_at":      r.NextAt,
+		"last_at":      r.LastAt,
+		"last_task_id": r.LastTaskID,
+		"occurrences":  r.Occurrences,
+	}).Error
+	return &task, err
+}
+
+// latestOccurrence returns the last occurrence of the template at or
+// before now, nil when there is none
+func latestOccurrence(r *model.Recurrence, now time.Time) (*time.Time, error) {
+	schedule, err := recurrenceSchedule(r)
+	if err != nil {
+		return nil, err
+	}
+	latest, ok := schedule.Latest(now)
+	if !ok {
+		return nil, nil
+	}
+	return &latest, nil
+}
+
+// nextOccurrence returns the first occurrence of the template from now on
+// and after its latest task, nil when the rule has no more occurrences
+func nextOccurrence(r *model.Recurrence, now time.Time) (*time.Time, error) {
+	schedule, err := recurrenceSchedule(r)
+	if err != nil {
+		return nil, err
+	}
+
+	after := now.Add(-time.Nanosecond)
+	if r.LastAt != nil && r.LastAt.After(after) {
+		after = *r.LastAt
+	}
+	next, ok := schedule.Next(after)
+	if !ok {
+		return nil, nil
+	}
+	return &next, nil
+}
+
+// recurrenceSchedule expands the rule of the template in its timezone
+func recurrenceSchedule(r *model.Recurrence) (*recurrence.Schedule, error) {
+	rule, err := recurrence.Parse(r.RRule)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := time.LoadLocation(r.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	return recurrence.NewSchedule(rule, r.StartAt, loc, r.ExceptDates), nil
+}
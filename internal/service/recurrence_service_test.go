@@ -0,0 +1,62 @@
+package service
+
+import (
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+var recurrenceColumns = []string{"id", "owner_id", "created_by", "project_id", "title", "description", "priority", "rrule", "timezone", "start_at", "next_at", "last_at"}
+
+func TestMaterializeDue(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	// Test case 1
+	t.Run("MaterializeDue: a failing template does not hold back the others", func(t *testing.T) {
+		db, f := newFakeDB(t)
+		broken, _ := uuid.NewV7()
+		daily, _ := uuid.NewV7()
+		project, _ := uuid.NewV7()
+		now := start.Add(time.Hour)
+
+		f.stub(`SELECT id FROM "recurrences"`, []string{"id"}, []driver.Value{broken.String()}, []driver.Value{daily.String()})
+		f.stubOnce(`SELECT * FROM "recurrences"`, recurrenceColumns,
+			[]driver.Value{broken.String(), adminID.String(), adminID.String(), project.String(), "Broken", "", "medium", "FREQ=DAILY", "UTC", start, start, nil})
+		f.stubOnce(`SELECT * FROM "recurrences"`, recurrenceColumns,
+			[]driver.Value{daily.String(), adminID.String(), adminID.String(), nil, "Daily", "", "medium", "FREQ=DAILY", "UTC", start, start, nil})
+		f.fail(`FROM "projects"`, errors.New("record not found"))
+		f.stub(`SELECT * FROM "tasks"`, []string{"id", "title", "status", "version"}, []driver.Value{daily.String(), "Daily", "pending", int64(1)})
+
+		tasks, err := NewRecurrenceService(db, nil).MaterializeDue(now)
+		require.Len(t, tasks, 1)
+		require.Equal(t, "Daily", tasks[0].Title)
+		require.ErrorContains(t, err, "recurring task "+broken.String())
+
+		rollback := f.indexOf("ROLLBACK")
+		insert := f.indexOf(`INSERT INTO "tasks"`)
+		require.Greater(t, rollback, 0)
+		require.Greater(t, insert, rollback)
+		require.Greater(t, f.indexOf("COMMIT"), insert)
+	})
+
+	// Test case 2
+	t.Run("MaterializeDue: only the latest missed occurrence gets a task", func(t *testing.T) {
+		db, f := newFakeDB(t)
+		daily, _ := uuid.NewV7()
+		now := start.AddDate(0, 0, 3).Add(time.Hour)
+
+		f.stub(`SELECT id FROM "recurrences"`, []string{"id"}, []driver.Value{daily.String()})
+		f.stub(`SELECT * FROM "recurrences"`, recurrenceColumns,
+			[]driver.Value{daily.String(), adminID.String(), adminID.String(), nil, "Daily", "", "medium", "FREQ=DAILY", "UTC", start, start, nil})
+		f.stub(`SELECT * FROM "tasks"`, []string{"id", "title", "status", "version"}, []driver.Value{daily.String(), "Daily", "pending", int64(1)})
+
+		tasks, err := NewRecurrenceService(db, nil).MaterializeDue(now)
+		require.NoError(t, err)
+		require.Len(t, tasks, 1)
+		require.True(t, start.AddDate(0, 0, 3).Equal(*tasks[0].DueAt), "due at %s", tasks[0].DueAt)
+	})
+}
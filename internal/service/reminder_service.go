@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"task-manager/internal/model"
+	"task-manager/internal/notify"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+)
+
+var (
+	ErrReminderNotFound = errors.New("reminder not found")
+	ErrNoDueDate        = errors.New("the task has no due date")
+)
+
+const (
+	// ReminderLease is how long a claimed reminder is kept from the other
+	// runs of the scheduler while it is delivered
+	ReminderLease = 5 * time.Minute
+
+	// MaxReminderAttempts is how many times the delivery of a reminder is
+	// tried before giving up
+	MaxReminderAttempts = 5
+)
+
+type (
+	IReminderService interface {
+		GetReminders(context.Context, uuid.UUID) ([]model.Reminder, error)
+		CreateReminder(context.Context, uuid.UUID, *model.Reminder) error
+		DeleteReminder(context.Context, uuid.UUID, uuid.UUID) error
+		ClaimDueReminders(time.Time, int) ([]notify.Message, error)
+		MarkSent(uuid.UUID, time.Time) error
+		MarkFailed(uuid.UUID, time.Time, error) error
+	}
+
+	ReminderService struct {
+		DB *gorm.DB
+
+		// Channels are the channels configured on the deployment
+		Channels []string
+
+		tasks *TaskService
+	}
+)
+
+func NewReminderService(db *gorm.DB, channels []string) IReminderService {
+	return &ReminderService{DB: db, Channels: channels, tasks: &TaskService{DB: db}}
+}
+
+// GetReminders lists the reminders of the caller on the visible task
+func (s *ReminderService) GetReminders(ctx context.Context, taskID uuid.UUID) ([]model.Reminder, error) {
+	principal, _, err := s.tasks.visibleTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	var reminders []model.Reminder
+	err = s.DB.
+		Where("task_id = ? AND user_id = ?", taskID, principal.UserID()).
+		Order("created_at").
+		Find(&reminders).Error
+	return reminders, err
+}
+
+// CreateReminder reminds the caller of the visible task, through the inbox
+// unless another channel is given. Reminders relative to the due date need
+// the task to have one.
+func (s *ReminderService) CreateReminder(ctx context.Context, taskID uuid.UUID, reminder *model.Reminder) error {
+	principal, _, err := s.tasks.visibleTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	if reminder.Channel == "" {
+		reminder.Channel = notify.ChannelInbox
+	}
+	if !s.enabled(reminder.Channel) {
+		return notify.ErrChannelDisabled
+	}
+	if reminder.BeforeMinutes != nil {
+		var task model.Task
+		if err := s.DB.Select("due_at").First(&task, "id = ?", taskID).Error; err != nil {
+			return err
+		}
+		if task.DueAt == nil {
+			return ErrNoDueDate
+		}
+	}
+
+	reminder.ID, _ = uuid.NewV7()
+	reminder.TaskID = taskID
+	reminder.UserID = principal.UserID()
+	reminder.SentAt, reminder.RetryAt, reminder.FailedAt = nil, nil, nil
+	reminder.Attempts, reminder.LastError = 0, ""
+	return s.DB.Create(reminder).Error
+}
+
+// DeleteReminder deletes a reminder of the caller on the visible task
+func (s *ReminderService) DeleteReminder(ctx context.Context, taskID, id uuid.UUID) error {
+	principal, _, err := s.tasks.visibleTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	res := s.DB.Delete(&model.Reminder{}, "id = ? AND task_id = ? AND user_id = ?", id, taskID, principal.UserID())
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrReminderNotFound
+	}
+	return nil
+}
+
+// ClaimDueReminders returns the messages of at most limit reminders due at
+// now, and keeps them from the other runs for the ReminderLease. Reminders
+// of done and deleted tasks wait for the task to be reopened or restored.
+func (s *ReminderService) ClaimDueReminders(now time.Time, limit int) ([]notify.Message, error) {
+	var messages []notify.Message
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var due []struct {
+			ID      uuid.UUID
+			TaskID  uuid.UUID
+			UserID  uuid.UUID
+			Channel string
+			Title   string
+			DueAt   *time.Time
+			Email   string
+		}
+		err := tx.Raw(`SELECT reminders.id, reminders.task_id, reminders.user_id, reminders.channel, tasks.title, tasks.due_at, users.email
+			FROM reminders
+			JOIN tasks ON tasks.id = reminders.task_id
+			JOIN users ON users.id = reminders.user_id
+			WHERE reminders.sent_at IS NULL AND reminders.failed_at IS NULL
+			AND (reminders.retry_at IS NULL OR reminders.retry_at <= ?)
+			AND tasks.deleted_at IS NULL AND tasks.status_category <> ?
+			AND COALESCE(reminders.remind_at, tasks.due_at - reminders.before_minutes * INTERVAL '1 minute') <= ?
+			ORDER BY reminders.created_at
+			LIMIT ?
+			FOR UPDATE OF reminders SKIP LOCKED`,
+			now, model.CategoryDone, now, limit).
+			Scan(&due).Error
+		if err != nil || len(due) == 0 {
+			return err
+		}
+
+		ids := make([]uuid.UUID, len(due))
+		for i, r := range due {
+			ids[i] = r.ID
+			messages = append(messages, notify.Message{
+				ReminderID: r.ID,
+				TaskID:     r.TaskID,
+				UserID:     r.UserID,
+				Channel:    r.Channel,
+				Subject:    "Reminder: " + r.Title,
+				Body:       reminderBody(r.Title, r.DueAt),
+				DueAt:      r.DueAt,
+				Email:      r.Email,
+			})
+		}
+		return tx.Model(&model.Reminder{}).Where("id IN (?)", ids).UpdateColumn("retry_at", now.Add(ReminderLease)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+func (s *ReminderService) MarkSent(id uuid.UUID, at time.Time) error {
+	return s.DB.Model(&model.Reminder{}).Where("id = ?", id).UpdateColumns(map[string]interface{}{
+		"sent_at":    at,
+		"retry_at":   nil,
+		"last_error": "",
+	}).Error
+}
+
+// MarkFailed schedules the next delivery of the reminder, backing off
+// exponentially, or gives up after MaxReminderAttempts
+func (s *ReminderService) MarkFailed(id uuid.UUID, at time.Time, cause error) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		var reminder model.Reminder
+		if err := tx.Set("gorm:query_option", "FOR UPDATE").First(&reminder, "id = ?", id).Error; err != nil {
+			return err
+		}
+
+		attempts := reminder.Attempts + 1
+		columns := map[string]interface{}{
+			"attempts":   attempts,
+			"last_error": cause.Error(),
+			"retry_at":   at.Add(reminderBackoff(attempts)),
+		}
+		if attempts >= MaxReminderAttempts {
+			columns["retry_at"] = nil
+			columns["failed_at"] = at
+		}
+		return tx.Model(&reminder).UpdateColumns(columns).Error
+	})
+}
+
+/*
+	Supporting functions
+*/
+
+func (s *ReminderService) enabled(channel string) bool {
+	for _, c := range s.Channels {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// reminderBackoff doubles the delay before each new attempt, from a
+// minute up to an hour
+func reminderBackoff(attempts int) time.Duration {
+	delay := time.Minute << (attempts - 1)
+	if delay > time.Hour || delay <= 0 {
+		delay = time.Hour
+	}
+	return delay
+}
+
+func reminderBody(title string, dueAt *time.Time) string {
+	if dueAt == nil {
+		return "Reminder about " + title
+	}
+	return title + " is due on " + dueAt.UTC().Format("2006-01-02 15:04 MST")
+}
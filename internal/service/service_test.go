@@ -0,0 +1,13 @@
+package service
+
+import (
+	"context"
+	"task-manager/internal/auth"
+
+	"github.com/gofrs/uuid"
+)
+
+var (
+	adminID, _ = uuid.NewV7()
+	adminCtx   = auth.WithPrincipal(context.Background(), &auth.Principal{Subject: adminID.String(), Roles: []string{auth.RoleAdmin}})
+)
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"task-manager/internal/auth"
+	"task-manager/internal/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+)
+
+var (
+	ErrBlockerNotFound  = errors.New("blocking task not found")
+	ErrDependencyCycle  = errors.New("dependency would create a cycle")
+	ErrTaskBlocked      = errors.New("task is blocked by open tasks")
+	ErrDependencyExists = errors.New("dependency already exists")
+)
+
+// AddDependency makes the visible task blocked by the blocker, which must
+// be visible too. Edges closing a cycle are rejected.
+func (s *TaskService) AddDependency(ctx context.Context, taskID, blockerID uuid.UUID) error {
+	if _, err := s.GetTaskByID(ctx, taskID); err != nil {
+		return err
+	}
+	if _, err := s.GetTaskByID(ctx, blockerID); err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return ErrBlockerNotFound
+		}
+		return err
+	}
+	principal, _ := auth.PrincipalFromContext(ctx)
+
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		var count int
+		if err := tx.Model(&model.TaskDependency{}).
+			Where("task_id = ? AND blocked_by_id = ?", taskID, blockerID).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrDependencyExists
+		}
+
+		cycle, err := dependsOn(tx, blockerID, taskID)
+		if err != nil {
+			return err
+		}
+		if cycle {
+			return ErrDependencyCycle
+		}
+
+		dependency := model.TaskDependency{TaskID: taskID, BlockedByID: blockerID, CreatedBy: principal.UserID()}
+		return tx.Create(&dependency).Error
+	})
+}
+
+// RemoveDependency unblocks the visible task from the blocker
+func (s *TaskService) RemoveDependency(ctx context.Context, taskID, blockerID uuid.UUID) error {
+	if _, err := s.GetTaskByID(ctx, taskID); err != nil {
+		return err
+	}
+
+	res := s.DB.Delete(&model.TaskDependency{}, "task_id = ? AND blocked_by_id = ?", taskID, blockerID)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+/*
+	Supporting functions
+*/
+
+// dependsOn tells whether the task is blocked by the other task, directly
+// or through other tasks. The graph is walked one level at a time.
+func dependsOn(db *gorm.DB, taskID, otherID uuid.UUID) (bool, error) {
+	if taskID == otherID {
+		return true, nil
+	}
+
+	visited := map[uuid.UUID]bool{taskID: true}
+	level := []uuid.UUID{taskID}
+	for len(level) > 0 {
+		var blockers []uuid.UUID
+		if err := db.Model(&model.TaskDependency{}).
+			Where("task_id IN (?)", level).
+			Pluck("blocked_by_id", &blockers).Error; err != nil {
+			return false, err
+		}
+
+		level = level[:0]
+		for _, id := range blockers {
+			if id == otherID {
+				return true, nil
+			}
+			if !visited[id] {
+				visited[id] = true
+				level = append(level, id)
+			}
+		}
+	}
+	return false, nil
+}
+
+// ensureUnblocked checks that all the blockers of the task are completed
+func (s *TaskService) ensureUnblocked(taskID uuid.UUID) error {
+	var count int
+	err := s.DB.Model(&model.Task{}).
+		Where("id IN (SELECT blocked_by_id FROM task_dependencies WHERE task_id = ?)", taskID).
+		Where("status_category <> ?", model.CategoryDone).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrTaskBlocked
+	}
+	return nil
+}
+
+// loadDependencies sets the blockers of the tasks and the tasks they block
+func (s *TaskService) loadDependencies(tasks []model.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, len(tasks))
+	for i := range tasks {
+		ids[i] = tasks[i].ID
+	}
+
+	// Dependencies on tasks in the trash are kept for their restore, but
+	// not shown
+	var dependencies []model.TaskDependency
+	if err := s.DB.
+		Where("task_id IN (?) OR blocked_by_id IN (?)", ids, ids).
+		Where("task_id NOT IN (SELECT id FROM tasks WHERE deleted_at IS NOT NULL)").
+		Where("blocked_by_id NOT IN (SELECT id FROM tasks WHERE deleted_at IS NOT NULL)").
+		Order("created_at").
+		Find(&dependencies).Error; err != nil {
+		return err
+	}
+
+	blockedBy := make(map[uuid.UUID][]uuid.UUID)
+	blocks := make(map[uuid.UUID][]uuid.UUID)
+	for _, d := range dependencies {
+		blockedBy[d.TaskID] = append(blockedBy[d.TaskID], d.BlockedByID)
+		blocks[d.BlockedByID] = append(blocks[d.BlockedByID], d.TaskID)
+	}
+	for i := range tasks {
+		tasks[i].BlockedBy = blockedBy[tasks[i].ID]
+		tasks[i].Blocks = blocks[tasks[i].ID]
+	}
+	return nil
+}
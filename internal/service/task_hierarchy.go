@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"task-manager/internal/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+)
+
+// MaxTaskDepth is the number of levels of a task hierarchy, a root task
+// and its subtasks make two levels
+const MaxTaskDepth = 5
+
+var (
+	ErrParentNotFound  = errors.New("parent task not found")
+	ErrTaskCycle       = errors.New("a task cannot be a subtask of itself or of its subtasks")
+	ErrTaskTooDeep     = errors.New("task hierarchy is too deep")
+	ErrTaskHasSubtasks = errors.New("task has subtasks")
+)
+
+// GetSubtasks returns the visible direct subtasks of the visible task
+func (s *TaskService) GetSubtasks(ctx context.Context, taskID uuid.UUID) ([]model.Task, error) {
+	if _, err := s.GetTaskByID(ctx, taskID); err != nil {
+		return nil, err
+	}
+
+	db, err := s.visible(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []model.Task
+	if err := db.Where("tasks.parent_id = ?", taskID).Order("tasks.created_at").Preload("Labels").Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	return tasks, s.decorate(tasks)
+}
+
+/*
+	Supporting functions
+*/
+
+// validateParent checks that the task, new when it has no row yet, can
+// become a subtask of the parent: the parent is visible, is not the task
+// nor one of its subtasks, and the hierarchy stays within MaxTaskDepth.
+func (s *TaskService) validateParent(ctx context.Context, taskID, parentID uuid.UUID) error {
+	if _, err := s.GetTaskByID(ctx, parentID); err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return ErrParentNotFound
+		}
+		return err
+	}
+
+	// Walk up from the parent to the root
+	depth := 0
+	for id := &parentID; id != nil; depth++ {
+		if *id == taskID {
+			return ErrTaskCycle
+		}
+		if depth >= MaxTaskDepth {
+			return ErrTaskTooDeep
+		}
+
+		var ancestor model.Task
+		if err := s.DB.Select("id, parent_id").First(&ancestor, "id = ?", *id).Error; err != nil {
+			return err
+		}
+		id = ancestor.ParentID
+	}
+
+	// The subtasks of the task move along with it
+	height, err := subtreeHeight(s.DB, taskID)
+	if err != nil {
+		return err
+	}
+	if depth+height > MaxTaskDepth {
+		return ErrTaskTooDeep
+	}
+	return nil
+}
+
+// subtreeHeight counts the levels of the task and its subtasks, the task
+// alone is one level
+func subtreeHeight(db *gorm.DB, taskID uuid.UUID) (int, error) {
+	height := 1
+	level := []uuid.UUID{taskID}
+	for height <= MaxTaskDepth {
+		var children []uuid.UUID
+		if err := db.Model(&model.Task{}).Where("parent_id IN (?)", level).Pluck("id", &children).Error; err != nil {
+			return 0, err
+		}
+		if len(children) == 0 {
+			break
+		}
+		height++
+		level = children
+	}
+	return height, nil
+}
+
+// descendantIDs returns the subtasks of the task, level by level
+func descendantIDs(db *gorm.DB, taskID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	level := []uuid.UUID{taskID}
+	for depth := 1; depth < MaxTaskDepth && len(level) > 0; depth++ {
+		var children []uuid.UUID
+		if err := db.Model(&model.Task{}).Where("parent_id IN (?)", level).Pluck("id", &children).Error; err != nil {
+			return nil, err
+		}
+		ids = append(ids, children...)
+		level = children
+	}
+	return ids, nil
+}
+
+// rollUpProgress sets the progress of the tasks having subtasks, from the
+// share of their direct subtasks that are completed
+func (s *TaskService) rollUpProgress(tasks []model.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, len(tasks))
+	for i := range tasks {
+		ids[i] = tasks[i].ID
+	}
+
+	rows, err := s.DB.Model(&model.Task{}).
+		Select("parent_id, COUNT(*), COUNT(CASE WHEN status_category = ? THEN 1 END)", model.CategoryDone).
+		Where("parent_id IN (?)", ids).
+		Group("parent_id").
+		Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	progress := make(map[uuid.UUID]*model.TaskProgress)
+	for rows.Next() {
+		var parentID uuid.UUID
+		var p model.TaskProgress
+		if err := rows.Scan(&parentID, &p.Subtasks, &p.Completed); err != nil {
+			return err
+		}
+		p.Percent = p.Completed * 100 / p.Subtasks
+		progress[parentID] = &p
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for i := range tasks {
+		tasks[i].Progress = progress[tasks[i].ID]
+	}
+	return nil
+}
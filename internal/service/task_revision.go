@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"task-manager/internal/auth"
+	"task-manager/internal/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+)
+
+var (
+	ErrRevisionNotFound = errors.New("revision not found")
+)
+
+// GetTaskHistory returns the revisions of the visible task, the latest
+// first, without their snapshots
+func (s *TaskService) GetTaskHistory(ctx context.Context, id uuid.UUID) ([]model.TaskRevision, error) {
+	if _, err := s.GetTaskByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	var revisions []model.TaskRevision
+	err := s.DB.
+		Select("id, task_id, revision, actor_id, changes, created_at").
+		Where("task_id = ?", id).
+		Order("revision DESC").
+		Find(&revisions).Error
+	return revisions, err
+}
+
+// GetTaskRevision returns the revision of the visible task along with the
+// snapshot of the task after it
+func (s *TaskService) GetTaskRevision(ctx context.Context, id uuid.UUID, revision int) (*model.TaskRevision, error) {
+	if _, err := s.GetTaskByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	var rev model.TaskRevision
+	if err := s.DB.First(&rev, "task_id = ? AND revision = ?", id, revision).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, ErrRevisionNotFound
+		}
+		return nil, err
+	}
+	return &rev, nil
+}
+
+// RevertTask puts the fields of the visible task back to their values at
+// the revision. The revert is a change like any other, it follows the
+// workflow and is recorded as a new revision.
+func (s *TaskService) RevertTask(ctx context.Context, id uuid.UUID, revision int, opts model.UpdateTaskOptions) (*model.Task, error) {
+	rev, err := s.GetTaskRevision(ctx, id, revision)
+	if err != nil {
+		return nil, err
+	}
+	return s.PatchTask(ctx, id, *rev.Snapshot, opts)
+}
+
+/*
+	Supporting functions
+*/
+
+// recordRevision stores the revision of the task written in tx, from its
+// state before the change, nil when the task was created
+func (s *TaskService) recordRevision(ctx context.Context, tx *gorm.DB, before *model.Task, id uuid.UUID) error {
+	return s.recordRevisionWith(ctx, tx, before, id, nil)
+}
+
+// recordRevisionWith records the revision along with changes of fields
+// the revisions do not snapshot, like the deletion of the task
+func (s *TaskService) recordRevisionWith(ctx context.Context, tx *gorm.DB, before *model.Task, id uuid.UUID, extra model.TaskChanges) error {
+	var after model.Task
+	if err := tx.First(&after, "id = ?", id).Error; err != nil {
+		return err
+	}
+
+	var old *model.TaskPatch
+	if before != nil {
+		patch := model.NewTaskPatch(before)
+		old = &patch
+	}
+	snapshot := model.NewTaskPatch(&after)
+	changes, err := taskChanges(old, snapshot)
+	if err != nil {
+		return err
+	}
+	for field, change := range extra {
+		changes[field] = change
+	}
+
+	rev := model.TaskRevision{
+		TaskID:   id,
+		Revision: after.Version,
+		Changes:  changes,
+		Snapshot: &snapshot,
+	}
+	rev.ID, _ = uuid.NewV7()
+	if principal, ok := auth.PrincipalFromContext(ctx); ok {
+		rev.ActorID = principal.UserID()
+	}
+	return tx.Create(&rev).Error
+}
+
+// taskChanges compares the fields of the task before and after a change,
+// every field is new when there is no before
+func taskChanges(before *model.TaskPatch, after model.TaskPatch) (model.TaskChanges, error) {
+	oldFields := map[string]interface{}{}
+	if before != nil {
+		if err := toFields(*before, &oldFields); err != nil {
+			return nil, err
+		}
+	}
+	var newFields map[string]interface{}
+	if err := toFields(after, &newFields); err != nil {
+		return nil, err
+	}
+
+	changes := model.TaskChanges{}
+	for field, value := range newFields {
+		if old, ok := oldFields[field]; !ok || !reflect.DeepEqual(old, value) {
+			changes[field] = model.FieldChange{Old: oldFields[field], New: value}
+		}
+	}
+	return changes, nil
+}
+
+// toFields decodes the JSON fields of the patch into the map
+func toFields(patch model.TaskPatch, fields *map[string]interface{}) error {
+	b, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, fields)
+}
@@ -2,50 +2,873 @@ package service
 
 import (
 	"context"
+	"errors"
+	"strings"
+	"task-manager/internal/auth"
 	"task-manager/internal/model"
+	"task-manager/internal/workflow"
+	"time"
 
 	"github.com/gofrs/uuid"
 	"github.com/jinzhu/gorm"
 )
 
+var (
+	ErrUnauthenticated   = errors.New("no authenticated principal")
+	ErrNotTaskOwner      = errors.New("only the task owner can manage its access")
+	ErrAssigneeNotMember = errors.New("assignees must be members of the task's workspace")
+	ErrAssigneeNoAccess  = errors.New("assignees must be the owner of the task or have been granted access to it")
+	ErrLabelMismatch     = errors.New("labels must belong to the workspace, or the owner, of the task")
+	ErrVersionMismatch   = errors.New("task was changed since it was read")
+)
+
 type (
 	ITaskService interface {
 		CreateTask(context.Context, *model.Task) error
-		GetAllTasks(context.Context) ([]model.Task, error)
+		GetAllTasks(context.Context, model.TaskFilter) ([]model.Task, error)
 		GetTaskByID(context.Context, uuid.UUID) (*model.Task, error)
-		UpdateTask(context.Context, uuid.UUID, *model.Task) error
-		DeleteTask(context.Context, uuid.UUID) error
+		UpdateTask(context.Context, uuid.UUID, *model.Task, model.UpdateTaskOptions) (*model.Task, error)
+		PatchTask(context.Context, uuid.UUID, model.TaskPatch, model.UpdateTaskOptions) (*model.Task, error)
+		TransitionTask(context.Context, uuid.UUID, string, model.UpdateTaskOptions) (*model.Task, error)
+		GetWorkflow(context.Context, *uuid.UUID) (*workflow.StateMachine, error)
+		DeleteTask(context.Context, uuid.UUID, model.DeleteTaskOptions) error
+		GetSubtasks(context.Context, uuid.UUID) ([]model.Task, error)
+		GetTaskGrants(context.Context, uuid.UUID) ([]model.TaskGrant, error)
+		GrantTask(context.Context, uuid.UUID, uuid.UUID) error
+		RevokeTaskGrant(context.Context, uuid.UUID, uuid.UUID) error
+		GetTaskAssignees(context.Context, uuid.UUID) ([]model.TaskAssignee, error)
+		GetTaskAssignmentLog(context.Context, uuid.UUID) ([]model.TaskAssignmentLog, error)
+		AssignTask(context.Context, uuid.UUID, []uuid.UUID) error
+		UnassignTask(context.Context, uuid.UUID, []uuid.UUID) error
+		AttachLabels(context.Context, uuid.UUID, []uuid.UUID) error
+		DetachLabel(context.Context, uuid.UUID, uuid.UUID) error
+		AddDependency(context.Context, uuid.UUID, uuid.UUID) error
+		RemoveDependency(context.Context, uuid.UUID, uuid.UUID) error
+		GetTrash(context.Context) ([]model.Task, error)
+		RestoreTask(context.Context, uuid.UUID) (*model.Task, error)
+		GetTaskHistory(context.Context, uuid.UUID) ([]model.TaskRevision, error)
+		GetTaskRevision(context.Context, uuid.UUID, int) (*model.TaskRevision, error)
+		RevertTask(context.Context, uuid.UUID, int, model.UpdateTaskOptions) (*model.Task, error)
+		MarkOverdueTasks(time.Time) ([]model.Task, error)
+		PurgeDeletedTasks(time.Time) (int64, error)
 	}
 
 	TaskService struct {
-		DB *gorm.DB
+		DB       *gorm.DB
+		Workflow *workflow.StateMachine
 	}
 )
 
-func NewTaskService(db *gorm.DB) ITaskService {
-	return &TaskService{DB: db}
+// NewTaskService returns the task service enforcing the status transitions
+// of the state machine, or of the default one when it is nil, for the
+// tasks outside of a project
+func NewTaskService(db *gorm.DB, sm *workflow.StateMachine) ITaskService {
+	if sm == nil {
+		sm = workflow.Default()
+	}
+	return &TaskService{DB: db, Workflow: sm}
 }
 
+// CreateTask stores the task in the workspace of ctx, or among the
+// personal tasks when ctx has no tenant. Tasks start in the initial status
+// of their workflow unless another one is given.
 func (s *TaskService) CreateTask(ctx context.Context, task *model.Task) error {
-	return s.DB.Create(task).Error
+	task.Labels = nil
+	task.Progress = nil
+	task.RecurrenceID = nil
+	task.DeletedAt = nil
+	task.WorkspaceID = nil
+	if tenant, ok := TenantFromContext(ctx); ok {
+		task.WorkspaceID = &tenant.WorkspaceID
+	}
+
+	if task.ProjectID != nil {
+		if _, err := s.visibleProject(ctx, *task.ProjectID); err != nil {
+			return err
+		}
+	}
+	sm, err := s.projectWorkflow(task.ProjectID)
+	if err != nil {
+		return err
+	}
+	if task.Status == "" {
+		task.Status = sm.Initial()
+	}
+	if !sm.Known(task.Status) {
+		return workflow.ErrUnknownStatus
+	}
+	task.StatusCategory = sm.Category(task.Status)
+	task.Version = 1
+
+	if task.ParentID != nil {
+		if err := s.validateParent(ctx, task.ID, *task.ParentID); err != nil {
+			return err
+		}
+	}
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(task).Error; err != nil {
+			return err
+		}
+		return s.recordRevision(ctx, tx, nil, task.ID)
+	})
 }
 
-func (s *TaskService) GetAllTasks(ctx context.Context) ([]model.Task, error) {
+// GetAllTasks lists the visible tasks matching the filter
+func (s *TaskService) GetAllTasks(ctx context.Context, filter model.TaskFilter) ([]model.Task, error) {
+	db, err := s.visible(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	var tasks []model.Task
-	err := s.DB.Find(&tasks).Error
-	return tasks, err
+	if err := applyTaskFilter(db, filter, time.Now()).Preload("Labels").Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	return tasks, s.decorate(tasks)
 }
 
 func (s *TaskService) GetTaskByID(ctx context.Context, id uuid.UUID) (*model.Task, error) {
+	db, err := s.visible(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var task model.Task
+	if err := db.Preload("Labels").First(&task, "tasks.id = ?", id).Error; err != nil {
+		return &task, err
+	}
+	tasks := []model.Task{task}
+	err = s.decorate(tasks)
+	return &tasks[0], err
+}
+
+// UpdateTask updates the visible task. A new status must follow the
+// transitions of the workflow, and starting or completing a task whose
+// blockers are not all completed takes the force option. It returns the
+// updated task.
+func (s *TaskService) UpdateTask(ctx context.Context, id uuid.UUID, task *model.Task, opts model.UpdateTaskOptions) (*model.Task, error) {
+	current, err := s.GetTaskByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Version != 0 && opts.Version != current.Version {
+		return nil, ErrVersionMismatch
+	}
+	task.StatusCategory = ""
+	if task.Status != "" && task.Status != current.Status {
+		sm, err := s.projectWorkflow(current.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.checkTransition(sm, current, task.Status, opts); err != nil {
+			return nil, err
+		}
+		task.StatusCategory = sm.Category(task.Status)
+	}
+	if task.ParentID != nil {
+		if err := s.validateParent(ctx, id, *task.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Compare and swap, the task must still have the version it was read
+	// with
+	task.Version = current.Version + 1
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&model.Task{}).
+			Where("tasks.id = ? AND tasks.version = ?", id, current.Version).
+			Omit("id", "workspace_id", "project_id", "recurrence_id", "owner_id", "created_by", "overdue_at", "created_at", "deleted_at").
+			Updates(task)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrVersionMismatch
+		}
+		return s.recordRevision(ctx, tx, current, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.resetOverdue(id); err != nil {
+		return nil, err
+	}
+	return s.GetTaskByID(ctx, id)
+}
+
+// PatchTask writes every patchable field of the visible task, unlike
+// UpdateTask zero values are stored so fields can be cleared. It returns
+// the updated task.
+func (s *TaskService) PatchTask(ctx context.Context, id uuid.UUID, patch model.TaskPatch, opts model.UpdateTaskOptions) (*model.Task, error) {
+	current, err := s.GetTaskByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Version != 0 && opts.Version != current.Version {
+		return nil, ErrVersionMismatch
+	}
+
+	updates := map[string]interface{}{
+		"version":     current.Version + 1,
+		"title":       patch.Title,
+		"description": patch.Description,
+		"priority":    patch.Priority,
+		"due_at":      patch.DueAt,
+		"parent_id":   patch.ParentID,
+	}
+	if patch.Status != current.Status {
+		sm, err := s.projectWorkflow(current.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.checkTransition(sm, current, patch.Status, opts); err != nil {
+			return nil, err
+		}
+		updates["status"] = patch.Status
+		updates["status_category"] = sm.Category(patch.Status)
+	}
+	if patch.ParentID != nil {
+		if err := s.validateParent(ctx, id, *patch.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&model.Task{}).Where("tasks.id = ? AND tasks.version = ?", id, current.Version).Updates(updates)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrVersionMismatch
+		}
+		return s.recordRevision(ctx, tx, current, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.resetOverdue(id); err != nil {
+		return nil, err
+	}
+	return s.GetTaskByID(ctx, id)
+}
+
+// TransitionTask applies the action to the status of the visible task, and
+// returns the updated task
+func (s *TaskService) TransitionTask(ctx context.Context, id uuid.UUID, action string, opts model.UpdateTaskOptions) (*model.Task, error) {
+	task, err := s.GetTaskByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	sm, err := s.projectWorkflow(task.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	status, err := sm.Target(action, task.Status)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkTransition(sm, task, status, opts); err != nil {
+		return nil, err
+	}
+
+	category := sm.Category(status)
+	updates := map[string]interface{}{"status": status, "status_category": category, "version": task.Version + 1}
+	if category == model.CategoryDone {
+		updates["overdue_at"] = nil
+	}
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&model.Task{}).Where("id = ? AND version = ?", id, task.Version).Updates(updates)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrVersionMismatch
+		}
+		return s.recordRevision(ctx, tx, task, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	task.Status = status
+	task.StatusCategory = category
+	task.Version++
+	return task, nil
+}
+
+// GetWorkflow returns the workflow of the project, or of the deployment
+// when the project is nil. The project must be visible, or hold a task
+// visible to the caller.
+func (s *TaskService) GetWorkflow(ctx context.Context, projectID *uuid.UUID) (*workflow.StateMachine, error) {
+	if projectID == nil {
+		return s.Workflow, nil
+	}
+
+	_, err := s.visibleProject(ctx, *projectID)
+	if errors.Is(err, ErrProjectNotFound) {
+		db, err := s.visible(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var count int
+		if err := db.Model(&model.Task{}).Where("tasks.project_id = ?", *projectID).Count(&count).Error; err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			return nil, ErrProjectNotFound
+		}
+	} else if err != nil {
+		return nil, err
+	}
+	return s.projectWorkflow(projectID)
+}
+
+// DeleteTask moves the visible task to the trash. Its subtasks are trashed
+// with it, orphaned, or prevent the deletion depending on the subtasks
+// option, which defaults to reject.
+func (s *TaskService) DeleteTask(ctx context.Context, id uuid.UUID, opts model.DeleteTaskOptions) error {
+	if _, err := s.GetTaskByID(ctx, id); err != nil {
+		return err
+	}
+
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		// Lock the task so its version cannot change before it is deleted
+		var task model.Task
+		if err := tx.Set("gorm:query_option", "FOR UPDATE").First(&task, "id = ?", id).Error; err != nil {
+			return err
+		}
+		if opts.Version != 0 && opts.Version != task.Version {
+			return ErrVersionMismatch
+		}
+
+		ids := []uuid.UUID{id}
+		switch opts.Subtasks {
+		case model.SubtasksCascade:
+			descendants, err := descendantIDs(tx, id)
+			if err != nil {
+				return err
+			}
+			ids = append(ids, descendants...)
+		case model.SubtasksOrphan:
+			if err := tx.Model(&model.Task{}).Where("parent_id = ?", id).UpdateColumn("parent_id", nil).Error; err != nil {
+				return err
+			}
+		default:
+			var count int
+			if err := tx.Model(&model.Task{}).Where("parent_id = ?", id).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return ErrTaskHasSubtasks
+			}
+		}
+
+		res := tx.Delete(&model.Task{}, "id IN (?)", ids)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		// Timers cannot be stopped on the tasks of the trash
+		return stopTaskTimers(tx, ids)
+	})
+}
+
+func (s *TaskService) GetTaskGrants(ctx context.Context, taskID uuid.UUID) ([]model.TaskGrant, error) {
+	if _, err := s.GetTaskByID(ctx, taskID); err != nil {
+		return nil, err
+	}
+
+	var grants []model.TaskGrant
+	err := s.DB.Where("task_id = ?", taskID).Find(&grants).Error
+	return grants, err
+}
+
+// GrantTask gives the user access to the task. Only the owner of the task
+// can grant access.
+func (s *TaskService) GrantTask(ctx context.Context, taskID, userID uuid.UUID) error {
+	principal, err := s.requireOwner(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	grant := model.TaskGrant{TaskID: taskID, UserID: userID, GrantedBy: principal.UserID()}
+	return s.DB.Save(&grant).Error
+}
+
+// RevokeTaskGrant removes the access of the user to the task
+func (s *TaskService) RevokeTaskGrant(ctx context.Context, taskID, userID uuid.UUID) error {
+	if _, err := s.requireOwner(ctx, taskID); err != nil {
+		return err
+	}
+
+	res := s.DB.Delete(&model.TaskGrant{}, "task_id = ? AND user_id = ?", taskID, userID)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (s *TaskService) GetTaskAssignees(ctx context.Context, taskID uuid.UUID) ([]model.TaskAssignee, error) {
+	if _, err := s.GetTaskByID(ctx, taskID); err != nil {
+		return nil, err
+	}
+
+	var assignees []model.TaskAssignee
+	err := s.DB.Where("task_id = ?", taskID).Order("created_at").Find(&assignees).Error
+	return assignees, err
+}
+
+// GetTaskAssignmentLog returns the assignment changes of the task, oldest first
+func (s *TaskService) GetTaskAssignmentLog(ctx context.Context, taskID uuid.UUID) ([]model.TaskAssignmentLog, error) {
+	if _, err := s.GetTaskByID(ctx, taskID); err != nil {
+		return nil, err
+	}
+
+	var logs []model.TaskAssignmentLog
+	err := s.DB.Where("task_id = ?", taskID).Order("created_at").Find(&logs).Error
+	return logs, err
+}
+
+// AssignTask adds the users to the assignees of the visible task. Users
+// already assigned are left as they are.
+func (s *TaskService) AssignTask(ctx context.Context, taskID uuid.UUID, userIDs []uuid.UUID) error {
+	task, err := s.GetTaskByID(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	principal, _ := auth.PrincipalFromContext(ctx)
+	userIDs = uniqueIDs(userIDs)
+
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := validateAssignees(tx, task, userIDs); err != nil {
+			return err
+		}
+
+		var assigned []uuid.UUID
+		if err := tx.Model(&model.TaskAssignee{}).
+			Where("task_id = ? AND user_id IN (?)", taskID, userIDs).
+			Pluck("user_id", &assigned).Error; err != nil {
+			return err
+		}
+
+		for _, userID := range userIDs {
+			if containsID(assigned, userID) {
+				continue
+			}
+			assignee := model.TaskAssignee{TaskID: taskID, UserID: userID, AssignedBy: principal.UserID()}
+			if err := tx.Create(&assignee).Error; err != nil {
+				return err
+			}
+			if err := logAssignment(tx, taskID, userID, model.AssignmentAssigned, principal.UserID()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// UnassignTask removes the users from the assignees of the visible task
+func (s *TaskService) UnassignTask(ctx context.Context, taskID uuid.UUID, userIDs []uuid.UUID) error {
+	if _, err := s.GetTaskByID(ctx, taskID); err != nil {
+		return err
+	}
+	principal, _ := auth.PrincipalFromContext(ctx)
+	userIDs = uniqueIDs(userIDs)
+
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		var assigned []uuid.UUID
+		if err := tx.Model(&model.TaskAssignee{}).
+			Where("task_id = ? AND user_id IN (?)", taskID, userIDs).
+			Pluck("user_id", &assigned).Error; err != nil {
+			return err
+		}
+		if len(assigned) == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := tx.Delete(&model.TaskAssignee{}, "task_id = ? AND user_id IN (?)", taskID, assigned).Error; err != nil {
+			return err
+		}
+		for _, userID := range assigned {
+			if err := logAssignment(tx, taskID, userID, model.AssignmentUnassigned, principal.UserID()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// AttachLabels attaches the labels to the visible task. The labels must be
+// in the same workspace as the task, or belong to the owner of a personal
+// task.
+func (s *TaskService) AttachLabels(ctx context.Context, taskID uuid.UUID, labelIDs []uuid.UUID) error {
+	task, err := s.GetTaskByID(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	labelIDs = uniqueIDs(labelIDs)
+
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		labels := tx.Model(&model.Label{}).Where("id IN (?)", labelIDs)
+		if task.WorkspaceID != nil {
+			labels = labels.Where("workspace_id = ?", *task.WorkspaceID)
+		} else {
+			labels = labels.Where("workspace_id IS NULL AND owner_id = ?", task.OwnerID)
+		}
+		var count int
+		if err := labels.Count(&count).Error; err != nil {
+			return err
+		}
+		if count != len(labelIDs) {
+			return ErrLabelMismatch
+		}
+
+		for _, labelID := range labelIDs {
+			if containsLabel(task.Labels, labelID) {
+				continue
+			}
+			if err := tx.Create(&model.TaskLabel{TaskID: taskID, LabelID: labelID}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DetachLabel removes the label from the visible task
+func (s *TaskService) DetachLabel(ctx context.Context, taskID, labelID uuid.UUID) error {
+	if _, err := s.GetTaskByID(ctx, taskID); err != nil {
+		return err
+	}
+
+	res := s.DB.Delete(&model.TaskLabel{}, "task_id = ? AND label_id = ?", taskID, labelID)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// MarkOverdueTasks flags the tasks that passed their due date since the
+// last run, across all tenants, and returns them. It backs the overdue job
+// and bypasses the visibility of a principal.
+func (s *TaskService) MarkOverdueTasks(now time.Time) ([]model.Task, error) {
+	var tasks []model.Task
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Set("gorm:query_option", "FOR UPDATE SKIP LOCKED").
+			Where("due_at < ? AND overdue_at IS NULL AND status_category <> ?", now, model.CategoryDone).
+			Find(&tasks).Error
+		if err != nil || len(tasks) == 0 {
+			return err
+		}
+
+		ids := make([]uuid.UUID, len(tasks))
+		for i := range tasks {
+			ids[i] = tasks[i].ID
+			tasks[i].OverdueAt = &now
+		}
+		return tx.Model(&model.Task{}).Where("id IN (?)", ids).UpdateColumn("overdue_at", now).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+/*
+	Supporting functions
+*/
+
+// checkTransition checks that the task can move to the status, following
+// the workflow and, unless forced, its dependencies
+func (s *TaskService) checkTransition(sm *workflow.StateMachine, task *model.Task, status string, opts model.UpdateTaskOptions) error {
+	if err := sm.Validate(task.Status, status); err != nil {
+		return err
+	}
+	if sm.Category(status) != model.CategoryTodo && !opts.Force {
+		return s.ensureUnblocked(task.ID)
+	}
+	return nil
+}
+
+// resetOverdue clears the overdue mark of a task that is no longer
+// overdue, so it is reported again if it falls overdue another time
+func (s *TaskService) resetOverdue(id uuid.UUID) error {
+	return s.DB.Model(&model.Task{}).
+		Where("id = ? AND overdue_at IS NOT NULL", id).
+		Where("due_at IS NULL OR due_at >= ? OR status_category = ?", time.Now(), model.CategoryDone).
+		Update("overdue_at", nil).Error
+}
+
+// visibleProject returns the project if it is visible to the caller
+func (s *TaskService) visibleProject(ctx context.Context, id uuid.UUID) (*model.Project, error) {
+	db, err := visibleProjects(ctx, s.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	var project model.Project
+	if err := db.First(&project, "projects.id = ?", id).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, ErrProjectNotFound
+		}
+		return nil, err
+	}
+	return &project, nil
+}
+
+// projectWorkflow returns the workflow of the project, or of the
+// deployment when the project is nil
+func (s *TaskService) projectWorkflow(projectID *uuid.UUID) (*workflow.StateMachine, error) {
+	if projectID == nil {
+		return s.Workflow, nil
+	}
+
+	var project model.Project
+	if err := s.DB.Select("workflow").First(&project, "id = ?", *projectID).Error; err != nil {
+		return nil, err
+	}
+	return workflow.New(project.Workflow)
+}
+
+// decorate sets the fields of the tasks derived from the other tasks
+func (s *TaskService) decorate(tasks []model.Task) error {
+	if err := s.rollUpProgress(tasks); err != nil {
+		return err
+	}
+	if err := s.rollUpTime(tasks); err != nil {
+		return err
+	}
+	return s.loadDependencies(tasks)
+}
+
+// rollUpTime sets the time logged on the tasks
+func (s *TaskService) rollUpTime(tasks []model.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, len(tasks))
+	for i := range tasks {
+		ids[i] = tasks[i].ID
+	}
+
+	spent, err := timeSpent(s.DB, "work_logs.task_id", ids)
+	if err != nil {
+		return err
+	}
+	for i := range tasks {
+		tasks[i].TimeSpent = spent[tasks[i].ID]
+	}
+	return nil
+}
+
+// priorityRank orders the priorities from the lowest to the highest
+const priorityRank = "CASE tasks.priority WHEN 'urgent' THEN 4 WHEN 'high' THEN 3 WHEN 'medium' THEN 2 ELSE 1 END"
+
+// applyTaskFilter narrows and orders the query of tasks by the filter
+func applyTaskFilter(db *gorm.DB, filter model.TaskFilter, now time.Time) *gorm.DB {
+	if filter.Status != "" {
+		db = db.Where("tasks.status = ?", filter.Status)
+	}
+	if filter.Category != "" {
+		db = db.Where("tasks.status_category = ?", filter.Category)
+	}
+	if filter.ProjectID != "" {
+		db = db.Where("tasks.project_id = ?", filter.ProjectID)
+	}
+	if filter.Priority != "" {
+		db = db.Where("tasks.priority = ?", filter.Priority)
+	}
+	if !filter.DueBefore.IsZero() {
+		db = db.Where("tasks.due_at < ?", filter.DueBefore)
+	}
+	if !filter.DueAfter.IsZero() {
+		db = db.Where("tasks.due_at >= ?", filter.DueAfter)
+	}
+	if filter.AssigneeID != uuid.Nil {
+		db = db.Where("tasks.id IN (SELECT task_id FROM task_assignees WHERE user_id = ?)", filter.AssigneeID)
+	}
+	if len(filter.Labels) > 0 {
+		names := make([]string, len(filter.Labels))
+		for i, name := range filter.Labels {
+			names[i] = strings.ToLower(name)
+		}
+		labeled := "SELECT task_labels.task_id FROM task_labels " +
+			"JOIN labels ON labels.id = task_labels.label_id WHERE LOWER(labels.name) IN (?)"
+		if filter.LabelMatch == "all" {
+			db = db.Where("tasks.id IN ("+labeled+" GROUP BY task_labels.task_id HAVING COUNT(DISTINCT LOWER(labels.name)) = ?)",
+				names, len(uniqueStrings(names)))
+		} else {
+			db = db.Where("tasks.id IN ("+labeled+")", names)
+		}
+	}
+	if filter.Overdue != nil {
+		overdue := "tasks.due_at < ? AND tasks.status_category <> ?"
+		if *filter.Overdue {
+			db = db.Where(overdue, now, model.CategoryDone)
+		} else {
+			db = db.Where("NOT ("+overdue+") OR tasks.due_at IS NULL", now, model.CategoryDone)
+		}
+	}
+
+	switch filter.Sort {
+	case "created_at":
+		db = db.Order("tasks.created_at")
+	case "-created_at":
+		db = db.Order("tasks.created_at DESC")
+	case "due_at":
+		db = db.Order("tasks.due_at NULLS LAST")
+	case "-due_at":
+		db = db.Order("tasks.due_at DESC NULLS LAST")
+	case "priority":
+		db = db.Order(priorityRank)
+	case "-priority":
+		db = db.Order(priorityRank + " DESC")
+	}
+	return db
+}
+
+// visible scopes the tasks table to the tenant of ctx. Within a workspace
+// every member sees every task. Outside of one, the personal tasks are
+// scoped to the ones the principal owns or has been granted, whatever its
+// role.
+func (s *TaskService) visible(ctx context.Context) (*gorm.DB, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+	if tenant, ok := TenantFromContext(ctx); ok {
+		return s.DB.Where("tasks.workspace_id = ?", tenant.WorkspaceID), nil
+	}
+
+	userID := principal.UserID()
+	return s.DB.Where("tasks.workspace_id IS NULL").Where(
+		"tasks.owner_id = ? OR tasks.id IN (SELECT task_id FROM task_grants WHERE user_id = ?)",
+		userID, userID,
+	), nil
+}
+
+// visibleTask checks that the task is visible to the principal of ctx, for
+// the services working on the rows of a task. It returns the principal and
+// the columns of the task that scope its rows.
+func (s *TaskService) visibleTask(ctx context.Context, taskID uuid.UUID) (*auth.Principal, *model.Task, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, nil, ErrUnauthenticated
+	}
+	db, err := s.visible(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	var task model.Task
-	err := s.DB.First(&task, "id = ?", id).Error
-	return &task, err
+	err = db.Select("tasks.id, tasks.title, tasks.workspace_id, tasks.owner_id").First(&task, "tasks.id = ?", taskID).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	return principal, &task, nil
+}
+
+// requireOwner checks that the principal of ctx owns the visible task
+func (s *TaskService) requireOwner(ctx context.Context, taskID uuid.UUID) (*auth.Principal, error) {
+	task, err := s.GetTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	principal, _ := auth.PrincipalFromContext(ctx)
+	if _, ok := TenantFromContext(ctx); ok {
+		// Workspace tasks are shared with all members already
+		return nil, ErrNotTaskOwner
+	}
+	if task.OwnerID != principal.UserID() {
+		return nil, ErrNotTaskOwner
+	}
+	return principal, nil
 }
 
-func (s *TaskService) UpdateTask(ctx context.Context, id uuid.UUID, task *model.Task) error {
-	return s.DB.Model(&model.Task{}).Where("id = ?", id).Updates(task).Error
+// validateAssignees checks that the users can be assigned to the task. The
+// tasks of a workspace go to its members, personal tasks to their owner
+// and the users granted access.
+func validateAssignees(db *gorm.DB, task *model.Task, userIDs []uuid.UUID) error {
+	var allowed []uuid.UUID
+	if task.WorkspaceID != nil {
+		if err := db.Model(&model.WorkspaceMember{}).
+			Where("workspace_id = ? AND user_id IN (?)", *task.WorkspaceID, userIDs).
+			Pluck("user_id", &allowed).Error; err != nil {
+			return err
+		}
+		if len(allowed) != len(userIDs) {
+			return ErrAssigneeNotMember
+		}
+		return nil
+	}
+
+	if err := db.Model(&model.TaskGrant{}).
+		Where("task_id = ? AND user_id IN (?)", task.ID, userIDs).
+		Pluck("user_id", &allowed).Error; err != nil {
+		return err
+	}
+	for _, userID := range userIDs {
+		if userID != task.OwnerID && !containsID(allowed, userID) {
+			return ErrAssigneeNoAccess
+		}
+	}
+	return nil
 }
 
-func (s *TaskService) DeleteTask(ctx context.Context, id uuid.UUID) error {
-	return s.DB.Delete(&model.Task{}, "id = ?", id).Error
+// logAssignment records the assignment change made by the actor
+func logAssignment(db *gorm.DB, taskID, userID uuid.UUID, action string, actorID uuid.UUID) error {
+	entry := model.TaskAssignmentLog{TaskID: taskID, UserID: userID, Action: action, ActorID: actorID}
+	entry.ID, _ = uuid.NewV7()
+	return db.Create(&entry).Error
+}
+
+// uniqueIDs returns the IDs without duplicates, in their first order
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	unique := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !containsID(unique, id) {
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+func uniqueStrings(values []string) []string {
+	unique := make([]string, 0, len(values))
+	seen := make(map[string]bool, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
+
+func containsLabel(labels []model.Label, id uuid.UUID) bool {
+	for _, label := range labels {
+		if label.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func containsID(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, other := range ids {
+		if other == id {
+			return true
+		}
+	}
+	return false
 }
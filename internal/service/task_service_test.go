@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"database/sql/driver"
+	"task-manager/internal/model"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVisible(t *testing.T) {
+	// Test case 1
+	t.Run("visible: admins only see their own personal tasks", func(t *testing.T) {
+		db, f := newFakeDB(t)
+
+		scoped, err := (&TaskService{DB: db}).visible(adminCtx)
+		require.NoError(t, err)
+		require.NoError(t, scoped.Find(&[]model.Task{}).Error)
+
+		query := f.executed()[f.indexOf(`SELECT * FROM "tasks"`)]
+		require.Contains(t, query, "tasks.owner_id = $")
+		require.Contains(t, query, "SELECT task_id FROM task_grants WHERE user_id = $")
+	})
+}
+
+func TestVisibleTask(t *testing.T) {
+	// Test case 1
+	t.Run("visibleTask: unauthenticated", func(t *testing.T) {
+		db, f := newFakeDB(t)
+		id, _ := uuid.NewV7()
+
+		_, _, err := (&TaskService{DB: db}).visibleTask(context.Background(), id)
+		require.ErrorIs(t, err, ErrUnauthenticated)
+		require.Empty(t, f.executed())
+	})
+
+	// Test case 2
+	t.Run("visibleTask: scoped to the visible tasks", func(t *testing.T) {
+		db, f := newFakeDB(t)
+		id, _ := uuid.NewV7()
+		f.stub(`FROM "tasks"`, []string{"id", "title", "owner_id"}, []driver.Value{id.String(), "Task 1", adminID.String()})
+
+		principal, task, err := (&TaskService{DB: db}).visibleTask(adminCtx, id)
+		require.NoError(t, err)
+		require.Equal(t, adminID, principal.UserID())
+		require.Equal(t, "Task 1", task.Title)
+		require.Contains(t, f.executed()[0], "tasks.owner_id = $")
+	})
+}
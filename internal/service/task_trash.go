@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"task-manager/internal/model"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+)
+
+var (
+	ErrParentDeleted = errors.New("the parent task is in the trash, restore it first")
+)
+
+// GetTrash returns the visible tasks in the trash, the most recently
+// deleted first
+func (s *TaskService) GetTrash(ctx context.Context) ([]model.Task, error) {
+	db, err := s.visible(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []model.Task
+	err = db.Unscoped().
+		Preload("Labels").
+		Where("tasks.deleted_at IS NOT NULL").
+		Order("tasks.deleted_at DESC").
+		Find(&tasks).Error
+	return tasks, err
+}
+
+// RestoreTask takes the visible task out of the trash, along with the
+// subtasks deleted with it. A subtask cannot be restored while its parent
+// is in the trash.
+func (s *TaskService) RestoreTask(ctx context.Context, id uuid.UUID) (*model.Task, error) {
+	db, err := s.visible(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var task model.Task
+	if err := db.Unscoped().Where("tasks.deleted_at IS NOT NULL").First(&task, "tasks.id = ?", id).Error; err != nil {
+		return nil, err
+	}
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		if task.ParentID != nil {
+			var count int
+			if err := tx.Unscoped().Model(&model.Task{}).
+				Where("id = ? AND deleted_at IS NOT NULL", *task.ParentID).
+				Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return ErrParentDeleted
+			}
+		}
+
+		// A cascading delete trashes the subtasks at the same time
+		descendants, err := descendantIDs(tx.Unscoped().Where("deleted_at = ?", *task.DeletedAt), id)
+		if err != nil {
+			return err
+		}
+		ids := append(descendants, id)
+		var before []model.Task
+		if err := tx.Unscoped().Where("id IN (?)", ids).Find(&before).Error; err != nil {
+			return err
+		}
+
+		// The restore is a change of the tasks, older ETags no longer match
+		err = tx.Unscoped().Model(&model.Task{}).
+			Where("id IN (?)", ids).
+			UpdateColumns(map[string]interface{}{"deleted_at": nil, "version": gorm.Expr("version + 1")}).Error
+		if err != nil {
+			return err
+		}
+		for i := range before {
+			restored := model.TaskChanges{"deleted_at": {Old: before[i].DeletedAt, New: nil}}
+			if err := s.recordRevisionWith(ctx, tx, &before[i], before[i].ID, restored); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetTaskByID(ctx, id)
+}
+
+// PurgeDeletedTasks permanently deletes the tasks moved to the trash
+// before the time, across all tenants, and returns how many were deleted.
+// It backs the purge job.
+func (s *TaskService) PurgeDeletedTasks(before time.Time) (int64, error) {
+	var purged int64
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var ids []uuid.UUID
+		if err := tx.Unscoped().Model(&model.Task{}).Where("deleted_at < ?", before).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		if err := purgeTaskRows(tx, ids); err != nil {
+			return err
+		}
+
+		res := tx.Unscoped().Delete(&model.Task{}, "id IN (?)", ids)
+		purged = res.RowsAffected
+		return res.Error
+	})
+	return purged, err
+}
+
+/*
+	Supporting functions
+*/
+
+// purgeTaskRows deletes the rows referencing the tasks about to be purged.
+// The schema built by AutoMigrate has no foreign keys to cascade the
+// delete.
+func purgeTaskRows(tx *gorm.DB, ids []uuid.UUID) error {
+	comments := tx.Model(&model.Comment{}).Select("id").Where("task_id IN (?)", ids).QueryExpr()
+	deletes := []struct {
+		model interface{}
+		where string
+		args  []interface{}
+	}{
+		{&model.CommentRevision{}, "comment_id IN (?)", []interface{}{comments}},
+		{&model.CommentMention{}, "comment_id IN (?)", []interface{}{comments}},
+		{&model.Comment{}, "task_id IN (?)", []interface{}{ids}},
+		{&model.Notification{}, "task_id IN (?)", []interface{}{ids}},
+		{&model.Reminder{}, "task_id IN (?)", []interface{}{ids}},
+		{&model.WorkLog{}, "task_id IN (?)", []interface{}{ids}},
+		{&model.TaskRevision{}, "task_id IN (?)", []interface{}{ids}},
+		{&model.TaskLabel{}, "task_id IN (?)", []interface{}{ids}},
+		{&model.TaskAssignee{}, "task_id IN (?)", []interface{}{ids}},
+		{&model.TaskAssignmentLog{}, "task_id IN (?)", []interface{}{ids}},
+		{&model.TaskGrant{}, "task_id IN (?)", []interface{}{ids}},
+		{&model.TaskDependency{}, "task_id IN (?) OR blocked_by_id IN (?)", []interface{}{ids, ids}},
+	}
+	for _, d := range deletes {
+		if err := tx.Delete(d.model, append([]interface{}{d.where}, d.args...)...).Error; err != nil {
+			return err
+		}
+	}
+
+	// The subtasks and recurring tasks left behind lose their link
+	if err := tx.Unscoped().Model(&model.Task{}).Where("parent_id IN (?)", ids).UpdateColumn("parent_id", nil).Error; err != nil {
+		return err
+	}
+	return tx.Model(&model.Recurrence{}).Where("last_task_id IN (?)", ids).UpdateColumn("last_task_id", nil).Error
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"database/sql/driver"
+	"strings"
+	"task-manager/internal/model"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPurgeDeletedTasks(t *testing.T) {
+	// Test case 1
+	t.Run("PurgeDeletedTasks: deletes the rows referencing the tasks", func(t *testing.T) {
+		db, f := newFakeDB(t)
+		id1, _ := uuid.NewV7()
+		id2, _ := uuid.NewV7()
+		f.stub(`SELECT id FROM "tasks"`, []string{"id"}, []driver.Value{id1.String()}, []driver.Value{id2.String()})
+
+		purged, err := (&TaskService{DB: db}).PurgeDeletedTasks(time.Now())
+		require.NoError(t, err)
+		require.Equal(t, int64(1), purged)
+
+		tables := []string{
+			"comment_revisions", "comment_mentions", "comments", "notifications", "reminders", "work_logs",
+			"task_revisions", "task_labels", "task_assignees", "task_assignment_logs", "task_grants", "task_dependencies",
+		}
+		tasks := f.indexOf(`DELETE FROM "tasks"`)
+		require.Greater(t, tasks, 0)
+		for _, table := range tables {
+			i := f.indexOf(`DELETE FROM "` + table + `"`)
+			require.Greater(t, i, 0, table)
+			require.Less(t, i, tasks, table)
+		}
+		require.Contains(t, f.executed()[f.indexOf(`DELETE FROM "comment_revisions"`)], `SELECT id FROM "comments"`)
+		require.Contains(t, f.executed()[f.indexOf(`DELETE FROM "task_dependencies"`)], "blocked_by_id IN")
+
+		statements := f.executed()
+		require.Equal(t, "BEGIN", statements[0])
+		require.Equal(t, "COMMIT", statements[len(statements)-1])
+	})
+
+	// Test case 2
+	t.Run("PurgeDeletedTasks: nothing to purge", func(t *testing.T) {
+		db, f := newFakeDB(t)
+
+		purged, err := (&TaskService{DB: db}).PurgeDeletedTasks(time.Now())
+		require.NoError(t, err)
+		require.Zero(t, purged)
+		for _, stmt := range f.executed() {
+			require.False(t, strings.HasPrefix(stmt, "DELETE"), stmt)
+		}
+	})
+}
+
+func TestDeleteTask(t *testing.T) {
+	// Test case 1
+	t.Run("DeleteTask: stops the timers of the task", func(t *testing.T) {
+		db, f := newFakeDB(t)
+		id, _ := uuid.NewV7()
+		f.stub(`SELECT * FROM "tasks"`, []string{"id", "title", "status", "version"},
+			[]driver.Value{id.String(), "Task 1", "pending", int64(1)})
+		f.stub(`SELECT count(*) FROM "tasks"`, []string{"count"}, []driver.Value{int64(0)})
+
+		err := (&TaskService{DB: db}).DeleteTask(adminCtx, id, model.DeleteTaskOptions{})
+		require.NoError(t, err)
+
+		trash := f.indexOf(`UPDATE "tasks" SET "deleted_at"`)
+		timers := f.indexOf(`UPDATE "work_logs"`)
+		require.Greater(t, trash, 0)
+		require.Greater(t, timers, trash)
+		require.Contains(t, f.executed()[timers], "ended_at IS NULL")
+		require.Less(t, timers, f.indexOf("COMMIT"))
+	})
+}
+
+func TestRestoreTask(t *testing.T) {
+	// Test case 1
+	t.Run("RestoreTask: bumps the version and records a revision", func(t *testing.T) {
+		db, f := newFakeDB(t)
+		id, _ := uuid.NewV7()
+		deletedAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+		f.stub(`SELECT * FROM "tasks"`, []string{"id", "title", "status", "version", "deleted_at"},
+			[]driver.Value{id.String(), "Task 1", "pending", int64(3), deletedAt})
+
+		_, err := (&TaskService{DB: db}).RestoreTask(adminCtx, id)
+		require.NoError(t, err)
+
+		update := f.indexOf(`UPDATE "tasks"`)
+		require.Greater(t, update, 0)
+		require.Contains(t, f.executed()[update], `"version" = version + 1`)
+		require.Contains(t, f.executed()[update], `"deleted_at" = $`)
+		revision := f.indexOf(`INSERT INTO "task_revisions"`)
+		require.Greater(t, revision, update)
+		require.Less(t, revision, f.indexOf("COMMIT"))
+	})
+}
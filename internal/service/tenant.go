@@ -0,0 +1,21 @@
+package service
+
+import (
+	"context"
+	"task-manager/internal/model"
+)
+
+type tenantCtxKey struct{}
+
+// WithTenant returns a copy of ctx scoped to the workspace of the
+// membership. Services only ever read and write rows of that workspace.
+func WithTenant(ctx context.Context, member *model.WorkspaceMember) context.Context {
+	return context.WithValue(ctx, tenantCtxKey{}, member)
+}
+
+// TenantFromContext returns the workspace membership ctx is scoped to, if
+// any. Requests without a tenant work on the personal tasks of the caller.
+func TenantFromContext(ctx context.Context) (*model.WorkspaceMember, bool) {
+	member, ok := ctx.Value(tenantCtxKey{}).(*model.WorkspaceMember)
+	return member, ok && member != nil
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"task-manager/internal/auth"
+	"task-manager/internal/model"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+)
+
+var (
+	ErrTimerRunning     = errors.New("a timer is already running")
+	ErrNoRunningTimer   = errors.New("no timer is running on this task")
+	ErrWorkLogNotFound  = errors.New("work log not found")
+	ErrInvalidTimesheet = errors.New("the end of the range is before its start")
+)
+
+type (
+	ITimeService interface {
+		StartTimer(context.Context, uuid.UUID, string) (*model.WorkLog, error)
+		StopTimer(context.Context, uuid.UUID) (*model.WorkLog, error)
+		StopRunningTimer(context.Context) (*model.WorkLog, error)
+		GetRunningTimer(context.Context) (*model.WorkLog, error)
+		GetWorkLogs(context.Context, uuid.UUID) ([]model.WorkLog, error)
+		CreateWorkLog(context.Context, uuid.UUID, *model.WorkLog) error
+		DeleteWorkLog(context.Context, uuid.UUID, uuid.UUID) error
+		GetTimesheet(context.Context, model.TimesheetFilter) (*model.Timesheet, error)
+	}
+
+	TimeService struct {
+		DB *gorm.DB
+
+		tasks *TaskService
+	}
+)
+
+func NewTimeService(db *gorm.DB) ITimeService {
+	return &TimeService{DB: db, tasks: &TaskService{DB: db}}
+}
+
+// StartTimer starts a timer of the caller on the visible task. A user runs
+// one timer at a time, across tasks and workspaces.
+func (s *TimeService) StartTimer(ctx context.Context, taskID uuid.UUID, note string) (*model.WorkLog, error) {
+	principal, _, err := s.tasks.visibleTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	log := model.WorkLog{
+		TaskID:    taskID,
+		UserID:    principal.UserID(),
+		StartedAt: time.Now(),
+		Note:      note,
+		Source:    model.WorkLogTimer,
+	}
+	log.ID, _ = uuid.NewV7()
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		// The lock on the user serializes the timers started at once
+		if err := tx.Exec("SELECT 1 FROM users WHERE id = ? FOR UPDATE", log.UserID).Error; err != nil {
+			return err
+		}
+		var running int
+		if err := tx.Model(&model.WorkLog{}).Where("user_id = ? AND ended_at IS NULL", log.UserID).Count(&running).Error; err != nil {
+			return err
+		}
+		if running > 0 {
+			return ErrTimerRunning
+		}
+		return tx.Create(&log).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &log, nil
+}
+
+// StopTimer stops the timer of the caller on the visible task
+func (s *TimeService) StopTimer(ctx context.Context, taskID uuid.UUID) (*model.WorkLog, error) {
+	principal, _, err := s.tasks.visibleTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	return stopTimer(s.DB, "task_id = ? AND user_id = ? AND ended_at IS NULL", taskID, principal.UserID())
+}
+
+// StopRunningTimer stops the running timer of the caller, on any task. The
+// task need not be visible anymore, so a timer is never left running on a
+// task the caller lost access to.
+func (s *TimeService) StopRunningTimer(ctx context.Context) (*model.WorkLog, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+	return stopTimer(s.DB, "user_id = ? AND ended_at IS NULL", principal.UserID())
+}
+
+// GetRunningTimer returns the running timer of the caller, on any task
+func (s *TimeService) GetRunningTimer(ctx context.Context) (*model.WorkLog, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	var log model.WorkLog
+	if err := s.DB.First(&log, "user_id = ? AND ended_at IS NULL", principal.UserID()).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, ErrNoRunningTimer
+		}
+		return nil, err
+	}
+	return &log, nil
+}
+
+// GetWorkLogs lists the work logs of every user on the visible task, the
+// latest first
+func (s *TimeService) GetWorkLogs(ctx context.Context, taskID uuid.UUID) ([]model.WorkLog, error) {
+	if _, _, err := s.tasks.visibleTask(ctx, taskID); err != nil {
+		return nil, err
+	}
+
+	var logs []model.WorkLog
+	err := s.DB.Where("task_id = ?", taskID).Order("started_at DESC").Find(&logs).Error
+	return logs, err
+}
+
+// CreateWorkLog records time the caller spent on the visible task
+func (s *TimeService) CreateWorkLog(ctx context.Context, taskID uuid.UUID, log *model.WorkLog) error {
+	principal, _, err := s.tasks.visibleTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	log.ID, _ = uuid.NewV7()
+	log.TaskID = taskID
+	log.UserID = principal.UserID()
+	log.Source = model.WorkLogManual
+	log.Seconds = int64(log.EndedAt.Sub(log.StartedAt) / time.Second)
+	return s.DB.Create(log).Error
+}
+
+// DeleteWorkLog deletes a work log of the caller on the visible task
+func (s *TimeService) DeleteWorkLog(ctx context.Context, taskID, id uuid.UUID) error {
+	principal, _, err := s.tasks.visibleTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	res := s.DB.Delete(&model.WorkLog{}, "id = ? AND task_id = ? AND user_id = ?", id, taskID, principal.UserID())
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrWorkLogNotFound
+	}
+	return nil
+}
+
+// GetTimesheet lists the ended work logs on the visible tasks matching the
+// filter, by start time, along with the total of each user
+func (s *TimeService) GetTimesheet(ctx context.Context, filter model.TimesheetFilter) (*model.Timesheet, error) {
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.To.Before(filter.From) {
+		return nil, ErrInvalidTimesheet
+	}
+	db, err := s.tasks.visible(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	db = db.Table("work_logs").
+		Select("work_logs.id AS work_log_id, work_logs.user_id, users.username, work_logs.task_id, tasks.title AS task_title, " +
+			"tasks.project_id, work_logs.started_at, work_logs.ended_at, work_logs.seconds, work_logs.note").
+		Joins("JOIN tasks ON tasks.id = work_logs.task_id AND tasks.deleted_at IS NULL").
+		Joins("JOIN users ON users.id = work_logs.user_id").
+		Where("work_logs.ended_at IS NOT NULL")
+	if filter.UserID != "" {
+		db = db.Where("work_logs.user_id = ?", filter.UserID)
+	}
+	if filter.ProjectID != "" {
+		db = db.Where("tasks.project_id = ?", filter.ProjectID)
+	}
+	if !filter.From.IsZero() {
+		db = db.Where("work_logs.started_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		db = db.Where("work_logs.started_at < ?", filter.To.AddDate(0, 0, 1))
+	}
+
+	timesheet := model.Timesheet{Entries: []model.TimesheetEntry{}, Totals: []model.TimesheetTotal{}}
+	if err := db.Order("work_logs.started_at, work_logs.id").Scan(&timesheet.Entries).Error; err != nil {
+		return nil, err
+	}
+
+	totals := make(map[uuid.UUID]int)
+	for _, e := range timesheet.Entries {
+		i, ok := totals[e.UserID]
+		if !ok {
+			i = len(timesheet.Totals)
+			totals[e.UserID] = i
+			timesheet.Totals = append(timesheet.Totals, model.TimesheetTotal{UserID: e.UserID, Username: e.Username})
+		}
+		timesheet.Totals[i].Seconds += e.Seconds
+		timesheet.Seconds += e.Seconds
+	}
+	return &timesheet, nil
+}
+
+/*
+	Supporting functions
+*/
+
+// stopTimer ends the running work log matching the conditions
+func stopTimer(db *gorm.DB, where ...interface{}) (*model.WorkLog, error) {
+	var log model.WorkLog
+	err := db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Set("gorm:query_option", "FOR UPDATE").First(&log, where...).Error
+		if gorm.IsRecordNotFoundError(err) {
+			return ErrNoRunningTimer
+		}
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		log.EndedAt = &now
+		log.Seconds = int64(now.Sub(log.StartedAt) / time.Second)
+		return tx.Model(&log).UpdateColumns(map[string]interface{}{"ended_at": now, "seconds": log.Seconds}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &log, nil
+}
+
+// stopTaskTimers ends the running work logs of the tasks, whoever started
+// them
+func stopTaskTimers(db *gorm.DB, taskIDs []uuid.UUID) error {
+	now := time.Now()
+	return db.Model(&model.WorkLog{}).
+		Where("task_id IN (?) AND ended_at IS NULL", taskIDs).
+		UpdateColumns(map[string]interface{}{
+			"ended_at": now,
+			"seconds":  gorm.Expr("CAST(EXTRACT(EPOCH FROM CAST(? AS TIMESTAMPTZ) - started_at) AS BIGINT)", now),
+		}).Error
+}
+
+// timeSpent sums the ended work logs of the tasks, or of the tasks of the
+// projects when grouped by tasks.project_id
+func timeSpent(db *gorm.DB, groupBy string, ids []uuid.UUID) (map[uuid.UUID]int64, error) {
+	rows, err := db.Table("work_logs").
+		Select(groupBy+", SUM(work_logs.seconds)").
+		Joins("JOIN tasks ON tasks.id = work_logs.task_id AND tasks.deleted_at IS NULL").
+		Where(groupBy+" IN (?) AND work_logs.ended_at IS NOT NULL", ids).
+		Group(groupBy).
+		Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	spent := make(map[uuid.UUID]int64)
+	for rows.Next() {
+		var id uuid.UUID
+		var seconds int64
+		if err := rows.Scan(&id, &seconds); err != nil {
+			return nil, err
+		}
+		spent[id] = seconds
+	}
+	return spent, rows.Err()
+}
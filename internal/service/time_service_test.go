@@ -0,0 +1,37 @@
+package service
+
+import (
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStopRunningTimer(t *testing.T) {
+	// Test case 1
+	t.Run("StopRunningTimer: stops the timer without checking the task", func(t *testing.T) {
+		db, f := newFakeDB(t)
+		id, _ := uuid.NewV7()
+		taskID, _ := uuid.NewV7()
+		f.stub(`SELECT * FROM "work_logs"`, []string{"id", "task_id", "user_id", "started_at"},
+			[]driver.Value{id.String(), taskID.String(), adminID.String(), time.Now().Add(-time.Minute)})
+
+		log, err := NewTimeService(db).StopRunningTimer(adminCtx)
+		require.NoError(t, err)
+		require.NotNil(t, log.EndedAt)
+		require.GreaterOrEqual(t, log.Seconds, int64(60))
+
+		require.Equal(t, -1, f.indexOf(`FROM "tasks"`))
+		require.Greater(t, f.indexOf(`UPDATE "work_logs"`), 0)
+	})
+
+	// Test case 2
+	t.Run("StopRunningTimer: no running timer", func(t *testing.T) {
+		db, _ := newFakeDB(t)
+
+		_, err := NewTimeService(db).StopRunningTimer(adminCtx)
+		require.ErrorIs(t, err, ErrNoRunningTimer)
+	})
+}
@@ -0,0 +1,311 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"task-manager/internal/auth"
+	"task-manager/internal/model"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+)
+
+var (
+	ErrRefreshTokenInvalid = errors.New("refresh token is invalid")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected")
+	ErrTokenAlreadyUsed    = errors.New("token was already used")
+
+	ErrPersonalAccessTokenInvalid    = errors.New("personal access token is invalid")
+	ErrPersonalAccessTokenRevoked    = errors.New("personal access token has been revoked")
+	ErrPersonalAccessTokenExpired    = errors.New("personal access token has expired")
+	ErrPersonalAccessTokenNotAllowed = errors.New("personal access tokens cannot manage personal access tokens")
+)
+
+type (
+	ITokenService interface {
+		IssueRefreshToken(context.Context, uuid.UUID) (string, error)
+		RotateRefreshToken(context.Context, string) (uuid.UUID, string, error)
+		RevokeRefreshToken(context.Context, uuid.UUID, string) error
+		RevokeAccessToken(context.Context, string, time.Time) error
+		IsAccessTokenRevoked(context.Context, string) (bool, error)
+		ConsumeToken(context.Context, string, time.Time) error
+		CreatePersonalAccessToken(context.Context, *model.PersonalAccessToken) (string, error)
+		GetPersonalAccessTokens(context.Context) ([]model.PersonalAccessToken, error)
+		RevokePersonalAccessToken(context.Context, uuid.UUID) error
+		AuthenticatePersonalAccessToken(context.Context, string) (*auth.Principal, error)
+	}
+
+	TokenService struct {
+		DB *gorm.DB
+	}
+)
+
+func NewTokenService(db *gorm.DB) ITokenService {
+	return &TokenService{DB: db}
+}
+
+// IssueRefreshToken starts a new token family for the user and returns
+// its first refresh token
+func (s *TokenService) IssueRefreshToken(ctx context.Context, userID uuid.UUID) (string, error) {
+	familyID, _ := uuid.NewV7()
+	token, _, err := s.createRefreshToken(s.DB, userID, familyID)
+	return token, err
+}
+
+// RotateRefreshToken exchanges a refresh token for a new one of the same
+// family and returns the owning user. Presenting a token that was already
+// rotated or revoked revokes the whole family.
+func (s *TokenService) RotateRefreshToken(ctx context.Context, token string) (uuid.UUID, string, error) {
+	var userID uuid.UUID
+	var newToken string
+	reused := false
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var current model.RefreshToken
+		if err := tx.First(&current, "token_hash = ?", auth.HashToken(token)).Error; err != nil {
+			if gorm.IsRecordNotFoundError(err) {
+				return ErrRefreshTokenInvalid
+			}
+			return err
+		}
+
+		if current.RevokedAt != nil {
+			reused = true
+			return nil
+		}
+		if time.Now().After(current.ExpiresAt) {
+			return ErrRefreshTokenInvalid
+		}
+
+		var replacement uuid.UUID
+		var err error
+		newToken, replacement, err = s.createRefreshToken(tx, current.UserID, current.FamilyID)
+		if err != nil {
+			return err
+		}
+
+		// Only one of two concurrent rotations of the same token can win,
+		// the loser is handled as a reuse
+		res := tx.Model(&model.RefreshToken{}).
+			Where("id = ? AND revoked_at IS NULL", current.ID).
+			Updates(map[string]any{"revoked_at": time.Now(), "replaced_by": replacement})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			reused = true
+			return errRollback
+		}
+
+		userID = current.UserID
+		return nil
+	})
+	if err != nil && !errors.Is(err, errRollback) {
+		return uuid.Nil, "", err
+	}
+
+	if reused {
+		if err := s.revokeFamilyOf(token); err != nil {
+			return uuid.Nil, "", err
+		}
+		return uuid.Nil, "", ErrRefreshTokenReused
+	}
+	return userID, newToken, nil
+}
+
+// RevokeRefreshToken revokes the family of a refresh token owned by the user
+func (s *TokenService) RevokeRefreshToken(ctx context.Context, userID uuid.UUID, token string) error {
+	var current model.RefreshToken
+	err := s.DB.First(&current, "token_hash = ? AND user_id = ?", auth.HashToken(token), userID).Error
+	if err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return ErrRefreshTokenInvalid
+		}
+		return err
+	}
+	return s.revokeFamily(s.DB, current.FamilyID)
+}
+
+// RevokeAccessToken adds the token ID to the revocation list. The entry is
+// only needed until the token expires on its own.
+func (s *TokenService) RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	return s.DB.Save(&model.RevokedToken{JTI: jti, ExpiresAt: expiresAt}).Error
+}
+
+// ConsumeToken adds the ID of a single use token to the revocation list,
+// and returns ErrTokenAlreadyUsed when it was already there. Concurrent
+// calls race on the primary key, only one of them inserts the entry.
+func (s *TokenService) ConsumeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	res := s.DB.Exec(
+		"INSERT INTO revoked_tokens (jti, expires_at, created_at) VALUES (?, ?, ?) ON CONFLICT (jti) DO NOTHING",
+		jti, expiresAt, time.Now(),
+	)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrTokenAlreadyUsed
+	}
+	return nil
+}
+
+func (s *TokenService) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	var count int
+	err := s.DB.Model(&model.RevokedToken{}).Where("jti = ?", jti).Count(&count).Error
+	return count > 0, err
+}
+
+// CreatePersonalAccessToken stores a new personal access token of the
+// caller and returns the token. Only its hash is kept, so it cannot be
+// shown again.
+func (s *TokenService) CreatePersonalAccessToken(ctx context.Context, pat *model.PersonalAccessToken) (string, error) {
+	principal, err := sessionPrincipal(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	token, err := auth.GeneratePersonalAccessToken()
+	if err != nil {
+		return "", err
+	}
+
+	pat.ID, _ = uuid.NewV7()
+	pat.UserID = principal.UserID()
+	pat.TokenHash = auth.HashToken(token)
+	pat.LastUsedAt, pat.RevokedAt = nil, nil
+	if err := s.DB.Create(pat).Error; err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// GetPersonalAccessTokens returns the personal access tokens of the caller
+func (s *TokenService) GetPersonalAccessTokens(ctx context.Context) ([]model.PersonalAccessToken, error) {
+	principal, err := sessionPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pats []model.PersonalAccessToken
+	err = s.DB.Where("user_id = ?", principal.UserID()).Order("created_at").Find(&pats).Error
+	return pats, err
+}
+
+// RevokePersonalAccessToken revokes a personal access token of the caller
+func (s *TokenService) RevokePersonalAccessToken(ctx context.Context, id uuid.UUID) error {
+	principal, err := sessionPrincipal(ctx)
+	if err != nil {
+		return err
+	}
+
+	res := s.DB.Model(&model.PersonalAccessToken{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, principal.UserID()).
+		Update("revoked_at", time.Now())
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// AuthenticatePersonalAccessToken returns the principal of a personal
+// access token. The roles are those the user holds now, the scopes of the
+// token can only narrow them down.
+func (s *TokenService) AuthenticatePersonalAccessToken(ctx context.Context, token string) (*auth.Principal, error) {
+	var pat model.PersonalAccessToken
+	if err := s.DB.First(&pat, "token_hash = ?", auth.HashToken(token)).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, ErrPersonalAccessTokenInvalid
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if pat.RevokedAt != nil {
+		return nil, ErrPersonalAccessTokenRevoked
+	}
+	if pat.ExpiresAt != nil && now.After(*pat.ExpiresAt) {
+		return nil, ErrPersonalAccessTokenExpired
+	}
+
+	var user model.User
+	if err := s.DB.First(&user, "id = ?", pat.UserID).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, ErrPersonalAccessTokenInvalid
+		}
+		return nil, err
+	}
+
+	// Recording the last use is best effort, it must not fail the request
+	s.DB.Model(&pat).UpdateColumn("last_used_at", now)
+
+	principal := &auth.Principal{
+		Subject:   user.ID.String(),
+		Username:  user.Username,
+		Roles:     []string{user.Role},
+		TokenID:   pat.ID.String(),
+		TokenType: auth.TokenTypePersonal,
+		Scopes:    pat.Scopes,
+		MFA:       user.TOTPEnabled,
+	}
+	if pat.ExpiresAt != nil {
+		principal.ExpiresAt = *pat.ExpiresAt
+	}
+	return principal, nil
+}
+
+/*
+	Supporting functions
+*/
+
+// sessionPrincipal returns the principal of ctx, which must have logged in
+// rather than presented a personal access token
+func sessionPrincipal(ctx context.Context) (*auth.Principal, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+	if principal.TokenType == auth.TokenTypePersonal {
+		return nil, ErrPersonalAccessTokenNotAllowed
+	}
+	return principal, nil
+}
+
+// errRollback aborts a transaction without reporting a failure
+var errRollback = errors.New("rollback")
+
+func (s *TokenService) createRefreshToken(db *gorm.DB, userID, familyID uuid.UUID) (string, uuid.UUID, error) {
+	token, err := auth.GenerateOpaqueToken(32)
+	if err != nil {
+		return "", uuid.Nil, err
+	}
+
+	record := model.RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: auth.HashToken(token),
+		ExpiresAt: time.Now().Add(auth.RefreshTokenTTL),
+	}
+	record.ID, _ = uuid.NewV7()
+	if err := db.Create(&record).Error; err != nil {
+		return "", uuid.Nil, err
+	}
+	return token, record.ID, nil
+}
+
+func (s *TokenService) revokeFamilyOf(token string) error {
+	var current model.RefreshToken
+	if err := s.DB.First(&current, "token_hash = ?", auth.HashToken(token)).Error; err != nil {
+		return err
+	}
+	return s.revokeFamily(s.DB, current.FamilyID)
+}
+
+func (s *TokenService) revokeFamily(db *gorm.DB, familyID uuid.UUID) error {
+	return db.Model(&model.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+}
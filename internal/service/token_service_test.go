@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsumeToken(t *testing.T) {
+	expiresAt := time.Now().Add(5 * time.Minute)
+
+	// Test case 1
+	t.Run("ConsumeToken: first use", func(t *testing.T) {
+		db, f := newFakeDB(t)
+
+		err := NewTokenService(db).ConsumeToken(context.Background(), "jti-1", expiresAt)
+		require.NoError(t, err)
+		require.Equal(t, 0, f.indexOf("ON CONFLICT (jti) DO NOTHING"))
+	})
+
+	// Test case 2
+	t.Run("ConsumeToken: already used", func(t *testing.T) {
+		db, f := newFakeDB(t)
+		f.affect(`INSERT INTO revoked_tokens`, 0)
+
+		err := NewTokenService(db).ConsumeToken(context.Background(), "jti-1", expiresAt)
+		require.ErrorIs(t, err, ErrTokenAlreadyUsed)
+	})
+}
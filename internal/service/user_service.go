@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"task-manager/internal/auth"
+	"task-manager/internal/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+)
+
+var (
+	ErrUserAlreadyExists  = errors.New("user already exists")
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	ErrEmailRequired      = errors.New("the identity provider did not share an email address")
+)
+
+// dummyPasswordHash is compared against when the user does not exist, so
+// that a failed login takes the same time whether or not the user exists
+const dummyPasswordHash = "$2a$10$0gvgmFPgmBNrgqP6PhlLjevCoS9cJs8zxThCluoYAROhugK4W0Mba"
+
+type (
+	IUserService interface {
+		CreateUser(context.Context, *model.User, string) error
+		GetUserByID(context.Context, uuid.UUID) (*model.User, error)
+		GetUserByUsername(context.Context, string) (*model.User, error)
+		Authenticate(context.Context, string, string) (*model.User, error)
+		UpdateUserRole(context.Context, uuid.UUID, string) error
+		ProvisionExternalUser(context.Context, *model.ExternalProfile) (*model.User, error)
+	}
+
+	UserService struct {
+		DB *gorm.DB
+	}
+)
+
+func NewUserService(db *gorm.DB) IUserService {
+	return &UserService{DB: db}
+}
+
+// CreateUser hashes the password and stores the user. The first user to
+// register becomes admin, everybody else starts as member. Usernames and
+// emails are stored in lower case, and are unique regardless of the case.
+func (s *UserService) CreateUser(ctx context.Context, user *model.User, password string) error {
+	user.Username = strings.ToLower(user.Username)
+	user.Email = strings.ToLower(user.Email)
+
+	var count int
+	err := s.DB.Model(&model.User{}).
+		Where("LOWER(username) = ? OR LOWER(email) = ?", user.Username, user.Email).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrUserAlreadyExists
+	}
+
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	user.PasswordHash = hash
+
+	return createUser(s.DB, user)
+}
+
+func (s *UserService) GetUserByID(ctx context.Context, id uuid.UUID) (*model.User, error) {
+	var user model.User
+	err := s.DB.First(&user, "id = ?", id).Error
+	return &user, err
+}
+
+func (s *UserService) GetUserByUsername(ctx context.Context, username string) (*model.User, error) {
+	var user model.User
+	err := s.DB.First(&user, "LOWER(username) = ?", strings.ToLower(username)).Error
+	return &user, err
+}
+
+// Authenticate returns the user when the password matches, and
+// ErrInvalidCredentials when the user is unknown or the password is wrong
+func (s *UserService) Authenticate(ctx context.Context, username, password string) (*model.User, error) {
+	user, err := s.GetUserByUsername(ctx, username)
+	if err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			auth.CheckPassword(dummyPasswordHash, password)
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	if !auth.CheckPassword(user.PasswordHash, password) {
+		return nil, ErrInvalidCredentials
+	}
+	return user, nil
+}
+
+// ProvisionExternalUser returns the local user of an identity provider
+// account, and creates it on first login. An account is linked to an
+// existing user with the same email only when both the provider and the
+// user verified it, anyone can register a password with an email they do
+// not own.
+func (s *UserService) ProvisionExternalUser(ctx context.Context, profile *model.ExternalProfile) (*model.User, error) {
+	var user model.User
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var identity model.UserIdentity
+		err := tx.First(&identity, "issuer = ? AND subject = ?", profile.Issuer, profile.Subject).Error
+		if err == nil {
+			return tx.First(&user, "id = ?", identity.UserID).Error
+		}
+		if !gorm.IsRecordNotFoundError(err) {
+			return err
+		}
+
+		if profile.Email == "" {
+			return ErrEmailRequired
+		}
+		err = tx.First(&user, "LOWER(email) = ?", strings.ToLower(profile.Email)).Error
+		switch {
+		case err == nil && !(profile.EmailVerified && user.EmailVerified):
+			return ErrUserAlreadyExists
+		case gorm.IsRecordNotFoundError(err):
+			user = model.User{Email: strings.ToLower(profile.Email), EmailVerified: profile.EmailVerified}
+			if user.Username, err = availableUsername(tx, profile); err != nil {
+				return err
+			}
+			if err := createUser(tx, &user); err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		}
+
+		identity = model.UserIdentity{Issuer: profile.Issuer, Subject: profile.Subject, UserID: user.ID}
+		return tx.Create(&identity).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (s *UserService) UpdateUserRole(ctx context.Context, id uuid.UUID, role string) error {
+	res := s.DB.Model(&model.User{}).Where("id = ?", id).Update("role", role)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+/*
+	Supporting functions
+*/
+
+// createUser stores the user. The first user becomes admin, everybody
+// else starts as member.
+func createUser(db *gorm.DB, user *model.User) error {
+	var count int
+	if err := db.Model(&model.User{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		user.Role = auth.RoleAdmin
+	} else if user.Role == "" {
+		user.Role = auth.RoleMember
+	}
+
+	if user.ID == uuid.Nil {
+		user.ID, _ = uuid.NewV7()
+	}
+	return db.Create(user).Error
+}
+
+// availableUsername derives a lower case username from the profile that
+// follows the rules of the registration, adding a number when it is
+// already taken
+func availableUsername(db *gorm.DB, profile *model.ExternalProfile) (string, error) {
+	base := profile.Username
+	if base == "" {
+		base = strings.SplitN(profile.Email, "@", 2)[0]
+	}
+	base = strings.Map(func(r rune) rune {
+		if r > 127 || !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9') {
+			return -1
+		}
+		return r
+	}, strings.ToLower(base))
+	if len(base) > 40 {
+		base = base[:40]
+	}
+	for len(base) < 3 {
+		base += "0"
+	}
+
+	username := base
+	for i := 1; i <= 100; i++ {
+		var count int
+		if err := db.Model(&model.User{}).Where("LOWER(username) = ?", username).Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return username, nil
+		}
+		username = fmt.Sprintf("%s%d", base, i)
+	}
+	return "", ErrUserAlreadyExists
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"database/sql/driver"
+	"task-manager/internal/model"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+var userColumns = []string{"id", "username", "email", "password_hash", "role", "email_verified"}
+
+func TestCreateUser(t *testing.T) {
+	// Test case 1
+	t.Run("CreateUser: stores the username and email in lower case", func(t *testing.T) {
+		db, f := newFakeDB(t)
+		f.stub(`SELECT count(*) FROM "users"`, []string{"count"}, []driver.Value{int64(0)})
+
+		user := &model.User{Username: "Alice", Email: "Alice@Example.com"}
+		err := NewUserService(db).CreateUser(context.Background(), user, "password1")
+		require.NoError(t, err)
+		require.Equal(t, "alice", user.Username)
+		require.Equal(t, "alice@example.com", user.Email)
+		require.Contains(t, f.executed()[0], "LOWER(username) = $1 OR LOWER(email) = $2")
+	})
+
+	// Test case 2
+	t.Run("CreateUser: username taken in another case", func(t *testing.T) {
+		db, f := newFakeDB(t)
+		f.stub(`LOWER(username)`, []string{"count"}, []driver.Value{int64(1)})
+
+		err := NewUserService(db).CreateUser(context.Background(), &model.User{Username: "Bob", Email: "bob@example.com"}, "password1")
+		require.ErrorIs(t, err, ErrUserAlreadyExists)
+	})
+}
+
+func TestProvisionExternalUser(t *testing.T) {
+	profile := &model.ExternalProfile{Issuer: "https://idp", Subject: "idp-42", Email: "Alice@example.com", EmailVerified: true}
+
+	// Test case 1
+	t.Run("ProvisionExternalUser: registered email is not linked", func(t *testing.T) {
+		db, f := newFakeDB(t)
+		id, _ := uuid.NewV7()
+		f.stub(`LOWER(email)`, userColumns, []driver.Value{id.String(), "alice", "alice@example.com", "hash", "member", false})
+
+		_, err := NewUserService(db).ProvisionExternalUser(context.Background(), profile)
+		require.ErrorIs(t, err, ErrUserAlreadyExists)
+		require.Equal(t, -1, f.indexOf(`INSERT INTO "user_identities"`))
+	})
+
+	// Test case 2
+	t.Run("ProvisionExternalUser: verified email is linked", func(t *testing.T) {
+		db, f := newFakeDB(t)
+		id, _ := uuid.NewV7()
+		f.stub(`LOWER(email)`, userColumns, []driver.Value{id.String(), "alice", "alice@example.com", "", "member", true})
+
+		user, err := NewUserService(db).ProvisionExternalUser(context.Background(), profile)
+		require.NoError(t, err)
+		require.Equal(t, id, user.ID)
+		require.Greater(t, f.indexOf(`INSERT INTO "user_identities"`), 0)
+	})
+
+	// Test case 3
+	t.Run("ProvisionExternalUser: unverified profile is not linked", func(t *testing.T) {
+		db, f := newFakeDB(t)
+		id, _ := uuid.NewV7()
+		f.stub(`LOWER(email)`, userColumns, []driver.Value{id.String(), "alice", "alice@example.com", "", "member", true})
+
+		unverified := *profile
+		unverified.EmailVerified = false
+		_, err := NewUserService(db).ProvisionExternalUser(context.Background(), &unverified)
+		require.ErrorIs(t, err, ErrUserAlreadyExists)
+	})
+
+	// Test case 4
+	t.Run("ProvisionExternalUser: new user in lower case", func(t *testing.T) {
+		db, f := newFakeDB(t)
+		f.stub(`SELECT count(*) FROM "users"`, []string{"count"}, []driver.Value{int64(0)})
+
+		withName := *profile
+		withName.Username = "Alice.Smith"
+		user, err := NewUserService(db).ProvisionExternalUser(context.Background(), &withName)
+		require.NoError(t, err)
+		require.Equal(t, "alicesmith", user.Username)
+		require.Equal(t, "alice@example.com", user.Email)
+		require.True(t, user.EmailVerified)
+	})
+}
@@ -0,0 +1,274 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"task-manager/internal/auth"
+	"task-manager/internal/model"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+)
+
+var (
+	ErrWorkspaceForbidden = errors.New("insufficient workspace role")
+	ErrLastOwner          = errors.New("a workspace must keep at least one owner")
+	ErrInvitationInvalid  = errors.New("invitation is invalid or expired")
+	ErrInvitationMismatch = errors.New("invitation was sent to another email address")
+)
+
+type (
+	IWorkspaceService interface {
+		CreateWorkspace(context.Context, *model.Workspace) error
+		GetWorkspaces(context.Context) ([]model.Workspace, error)
+		GetWorkspaceByID(context.Context, uuid.UUID) (*model.Workspace, error)
+		GetMembership(context.Context, uuid.UUID, uuid.UUID) (*model.WorkspaceMember, error)
+		GetMembers(context.Context, uuid.UUID) ([]model.WorkspaceMember, error)
+		AddMember(context.Context, uuid.UUID, uuid.UUID, string) error
+		UpdateMemberRole(context.Context, uuid.UUID, uuid.UUID, string) error
+		RemoveMember(context.Context, uuid.UUID, uuid.UUID) error
+		CreateInvitation(context.Context, uuid.UUID, string, string) (string, time.Time, error)
+		AcceptInvitation(context.Context, string) (*model.WorkspaceMember, error)
+	}
+
+	WorkspaceService struct {
+		DB *gorm.DB
+	}
+)
+
+func NewWorkspaceService(db *gorm.DB) IWorkspaceService {
+	return &WorkspaceService{DB: db}
+}
+
+// CreateWorkspace stores the workspace and makes the caller its owner
+func (s *WorkspaceService) CreateWorkspace(ctx context.Context, workspace *model.Workspace) error {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	workspace.ID, _ = uuid.NewV7()
+	workspace.CreatedBy = principal.UserID()
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(workspace).Error; err != nil {
+			return err
+		}
+		owner := model.WorkspaceMember{
+			WorkspaceID: workspace.ID,
+			UserID:      principal.UserID(),
+			Role:        model.WorkspaceRoleOwner,
+		}
+		return tx.Create(&owner).Error
+	})
+}
+
+// GetWorkspaces returns the workspaces the caller is a member of
+func (s *WorkspaceService) GetWorkspaces(ctx context.Context) ([]model.Workspace, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	var workspaces []model.Workspace
+	err := s.DB.
+		Where("id IN (SELECT workspace_id FROM workspace_members WHERE user_id = ?)", principal.UserID()).
+		Find(&workspaces).Error
+	return workspaces, err
+}
+
+// GetWorkspaceByID returns the workspace if the caller is a member of it
+func (s *WorkspaceService) GetWorkspaceByID(ctx context.Context, id uuid.UUID) (*model.Workspace, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	var workspace model.Workspace
+	err := s.DB.
+		Where("id IN (SELECT workspace_id FROM workspace_members WHERE user_id = ?)", principal.UserID()).
+		First(&workspace, "id = ?", id).Error
+	return &workspace, err
+}
+
+func (s *WorkspaceService) GetMembership(ctx context.Context, workspaceID, userID uuid.UUID) (*model.WorkspaceMember, error) {
+	var member model.WorkspaceMember
+	err := s.DB.First(&member, "workspace_id = ? AND user_id = ?", workspaceID, userID).Error
+	return &member, err
+}
+
+func (s *WorkspaceService) GetMembers(ctx context.Context, workspaceID uuid.UUID) ([]model.WorkspaceMember, error) {
+	if _, err := s.callerMembership(ctx, workspaceID); err != nil {
+		return nil, err
+	}
+
+	var members []model.WorkspaceMember
+	err := s.DB.Where("workspace_id = ?", workspaceID).Order("created_at").Find(&members).Error
+	return members, err
+}
+
+// AddMember adds the user to the workspace. Admins can add admins and
+// members, only owners can add owners.
+func (s *WorkspaceService) AddMember(ctx context.Context, workspaceID, userID uuid.UUID, role string) error {
+	caller, err := s.callerMembership(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+	if !canAssignRole(caller, role) {
+		return ErrWorkspaceForbidden
+	}
+
+	var count int
+	if err := s.DB.Model(&model.User{}).Where("id = ?", userID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	member := model.WorkspaceMember{WorkspaceID: workspaceID, UserID: userID, Role: role}
+	return s.DB.Save(&member).Error
+}
+
+func (s *WorkspaceService) UpdateMemberRole(ctx context.Context, workspaceID, userID uuid.UUID, role string) error {
+	caller, err := s.callerMembership(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		var member model.WorkspaceMember
+		if err := tx.First(&member, "workspace_id = ? AND user_id = ?", workspaceID, userID).Error; err != nil {
+			return err
+		}
+		if !canAssignRole(caller, role) || !canAssignRole(caller, member.Role) {
+			return ErrWorkspaceForbidden
+		}
+		if member.Role == model.WorkspaceRoleOwner && role != model.WorkspaceRoleOwner {
+			if err := ensureAnotherOwner(tx, workspaceID, userID); err != nil {
+				return err
+			}
+		}
+		return tx.Model(&member).Update("role", role).Error
+	})
+}
+
+// RemoveMember removes the user from the workspace. Members can always
+// leave, removing somebody else takes the same role as adding them.
+func (s *WorkspaceService) RemoveMember(ctx context.Context, workspaceID, userID uuid.UUID) error {
+	caller, err := s.callerMembership(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		var member model.WorkspaceMember
+		if err := tx.First(&member, "workspace_id = ? AND user_id = ?", workspaceID, userID).Error; err != nil {
+			return err
+		}
+		if caller.UserID != userID && !canAssignRole(caller, member.Role) {
+			return ErrWorkspaceForbidden
+		}
+		if member.Role == model.WorkspaceRoleOwner {
+			if err := ensureAnotherOwner(tx, workspaceID, userID); err != nil {
+				return err
+			}
+		}
+		return tx.Delete(&member).Error
+	})
+}
+
+// CreateInvitation returns a signed invitation to join the workspace
+func (s *WorkspaceService) CreateInvitation(ctx context.Context, workspaceID uuid.UUID, email, role string) (string, time.Time, error) {
+	caller, err := s.callerMembership(ctx, workspaceID)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if !canAssignRole(caller, role) {
+		return "", time.Time{}, ErrWorkspaceForbidden
+	}
+
+	return auth.GenerateInvitationToken(workspaceID, strings.ToLower(email), role, caller.UserID)
+}
+
+// AcceptInvitation adds the caller to the workspace of the invitation. The
+// invitation must have been sent to the email address of the caller.
+func (s *WorkspaceService) AcceptInvitation(ctx context.Context, token string) (*model.WorkspaceMember, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	claims, err := auth.ParseInvitationToken(token)
+	if err != nil {
+		return nil, ErrInvitationInvalid
+	}
+	workspaceID, err := uuid.FromString(claims.WorkspaceID)
+	if err != nil {
+		return nil, ErrInvitationInvalid
+	}
+
+	var user model.User
+	if err := s.DB.First(&user, "id = ?", principal.UserID()).Error; err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(user.Email, claims.Email) {
+		return nil, ErrInvitationMismatch
+	}
+
+	// Accepting twice keeps the current role
+	member, err := s.GetMembership(ctx, workspaceID, user.ID)
+	if err == nil {
+		return member, nil
+	}
+	if !gorm.IsRecordNotFoundError(err) {
+		return nil, err
+	}
+
+	member = &model.WorkspaceMember{WorkspaceID: workspaceID, UserID: user.ID, Role: claims.Role}
+	if err := s.DB.Create(member).Error; err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+/*
+	Supporting functions
+*/
+
+// callerMembership returns the membership of the caller, and a not found
+// error when the caller is not a member so that the workspace stays hidden
+func (s *WorkspaceService) callerMembership(ctx context.Context, workspaceID uuid.UUID) (*model.WorkspaceMember, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+	return s.GetMembership(ctx, workspaceID, principal.UserID())
+}
+
+// canAssignRole reports whether the caller may grant, or take away, the role
+func canAssignRole(caller *model.WorkspaceMember, role string) bool {
+	switch caller.Role {
+	case model.WorkspaceRoleOwner:
+		return true
+	case model.WorkspaceRoleAdmin:
+		return role != model.WorkspaceRoleOwner
+	default:
+		return false
+	}
+}
+
+func ensureAnotherOwner(tx *gorm.DB, workspaceID, userID uuid.UUID) error {
+	var count int
+	err := tx.Model(&model.WorkspaceMember{}).
+		Where("workspace_id = ? AND role = ? AND user_id <> ?", workspaceID, model.WorkspaceRoleOwner, userID).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrLastOwner
+	}
+	return nil
+}
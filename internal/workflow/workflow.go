@@ -0,0 +1,248 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"task-manager/internal/model"
+)
+
+// DefaultTransitions moves tasks from pending to in-progress to completed.
+// Tasks in progress can be put back to pending, and completed tasks are
+// reopened to pending.
+const DefaultTransitions = "pending>in-progress, in-progress>completed, in-progress>pending, completed>pending"
+
+// defaultStatuses are the statuses of the tasks outside of a project
+var defaultStatuses = []model.WorkflowStatus{
+	{Key: model.TaskStatusPending, Name: "Pending", Category: model.CategoryTodo},
+	{Key: model.TaskStatusInProgress, Name: "In progress", Category: model.CategoryActive},
+	{Key: model.TaskStatusCompleted, Name: "Completed", Category: model.CategoryDone},
+}
+
+var (
+	ErrInvalidTransitions = errors.New("invalid task transitions")
+	ErrInvalidWorkflow    = errors.New("invalid workflow")
+	ErrIllegalTransition  = errors.New("illegal status transition")
+	ErrUnknownStatus      = errors.New("unknown status")
+	ErrNotDone            = errors.New("task is not completed")
+)
+
+// TransitionError is returned for a transition the state machine refuses,
+// From equals To when the task already has the status
+type TransitionError struct {
+	From string
+	To   string
+
+	// Category is the category of the target status
+	Category string
+}
+
+func (e *TransitionError) Error() string {
+	if e.From == e.To {
+		return fmt.Sprintf("task already %s", e.From)
+	}
+	return fmt.Sprintf("cannot move a task from %s to %s", e.From, e.To)
+}
+
+func (e *TransitionError) Unwrap() error { return ErrIllegalTransition }
+
+// StateMachine holds the statuses of tasks and the transitions allowed
+// between them
+type StateMachine struct {
+	// ReopenTo is the status done tasks are reopened to
+	ReopenTo string
+
+	workflow    model.Workflow
+	categories  map[string]string
+	transitions map[string]map[string]bool
+}
+
+// Default returns the state machine of DefaultTransitions
+func Default() *StateMachine {
+	sm, _ := Parse(DefaultTransitions, model.TaskStatusPending)
+	return sm
+}
+
+// FromEnv reads the transitions from TASK_TRANSITIONS and the reopen
+// status from TASK_REOPEN_STATUS, both falling back to the defaults
+func FromEnv() (*StateMachine, error) {
+	spec := os.Getenv("TASK_TRANSITIONS")
+	if spec == "" {
+		spec = DefaultTransitions
+	}
+	reopenTo := os.Getenv("TASK_REOPEN_STATUS")
+	if reopenTo == "" {
+		reopenTo = model.TaskStatusPending
+	}
+	return Parse(spec, reopenTo)
+}
+
+// Parse reads comma separated "from>to" transitions between the default
+// statuses. Completed tasks must be able to move to the reopen status.
+func Parse(spec, reopenTo string) (*StateMachine, error) {
+	w := model.Workflow{Statuses: defaultStatuses, ReopenTo: reopenTo}
+	for _, entry := range strings.Split(spec, ",") {
+		from, to, ok := strings.Cut(strings.TrimSpace(entry), ">")
+		from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+		if !ok || from == "" || to == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidTransitions, entry)
+		}
+		w.Transitions = append(w.Transitions, model.WorkflowTransition{From: from, To: to})
+	}
+
+	sm, err := New(w)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTransitions, err)
+	}
+	if !sm.Can(model.TaskStatusCompleted, reopenTo) {
+		return nil, fmt.Errorf("%w: completed tasks cannot be reopened to %q", ErrInvalidTransitions, reopenTo)
+	}
+	return sm, nil
+}
+
+// New returns the state machine of the workflow. The workflow needs at
+// least a todo and a done status, and its transitions and reopen status
+// must refer to its statuses.
+func New(w model.Workflow) (*StateMachine, error) {
+	sm := &StateMachine{workflow: w, categories: make(map[string]string)}
+
+	var hasTodo, hasDone bool
+	for _, status := range w.Statuses {
+		if status.Key == "" {
+			return nil, fmt.Errorf("%w: status without a key", ErrInvalidWorkflow)
+		}
+		if _, ok := sm.categories[status.Key]; ok {
+			return nil, fmt.Errorf("%w: duplicate status %q", ErrInvalidWorkflow, status.Key)
+		}
+		switch status.Category {
+		case model.CategoryTodo:
+			hasTodo = true
+		case model.CategoryDone:
+			hasDone = true
+		case model.CategoryActive:
+		default:
+			return nil, fmt.Errorf("%w: unknown category %q", ErrInvalidWorkflow, status.Category)
+		}
+		sm.categories[status.Key] = status.Category
+	}
+	if !hasTodo || !hasDone {
+		return nil, fmt.Errorf("%w: a todo and a done status are required", ErrInvalidWorkflow)
+	}
+
+	if len(w.Transitions) > 0 {
+		sm.transitions = make(map[string]map[string]bool)
+	}
+	for _, t := range w.Transitions {
+		if !sm.Known(t.From) || !sm.Known(t.To) || t.From == t.To {
+			return nil, fmt.Errorf("%w: transition %s>%s", ErrInvalidWorkflow, t.From, t.To)
+		}
+		if sm.transitions[t.From] == nil {
+			sm.transitions[t.From] = make(map[string]bool)
+		}
+		sm.transitions[t.From][t.To] = true
+	}
+
+	if w.ReopenTo == "" {
+		w.ReopenTo = sm.first(model.CategoryTodo, "")
+	}
+	if category, ok := sm.categories[w.ReopenTo]; !ok || category == model.CategoryDone {
+		return nil, fmt.Errorf("%w: tasks cannot be reopened to %q", ErrInvalidWorkflow, w.ReopenTo)
+	}
+
+	sm.ReopenTo = w.ReopenTo
+	sm.workflow.ReopenTo = w.ReopenTo
+	return sm, nil
+}
+
+// Workflow returns the workflow of the state machine
+func (sm *StateMachine) Workflow() model.Workflow {
+	return sm.workflow
+}
+
+// Statuses returns the keys of the statuses, in their order
+func (sm *StateMachine) Statuses() []string {
+	keys := make([]string, len(sm.workflow.Statuses))
+	for i, status := range sm.workflow.Statuses {
+		keys[i] = status.Key
+	}
+	return keys
+}
+
+// Known tells whether the status is part of the workflow
+func (sm *StateMachine) Known(status string) bool {
+	_, ok := sm.categories[status]
+	return ok
+}
+
+// Category returns the category of the status, empty when it is unknown
+func (sm *StateMachine) Category(status string) string {
+	return sm.categories[status]
+}
+
+// Initial returns the status of new tasks, the first todo status
+func (sm *StateMachine) Initial() string {
+	return sm.first(model.CategoryTodo, "")
+}
+
+// Can tells whether a task can move from a status to another
+func (sm *StateMachine) Can(from, to string) bool {
+	if from == to || !sm.Known(from) || !sm.Known(to) {
+		return false
+	}
+	return sm.transitions == nil || sm.transitions[from][to]
+}
+
+// Validate returns a TransitionError unless the task can move from a
+// status to another
+func (sm *StateMachine) Validate(from, to string) error {
+	if !sm.Known(to) {
+		return fmt.Errorf("%w: %q", ErrUnknownStatus, to)
+	}
+	if !sm.Can(from, to) {
+		return &TransitionError{From: from, To: to, Category: sm.Category(to)}
+	}
+	return nil
+}
+
+// Target returns the status a task moves to by the action. Starting moves
+// to the first active status reachable, completing to the first done one,
+// and reopening a done task to ReopenTo.
+func (sm *StateMachine) Target(action, from string) (string, error) {
+	var category string
+	switch action {
+	case model.TaskActionStart:
+		category = model.CategoryActive
+	case model.TaskActionComplete:
+		category = model.CategoryDone
+	case model.TaskActionReopen:
+		if sm.Category(from) != model.CategoryDone {
+			return "", ErrNotDone
+		}
+		return sm.ReopenTo, sm.Validate(from, sm.ReopenTo)
+	default:
+		return "", fmt.Errorf("%w: %q", ErrIllegalTransition, action)
+	}
+
+	if sm.Category(from) == category {
+		return "", &TransitionError{From: from, To: from, Category: category}
+	}
+	if to := sm.first(category, from); to != "" {
+		return to, nil
+	}
+	if to := sm.first(category, ""); to != "" {
+		return "", &TransitionError{From: from, To: to, Category: category}
+	}
+	return "", fmt.Errorf("%w: the workflow has no %s status", ErrIllegalTransition, category)
+}
+
+// first returns the first status of the category, reachable from the
+// status unless it is empty
+func (sm *StateMachine) first(category, from string) string {
+	for _, status := range sm.workflow.Statuses {
+		if status.Category == category && (from == "" || sm.Can(from, status.Key)) {
+			return status.Key
+		}
+	}
+	return ""
+}
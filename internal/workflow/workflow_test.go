@@ -0,0 +1,156 @@
+package workflow
+
+import (
+	"errors"
+	"task-manager/internal/model"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefault(t *testing.T) {
+	sm := Default()
+
+	// Test case 1
+	t.Run("Default: allowed transitions", func(t *testing.T) {
+		require.NoError(t, sm.Validate(model.TaskStatusPending, model.TaskStatusInProgress))
+		require.NoError(t, sm.Validate(model.TaskStatusInProgress, model.TaskStatusCompleted))
+		require.NoError(t, sm.Validate(model.TaskStatusInProgress, model.TaskStatusPending))
+		require.NoError(t, sm.Validate(model.TaskStatusCompleted, model.TaskStatusPending))
+		require.Equal(t, model.TaskStatusPending, sm.ReopenTo)
+	})
+
+	// Test case 2
+	t.Run("Default: illegal transition", func(t *testing.T) {
+		err := sm.Validate(model.TaskStatusPending, model.TaskStatusCompleted)
+		require.ErrorIs(t, err, ErrIllegalTransition)
+		require.EqualError(t, err, "cannot move a task from pending to completed")
+	})
+
+	// Test case 3
+	t.Run("Default: same status", func(t *testing.T) {
+		err := sm.Validate(model.TaskStatusCompleted, model.TaskStatusCompleted)
+
+		var transitionErr *TransitionError
+		require.True(t, errors.As(err, &transitionErr))
+		require.Equal(t, transitionErr.From, transitionErr.To)
+		require.EqualError(t, err, "task already completed")
+	})
+}
+
+func TestParse(t *testing.T) {
+	// Test case 1
+	t.Run("Parse: custom transitions", func(t *testing.T) {
+		sm, err := Parse("pending>completed, completed>in-progress", model.TaskStatusInProgress)
+		require.NoError(t, err)
+		require.True(t, sm.Can(model.TaskStatusPending, model.TaskStatusCompleted))
+		require.False(t, sm.Can(model.TaskStatusPending, model.TaskStatusInProgress))
+		require.Equal(t, model.TaskStatusInProgress, sm.ReopenTo)
+	})
+
+	// Test case 2
+	t.Run("Parse: invalid entries", func(t *testing.T) {
+		for _, spec := range []string{"pending", "pending>done", "pending>pending", ""} {
+			_, err := Parse(spec, model.TaskStatusPending)
+			require.ErrorIs(t, err, ErrInvalidTransitions, spec)
+		}
+	})
+
+	// Test case 3
+	t.Run("Parse: completed tasks cannot be reopened", func(t *testing.T) {
+		_, err := Parse("pending>in-progress, in-progress>completed", model.TaskStatusPending)
+		require.ErrorIs(t, err, ErrInvalidTransitions)
+	})
+}
+
+func TestNew(t *testing.T) {
+	board := model.Workflow{
+		Statuses: []model.WorkflowStatus{
+			{Key: "backlog", Name: "Backlog", Category: model.CategoryTodo},
+			{Key: "doing", Name: "Doing", Category: model.CategoryActive},
+			{Key: "review", Name: "Review", Category: model.CategoryActive},
+			{Key: "blocked", Name: "Blocked", Category: model.CategoryTodo},
+			{Key: "done", Name: "Done", Category: model.CategoryDone},
+		},
+		Transitions: []model.WorkflowTransition{
+			{From: "backlog", To: "doing"},
+			{From: "doing", To: "review"},
+			{From: "doing", To: "blocked"},
+			{From: "blocked", To: "review"},
+			{From: "review", To: "done"},
+			{From: "done", To: "backlog"},
+		},
+	}
+
+	// Test case 1
+	t.Run("New: custom workflow", func(t *testing.T) {
+		sm, err := New(board)
+		require.NoError(t, err)
+		require.Equal(t, "backlog", sm.Initial())
+		require.Equal(t, "backlog", sm.ReopenTo)
+		require.Equal(t, model.CategoryActive, sm.Category("review"))
+		require.Equal(t, []string{"backlog", "doing", "review", "blocked", "done"}, sm.Statuses())
+		require.NoError(t, sm.Validate("doing", "blocked"))
+		require.ErrorIs(t, sm.Validate("backlog", "done"), ErrIllegalTransition)
+		require.ErrorIs(t, sm.Validate("backlog", "archived"), ErrUnknownStatus)
+	})
+
+	// Test case 2
+	t.Run("New: targets of the actions", func(t *testing.T) {
+		sm, err := New(board)
+		require.NoError(t, err)
+
+		to, err := sm.Target(model.TaskActionStart, "backlog")
+		require.NoError(t, err)
+		require.Equal(t, "doing", to)
+
+		// Blocked tasks can only resume to review
+		to, err = sm.Target(model.TaskActionStart, "blocked")
+		require.NoError(t, err)
+		require.Equal(t, "review", to)
+
+		_, err = sm.Target(model.TaskActionStart, "review")
+		require.EqualError(t, err, "task already review")
+
+		to, err = sm.Target(model.TaskActionComplete, "review")
+		require.NoError(t, err)
+		require.Equal(t, "done", to)
+
+		_, err = sm.Target(model.TaskActionComplete, "backlog")
+		require.EqualError(t, err, "cannot move a task from backlog to done")
+
+		_, err = sm.Target(model.TaskActionReopen, "doing")
+		require.ErrorIs(t, err, ErrNotDone)
+
+		to, err = sm.Target(model.TaskActionReopen, "done")
+		require.NoError(t, err)
+		require.Equal(t, "backlog", to)
+	})
+
+	// Test case 3
+	t.Run("New: free transitions", func(t *testing.T) {
+		sm, err := New(model.Workflow{Statuses: board.Statuses[:1:1], ReopenTo: ""})
+		require.ErrorIs(t, err, ErrInvalidWorkflow)
+
+		sm, err = New(model.Workflow{Statuses: []model.WorkflowStatus{board.Statuses[0], board.Statuses[4]}})
+		require.NoError(t, err)
+		require.NoError(t, sm.Validate("done", "backlog"))
+
+		_, err = sm.Target(model.TaskActionStart, "backlog")
+		require.ErrorIs(t, err, ErrIllegalTransition)
+	})
+
+	// Test case 4
+	t.Run("New: invalid workflows", func(t *testing.T) {
+		invalid := []model.Workflow{
+			{Statuses: append([]model.WorkflowStatus{board.Statuses[0]}, board.Statuses...)},
+			{Statuses: []model.WorkflowStatus{{Key: "open", Category: "later"}, board.Statuses[4]}},
+			{Statuses: board.Statuses, Transitions: []model.WorkflowTransition{{From: "backlog", To: "archived"}}},
+			{Statuses: board.Statuses, ReopenTo: "done"},
+		}
+		for _, w := range invalid {
+			_, err := New(w)
+			require.ErrorIs(t, err, ErrInvalidWorkflow)
+		}
+	})
+}
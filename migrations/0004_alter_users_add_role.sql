@@ -0,0 +1,2 @@
+ALTER TABLE users
+    ADD COLUMN role VARCHAR(20) CHECK(role IN ('admin', 'member', 'viewer')) NOT NULL DEFAULT 'member';
@@ -0,0 +1,7 @@
+-- Tasks are identified by UUIDs like the other tables, the existing tasks
+-- get new ids
+ALTER TABLE tasks
+    ALTER COLUMN id DROP DEFAULT,
+    ALTER COLUMN id TYPE UUID USING gen_random_uuid();
+
+DROP SEQUENCE IF EXISTS tasks_id_seq;
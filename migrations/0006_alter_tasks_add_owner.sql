@@ -0,0 +1,13 @@
+ALTER TABLE tasks
+    ADD COLUMN owner_id UUID REFERENCES users(id),
+    ADD COLUMN created_by UUID REFERENCES users(id);
+
+CREATE INDEX idx_tasks_owner_id ON tasks(owner_id);
+
+CREATE TABLE task_grants (
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    granted_by UUID REFERENCES users(id),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (task_id, user_id)
+);
@@ -0,0 +1,5 @@
+-- Tasks without a workspace are the personal tasks of their owner
+ALTER TABLE tasks
+    ADD COLUMN workspace_id UUID REFERENCES workspaces(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_tasks_workspace_id ON tasks(workspace_id);
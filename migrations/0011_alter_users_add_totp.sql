@@ -0,0 +1,8 @@
+ALTER TABLE users
+    ADD COLUMN totp_secret VARCHAR(64),
+    ADD COLUMN totp_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN totp_last_counter BIGINT NOT NULL DEFAULT 0;
+
+-- Users who only log in through an identity provider have no password
+ALTER TABLE users
+    ALTER COLUMN password_hash SET DEFAULT '';
@@ -0,0 +1,7 @@
+ALTER TABLE tasks
+    ADD COLUMN priority VARCHAR(10) NOT NULL DEFAULT 'medium',
+    ADD COLUMN due_at TIMESTAMPTZ,
+    ADD COLUMN overdue_at TIMESTAMPTZ;
+
+CREATE INDEX idx_tasks_due_at ON tasks(due_at);
+CREATE INDEX idx_tasks_overdue_at ON tasks(overdue_at);
@@ -0,0 +1,4 @@
+ALTER TABLE tasks
+    ADD COLUMN parent_id UUID REFERENCES tasks(id);
+
+CREATE INDEX idx_tasks_parent_id ON tasks(parent_id);
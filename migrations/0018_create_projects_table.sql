@@ -0,0 +1,27 @@
+CREATE TABLE projects (
+    id UUID PRIMARY KEY,
+    workspace_id UUID REFERENCES workspaces(id) ON DELETE CASCADE,
+    owner_id UUID NOT NULL REFERENCES users(id),
+    name VARCHAR(100) NOT NULL,
+    workflow TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_projects_workspace_id ON projects(workspace_id);
+
+-- Statuses come from the workflow of the project, their category is kept
+-- on the task for filtering and the overdue check
+ALTER TABLE tasks
+    DROP CONSTRAINT IF EXISTS tasks_status_check,
+    ALTER COLUMN status TYPE VARCHAR(50),
+    ADD COLUMN project_id UUID REFERENCES projects(id),
+    ADD COLUMN status_category VARCHAR(10) NOT NULL DEFAULT 'todo';
+
+UPDATE tasks SET status_category = CASE status
+    WHEN 'in-progress' THEN 'active'
+    WHEN 'completed' THEN 'done'
+    ELSE 'todo'
+END;
+
+CREATE INDEX idx_tasks_project_id ON tasks(project_id);
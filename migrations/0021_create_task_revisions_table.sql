@@ -0,0 +1,10 @@
+CREATE TABLE task_revisions (
+    id UUID PRIMARY KEY,
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    revision INTEGER NOT NULL,
+    actor_id UUID REFERENCES users(id),
+    changes TEXT NOT NULL,
+    snapshot TEXT,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (task_id, revision)
+);
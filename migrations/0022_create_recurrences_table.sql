@@ -0,0 +1,30 @@
+CREATE TABLE recurrences (
+    id UUID PRIMARY KEY,
+    workspace_id UUID REFERENCES workspaces(id) ON DELETE CASCADE,
+    owner_id UUID NOT NULL REFERENCES users(id),
+    project_id UUID REFERENCES projects(id) ON DELETE SET NULL,
+    created_by UUID REFERENCES users(id),
+    title TEXT NOT NULL,
+    description TEXT NOT NULL,
+    priority VARCHAR(10) NOT NULL DEFAULT 'medium',
+    rrule VARCHAR(500) NOT NULL,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    start_at TIMESTAMPTZ NOT NULL,
+    except_dates TEXT,
+    next_at TIMESTAMPTZ,
+    last_at TIMESTAMPTZ,
+    last_task_id UUID,
+    occurrences INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_recurrences_workspace_id ON recurrences(workspace_id);
+CREATE INDEX idx_recurrences_owner_id ON recurrences(owner_id);
+CREATE INDEX idx_recurrences_next_at ON recurrences(next_at);
+
+-- Tasks outlive the recurring task they were created from
+ALTER TABLE tasks
+    ADD COLUMN recurrence_id UUID REFERENCES recurrences(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_tasks_recurrence_id ON tasks(recurrence_id);
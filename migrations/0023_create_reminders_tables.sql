@@ -0,0 +1,34 @@
+CREATE TABLE reminders (
+    id UUID PRIMARY KEY,
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    channel VARCHAR(10) NOT NULL CHECK (channel IN ('inbox', 'email', 'webhook')),
+    remind_at TIMESTAMPTZ,
+    before_minutes INTEGER CHECK (before_minutes >= 0),
+    sent_at TIMESTAMPTZ,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    retry_at TIMESTAMPTZ,
+    last_error TEXT,
+    failed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((remind_at IS NULL) <> (before_minutes IS NULL))
+);
+
+CREATE INDEX idx_reminders_task_id ON reminders(task_id);
+CREATE INDEX idx_reminders_user_id ON reminders(user_id);
+
+-- The scheduler only looks at the reminders still to send
+CREATE INDEX idx_reminders_pending ON reminders(retry_at) WHERE sent_at IS NULL AND failed_at IS NULL;
+
+CREATE TABLE notifications (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    task_id UUID REFERENCES tasks(id) ON DELETE CASCADE,
+    reminder_id UUID REFERENCES reminders(id) ON DELETE SET NULL,
+    subject TEXT NOT NULL,
+    body TEXT NOT NULL,
+    read_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_notifications_user_id ON notifications(user_id, created_at);
@@ -0,0 +1,19 @@
+CREATE TABLE work_logs (
+    id UUID PRIMARY KEY,
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    started_at TIMESTAMPTZ NOT NULL,
+    ended_at TIMESTAMPTZ,
+    seconds BIGINT NOT NULL DEFAULT 0 CHECK (seconds >= 0),
+    note VARCHAR(500),
+    source VARCHAR(10) NOT NULL CHECK (source IN ('timer', 'manual')),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    CHECK (ended_at IS NULL OR ended_at >= started_at)
+);
+
+CREATE INDEX idx_work_logs_task_id ON work_logs(task_id);
+CREATE INDEX idx_work_logs_user_id ON work_logs(user_id, started_at);
+
+-- A user runs one timer at a time
+CREATE UNIQUE INDEX idx_work_logs_running ON work_logs(user_id) WHERE ended_at IS NULL;
@@ -0,0 +1,34 @@
+CREATE TABLE comments (
+    id UUID PRIMARY KEY,
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    parent_id UUID REFERENCES comments(id) ON DELETE CASCADE,
+    author_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    body TEXT NOT NULL,
+    edited_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_comments_task_id ON comments(task_id, created_at);
+CREATE INDEX idx_comments_parent_id ON comments(parent_id);
+
+-- The previous bodies of the edited comments
+CREATE TABLE comment_revisions (
+    id UUID PRIMARY KEY,
+    comment_id UUID NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+    body TEXT NOT NULL,
+    editor_id UUID REFERENCES users(id),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_comment_revisions_comment_id ON comment_revisions(comment_id);
+
+-- The users notified of a comment, so edits only notify the new mentions
+CREATE TABLE comment_mentions (
+    comment_id UUID NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (comment_id, user_id)
+);
+
+ALTER TABLE notifications ADD COLUMN comment_id UUID REFERENCES comments(id) ON DELETE CASCADE;
@@ -0,0 +1,4 @@
+-- Only the users whose email a provider verified are linked to the other
+-- accounts with that email
+ALTER TABLE users
+    ADD COLUMN email_verified BOOLEAN NOT NULL DEFAULT FALSE;
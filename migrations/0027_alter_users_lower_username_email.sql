@@ -0,0 +1,6 @@
+-- Usernames and emails are unique regardless of the case. Users that only
+-- differ by the case must be merged by hand before this runs.
+UPDATE users SET username = LOWER(username), email = LOWER(email);
+
+CREATE UNIQUE INDEX idx_users_username_lower ON users(LOWER(username));
+CREATE UNIQUE INDEX idx_users_email_lower ON users(LOWER(email));